@@ -0,0 +1,80 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// AddressBookEntry is a row of the -export-addresses address book. It holds public data only.
+type AddressBookEntry struct {
+	Chain     string `json:"chain"`
+	Address   string `json:"address"`
+	Vault     string `json:"vault"`
+	Key       string `json:"key"`
+	PublicKey string `json:"pubkey"`
+}
+
+// validateAddressBookFile checks the -export-addresses filename before any phrase is entered. The format follows the
+// extension.
+func validateAddressBookFile(filename string) error {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".json":
+		return nil
+	}
+	return fmt.Errorf("⚠ -export-addresses writes CSV or JSON, so the filename must end with .csv or .json: %s", filename)
+}
+
+// addressBook lists the recovered addresses of a vault with the public key they are derived from.
+func addressBook(vaultID string, addresses []ChainAddress, ecdsaSK, eddsaPK []byte) []AddressBookEntry {
+	ecdsaPK := ecdsaPublicKeyDetails(ecdsaSK).Compressed
+	entries := make([]AddressBookEntry, 0, len(addresses))
+	for _, a := range addresses {
+		pk := ecdsaPK
+		if a.Key == eddsaMasterKey {
+			pk = hex.EncodeToString(eddsaPK)
+		}
+		entries = append(entries, AddressBookEntry{a.Chain, a.Address, vaultID, a.Key, pk})
+	}
+	return entries
+}
+
+// marshalAddressBook encodes the address book as CSV with a header row, or as a JSON array, by the file extension.
+func marshalAddressBook(filename string, entries []AddressBookEntry) ([]byte, error) {
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		return append(data, '\n'), err
+	}
+	b := new(bytes.Buffer)
+	w := csv.NewWriter(b)
+	_ = w.Write([]string{"chain", "address", "vault", "key", "pubkey"})
+	for _, e := range entries {
+		_ = w.Write([]string{e.Chain, e.Address, e.Vault, e.Key, e.PublicKey})
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+// writeAddressBook writes the address book next to the other exports, tagged with the session ID. It never
+// overwrites a file.
+func writeAddressBook(filename string, entries []AddressBookEntry) (string, error) {
+	data, err := marshalAddressBook(filename, entries)
+	if err != nil {
+		return "", err
+	}
+	filename = ui.SessionFilename(filename)
+	if err = writeNewFile(filename, data); err != nil {
+		return "", fmt.Errorf("⚠ failed to write the address book: %w", err)
+	}
+	return filename, nil
+}
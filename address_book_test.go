@@ -0,0 +1,60 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressBook(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	eddsaPK := make([]byte, 32)
+	eddsaPK[0] = 0x42
+	entries := addressBook("vault-1", vaultAddresses(ecdsaSK, eddsaPK), ecdsaSK, eddsaPK)
+	require.Len(t, entries, 15)
+	assert.Equal(t, AddressBookEntry{"Ethereum & EVM chains", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", "vault-1", ecdsaMasterKey,
+		"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"}, entries[0])
+	assert.Equal(t, hex.EncodeToString(eddsaPK), entries[10].PublicKey)
+	assert.Equal(t, eddsaMasterKey, entries[10].Key)
+
+	dir := t.TempDir()
+	csvFile, err := writeAddressBook(filepath.Join(dir, "addresses.csv"), entries)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "addresses-"+ui.SessionID+".csv"), csvFile)
+	f, err := os.Open(csvFile)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 16)
+	assert.Equal(t, []string{"chain", "address", "vault", "key", "pubkey"}, rows[0])
+	assert.Equal(t, "Tron", rows[2][0])
+
+	jsonFile, err := writeAddressBook(filepath.Join(dir, "addresses.json"), entries)
+	require.NoError(t, err)
+	data, err := os.ReadFile(jsonFile)
+	require.NoError(t, err)
+	var decoded []AddressBookEntry
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, entries, decoded)
+	assert.NotContains(t, string(data), strings.Repeat("0", 63)+"1")
+
+	// existing files are never overwritten
+	_, err = writeAddressBook(filepath.Join(dir, "addresses.json"), entries)
+	assert.Error(t, err)
+
+	assert.NoError(t, validateAddressBookFile("book.CSV"))
+	assert.Error(t, validateAddressBookFile("book.txt"))
+}
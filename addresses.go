@@ -0,0 +1,207 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/address"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/cardano"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/stellar"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/wif"
+)
+
+// Vault keys are recovered as the master keys of the vault, and addresses are derived from them directly. Wallets must
+// import the private key itself, not derive a child key from it along an HD path.
+const (
+	ecdsaMasterKey = "ECDSA master key, no derivation"
+	eddsaMasterKey = "EdDSA master key, no derivation"
+)
+
+// defaultCosmosHRP is the bech32 prefix of the Cosmos Hub, whose addresses are shown unless -cosmos-hrp is set.
+const defaultCosmosHRP = "cosmos"
+
+// cosmosHRPs are the bech32 prefixes of the Cosmos SDK chains to show the addresses of. -cosmos-hrp sets them for the run.
+var cosmosHRPs = []string{defaultCosmosHRP}
+
+// parseCosmosHRPs reads the comma separated -cosmos-hrp prefixes, e.g. cosmos,osmo,celestia.
+func parseCosmosHRPs(option string) ([]string, error) {
+	hrps := make([]string, 0)
+	for _, hrp := range strings.Split(option, ",") {
+		hrp = strings.TrimSpace(hrp)
+		if hrp == "" || slices.Contains(hrps, hrp) {
+			continue
+		}
+		if !address.ValidHRP(hrp) {
+			return nil, fmt.Errorf("⚠ invalid -cosmos-hrp prefix `%s`: it is the lowercase start of the chain's addresses before the 1, e.g. osmo", hrp)
+		}
+		hrps = append(hrps, hrp)
+	}
+	if len(hrps) == 0 {
+		return nil, fmt.Errorf("⚠ -cosmos-hrp needs at least one address prefix, e.g. cosmos,osmo,celestia")
+	}
+	return hrps, nil
+}
+
+// ChainAddress is the address of a recovered vault key on a chain.
+type ChainAddress struct {
+	Chain, Address string
+	// Key tells which key and derivation path produced the address
+	Key string
+}
+
+// vaultAddresses derives the addresses of the recovered keys on each supported chain, from their public keys only.
+// eddsaPK is nil for older vaults without an EdDSA key.
+func vaultAddresses(ecdsaSK, eddsaPK []byte) []ChainAddress {
+	details := ecdsaPublicKeyDetails(ecdsaSK)
+	ethAddress, _ := hex.DecodeString(details.Address[2:])
+	compressedPK, _ := hex.DecodeString(details.Compressed)
+	mainnetP2TR, _ := address.BitcoinP2TR(compressedPK, false)
+	testnetP2TR, _ := address.BitcoinP2TR(compressedPK, true)
+	addresses := []ChainAddress{
+		{"Ethereum & EVM chains", details.Address, ecdsaMasterKey},
+		{"Tron", address.Tron(ethAddress), ecdsaMasterKey},
+		{"Bitcoin mainnet (P2WPKH)", address.BitcoinP2WPKH(compressedPK, false), ecdsaMasterKey},
+		{"Bitcoin mainnet (P2TR)", mainnetP2TR, ecdsaMasterKey},
+		{"Bitcoin testnet (P2WPKH)", address.BitcoinP2WPKH(compressedPK, true), ecdsaMasterKey},
+		{"Bitcoin testnet (P2TR)", testnetP2TR, ecdsaMasterKey},
+	}
+	for _, chain := range address.UTXOChains {
+		addresses = append(addresses, ChainAddress{chain.Name, chain.P2PKH(compressedPK), ecdsaMasterKey})
+	}
+	for _, hrp := range cosmosHRPs {
+		addresses = append(addresses, ChainAddress{fmt.Sprintf("Cosmos SDK (%s)", hrp), address.Cosmos(compressedPK, hrp), ecdsaMasterKey})
+	}
+	if eddsaPK != nil {
+		addresses = append(addresses,
+			ChainAddress{"Solana", address.Solana(eddsaPK), eddsaMasterKey},
+			ChainAddress{"XRP Ledger", address.XRPL(eddsaPK), eddsaMasterKey},
+			ChainAddress{"Cardano", cardano.Address(eddsaPK, false), eddsaMasterKey},
+			ChainAddress{"Stellar", stellar.AccountID(eddsaPK), eddsaMasterKey},
+			ChainAddress{"EdDSA public key (TON, TAO, etc.)", hex.EncodeToString(eddsaPK), eddsaMasterKey},
+		)
+	}
+	return addresses
+}
+
+// printTronAddress prints the base58 Tron address of the ECDSA key, which TronLink shows once the key is imported.
+func printTronAddress(out io.Writer, ecdsaSK []byte) {
+	ethAddress, _ := hex.DecodeString(ecdsaPublicKeyDetails(ecdsaSK).Address[2:])
+	fmt.Fprintf(out, "It controls the Tron address: %s%s%s\n", ui.AnsiCodes["bold"], address.Tron(ethAddress), ui.AnsiCodes["reset"])
+}
+
+// printStellarAddress prints the Stellar account ID of the EdDSA key.
+func printStellarAddress(out io.Writer, eddsaPK []byte) {
+	fmt.Fprintf(out, "It controls the Stellar account: %s%s%s\n", ui.AnsiCodes["bold"], stellar.AccountID(eddsaPK), ui.AnsiCodes["reset"])
+}
+
+// printCosmosAddresses prints the addresses of the ECDSA key on the -cosmos-hrp chains, which Keplr shows once the key
+// is imported with "Import existing wallet" and "Use a private key".
+func printCosmosAddresses(out io.Writer, ecdsaSK []byte) {
+	compressedPK, _ := hex.DecodeString(ecdsaPublicKeyDetails(ecdsaSK).Compressed)
+	for _, hrp := range cosmosHRPs {
+		fmt.Fprintf(out, "It controls the Cosmos SDK (%s) address: %s%s%s\n", hrp, ui.AnsiCodes["bold"], address.Cosmos(compressedPK, hrp),
+			ui.AnsiCodes["reset"])
+	}
+}
+
+// printBitcoinAddresses prints the native SegWit and Taproot addresses that the Bitcoin WIFs control, to compare with
+// the addresses the vault actually used.
+func printBitcoinAddresses(out io.Writer, ecdsaSK []byte) {
+	compressedPK, _ := hex.DecodeString(ecdsaPublicKeyDetails(ecdsaSK).Compressed)
+	for _, testnet := range []bool{false, true} {
+		network := "mainnet"
+		if testnet {
+			network = "testnet"
+		}
+		p2tr, _ := address.BitcoinP2TR(compressedPK, testnet)
+		fmt.Fprintf(out, "The %s WIF controls: %s%s%s (native SegWit, P2WPKH) and %s%s%s (Taproot, P2TR)\n", network,
+			ui.AnsiCodes["bold"], address.BitcoinP2WPKH(compressedPK, testnet), ui.AnsiCodes["reset"],
+			ui.AnsiCodes["bold"], p2tr, ui.AnsiCodes["reset"])
+	}
+}
+
+// printUTXOChainWIFs prints the WIFs of the ECDSA key for the selected Bitcoin derivatives, to import with their
+// addresses.
+func printUTXOChainWIFs(out io.Writer, ecdsaSK []byte, chains ChainSelection) {
+	var selected []address.UTXOChain
+	for _, chain := range address.UTXOChains {
+		if chains.ShowsChain(chain.Name) {
+			selected = append(selected, chain)
+		}
+	}
+	if len(selected) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\nHere are your private keys for other Bitcoin-like assets, for their transparent addresses. Keep safe and do not share.\n")
+	for _, chain := range selected {
+		fmt.Fprintf(out, "Recovered %s WIF: %s%s%s\n", chain.Name, ui.AnsiCodes["bold"],
+			wif.ToWIF(ecdsaSK, chain.WIFVersion, true), ui.AnsiCodes["reset"])
+	}
+}
+
+// printVaultAddresses prints the -addresses-only view. It holds no secrets. publicFiles are the address book and
+// watch-only wallets written with -export-addresses and -export-watch-only, if any.
+func printVaultAddresses(out io.Writer, addresses []ChainAddress, publicFiles []string) {
+	fmt.Fprintf(out, "\n%s%s VAULT ADDRESSES %s\n\n", ui.AnsiCodes["invertOn"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	for _, a := range addresses {
+		fmt.Fprintf(out, "%-34s %s%s%s  (%s)\n", a.Chain+":", ui.AnsiCodes["bold"], a.Address, ui.AnsiCodes["reset"], a.Key)
+	}
+	ui.Fprintf(out, "\nAll addresses are of the vault master keys: import the private key itself into wallets, not an HD child key.\n")
+	if len(publicFiles) > 0 {
+		// the written files are a result, so they are listed even with -quiet
+		fmt.Fprintf(out, "No private keys were shown, and only these files without secrets were written to disk: %s.\n", strings.Join(publicFiles, ", "))
+		return
+	}
+	ui.Fprintf(out, "No private keys were shown and nothing was written to disk.\n")
+}
+
+// checkPastedAddress explains whether an address pasted from a wallet or explorer is one of the recovered addresses.
+// Addresses that look different across wallets are often the same address with another checksum, case or encoding.
+func checkPastedAddress(pasted string, addresses []ChainAddress) (verdict string, ok bool) {
+	pasted = strings.TrimSpace(pasted)
+	for _, a := range addresses {
+		switch {
+		case pasted == a.Address:
+			return fmt.Sprintf("✓ It is the recovered %s address, of the %s.", a.Chain, a.Key), true
+		case strings.HasPrefix(a.Address, "bc1") || strings.HasPrefix(a.Address, "tb1"):
+			if strings.EqualFold(pasted, a.Address) {
+				return fmt.Sprintf("✓ It is the recovered %s address in upper case. Bech32 addresses do not depend on case.", a.Chain), true
+			}
+		}
+	}
+
+	ethHex := strings.TrimPrefix(addresses[0].Address, "0x")
+	pastedHex := strings.TrimPrefix(strings.TrimPrefix(pasted, "0x"), "0X")
+	switch {
+	case pastedHex == ethHex:
+		return "✓ It is the recovered Ethereum address, with its EIP-55 checksum.", true
+	case strings.EqualFold(pastedHex, ethHex):
+		if pastedHex == strings.ToLower(pastedHex) || pastedHex == strings.ToUpper(pastedHex) {
+			return fmt.Sprintf("✓ It is the recovered Ethereum address without the EIP-55 checksum, which wallets show in mixed case as %s. "+
+				"Wallets only differ in how they capitalize it.", addresses[0].Address), true
+		}
+		return "⚠ It has the letters of the recovered Ethereum address, but its mixed-case EIP-55 checksum is wrong, " +
+			"so it was altered or mistyped. Do not send funds to it.", false
+	case len(pastedHex) == 42 && strings.EqualFold(pastedHex[:2], "41") && strings.EqualFold(pastedHex[2:], ethHex):
+		return fmt.Sprintf("✓ It is the recovered Tron address in hex form, which Tron wallets show as %s. "+
+			"Tron addresses are the Ethereum address of the same key with a 41 prefix, in base58.", addresses[1].Address), true
+	}
+	if verdict, found := unsupportedChainVerdict(pasted); found {
+		return verdict, false
+	}
+	return "⚠ It does not match any of the recovered addresses.", false
+}
+
+// printAddressCheck prints the result of -check-address.
+func printAddressCheck(out io.Writer, pasted string, addresses []ChainAddress) {
+	verdict, _ := checkPastedAddress(pasted, addresses)
+	fmt.Fprintf(out, "\nChecking address %s\n%s\n", strings.TrimSpace(pasted), ui.Plain(verdict))
+}
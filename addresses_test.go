@@ -0,0 +1,131 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultAddresses(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+
+	addresses := vaultAddresses(ecdsaSK, nil)
+	assert.Equal(t, []ChainAddress{
+		{"Ethereum & EVM chains", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", ecdsaMasterKey},
+		{"Tron", "TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC", ecdsaMasterKey},
+		{"Bitcoin mainnet (P2WPKH)", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", ecdsaMasterKey},
+		{"Bitcoin mainnet (P2TR)", "bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9", ecdsaMasterKey},
+		{"Bitcoin testnet (P2WPKH)", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", ecdsaMasterKey},
+		{"Bitcoin testnet (P2TR)", "tb1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5ssk79hv2", ecdsaMasterKey},
+		{"Zcash transparent (t1)", "t1UYsZVJkLPeMjxEtACvSxfWuNmddpWfxzs", ecdsaMasterKey},
+		{"Horizen transparent (zn)", "znbmBYaXE1eNNkRTX56LpjASXHcMERfcigj", ecdsaMasterKey},
+		{"Komodo", "RKxTdfmtxtfLDKZBgx6SvNkBtNu9jRYnLh", ecdsaMasterKey},
+		{"Cosmos SDK (cosmos)", "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c", ecdsaMasterKey},
+	}, addresses)
+
+	addresses = vaultAddresses(ecdsaSK, make([]byte, 32))
+	assert.Len(t, addresses, 15)
+	assert.Equal(t, ChainAddress{"Solana", "11111111111111111111111111111111", eddsaMasterKey}, addresses[10])
+	assert.Equal(t, "Cardano", addresses[12].Chain)
+	assert.True(t, strings.HasPrefix(addresses[12].Address, "addr1v"), "an enterprise address")
+	assert.Equal(t, ChainAddress{"Stellar", "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF", eddsaMasterKey}, addresses[13])
+
+	out := new(bytes.Buffer)
+	printVaultAddresses(out, addresses, nil)
+	assert.Contains(t, out.String(), "TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC")
+	assert.Equal(t, 10, strings.Count(out.String(), "(ECDSA master key, no derivation)"))
+	assert.Equal(t, 5, strings.Count(out.String(), "(EdDSA master key, no derivation)"))
+	assert.NotContains(t, out.String(), "0000000000000000000000000000000000000000000000000000000000000001")
+}
+
+func TestCheckPastedAddress(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	addresses := vaultAddresses(ecdsaSK, make([]byte, 32))
+
+	tests := []struct {
+		pasted, verdict string
+		ok              bool
+	}{
+		{"0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", "Ethereum & EVM chains address", true},
+		{" 7E5F4552091A69125d5DfCb7b8C2659029395Bdf\n", "with its EIP-55 checksum", true},
+		{"0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", "without the EIP-55 checksum", true},
+		{"0X7E5F4552091A69125D5DFCB7B8C2659029395BDF", "without the EIP-55 checksum", true},
+		{"0x7e5F4552091A69125d5DfCb7b8C2659029395Bdf", "checksum is wrong", false},
+		{"TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC", "Tron address", true},
+		{"417e5f4552091a69125d5dfcb7b8c2659029395bdf", "Tron address in hex form", true},
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "Bitcoin mainnet (P2WPKH) address in upper case", true},
+		{"11111111111111111111111111111111", "Solana address, of the EdDSA master key", true},
+		{"t1UYsZVJkLPeMjxEtACvSxfWuNmddpWfxzs", "Zcash transparent (t1) address", true},
+		{"0x0000000000000000000000000000000000000001", "does not match", false},
+	}
+	for _, test := range tests {
+		verdict, ok := checkPastedAddress(test.pasted, addresses)
+		assert.Equal(t, test.ok, ok, test.pasted)
+		assert.Contains(t, verdict, test.verdict, test.pasted)
+	}
+}
+
+func TestPrintUTXOChainWIFs(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	out := new(bytes.Buffer)
+	printUTXOChainWIFs(out, ecdsaSK, nil)
+	assert.Contains(t, out.String(), "Zcash transparent (t1) WIF: ")
+	assert.Contains(t, out.String(), "Up1YVLk7uuErCHVQyFCtfinZngmdwfyfc47WCQ8oJxgowjVzNeqs")
+}
+
+func TestPrintVaultAddresses_Quiet(t *testing.T) {
+	ui.ConfigureOutput(true, false)
+	defer ui.ConfigureOutput(false, false)
+	out := new(bytes.Buffer)
+	printVaultAddresses(out, nil, []string{"addresses-1234abcd.csv"})
+	assert.Contains(t, out.String(), "addresses-1234abcd.csv", "-quiet hides decoration, not the files written")
+	assert.NotContains(t, out.String(), "HD child key")
+}
+
+func TestPrintTronAddress(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	out := new(bytes.Buffer)
+	printTronAddress(out, ecdsaSK)
+	assert.Contains(t, out.String(), "TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC")
+}
+
+func TestPrintStellarAddress(t *testing.T) {
+	out := new(bytes.Buffer)
+	printStellarAddress(out, make([]byte, 32))
+	assert.Contains(t, out.String(), "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF")
+}
+
+func TestCosmosHRPs(t *testing.T) {
+	hrps, err := parseCosmosHRPs(" osmo, celestia,osmo ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"osmo", "celestia"}, hrps)
+	for _, bad := range []string{"", " , ", "Osmo", "os-mo"} {
+		_, err = parseCosmosHRPs(bad)
+		assert.Error(t, err, bad)
+	}
+
+	previous := cosmosHRPs
+	t.Cleanup(func() { cosmosHRPs = previous })
+	cosmosHRPs = hrps
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	selection, err := parseChains("cosmos")
+	require.NoError(t, err)
+	assert.Equal(t, []ChainAddress{
+		{"Cosmos SDK (osmo)", "osmo1w508d6qejxtdg4y5r3zarvary0c5xw7kjxy2e2", ecdsaMasterKey},
+		{"Cosmos SDK (celestia)", "celestia1w508d6qejxtdg4y5r3zarvary0c5xw7kthx244", ecdsaMasterKey},
+	}, selection.Addresses(vaultAddresses(ecdsaSK, nil)))
+
+	out := new(bytes.Buffer)
+	printCosmosAddresses(out, ecdsaSK)
+	assert.Contains(t, out.String(), "Cosmos SDK (celestia) address: ")
+	assert.Contains(t, out.String(), "osmo1w508d6qejxtdg4y5r3zarvary0c5xw7kjxy2e2")
+}
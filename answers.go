@@ -0,0 +1,138 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Answers pre-answer the prompts of a recovery, so that it runs unattended, e.g. in rehearsal pipelines. A prompt
+	// without an answer fails the run before any work is done, instead of waiting for input.
+	Answers struct {
+		VaultID string `yaml:"vault-id"`
+		// Phrases are the phrases of the backup files, by file path or name. Only for rehearsal vaults.
+		Phrases map[string]string `yaml:"phrases"`
+		// PhrasesFromKeychain reads the phrases from the OS keychain, as stored with "recovery-tool keychain store"
+		PhrasesFromKeychain bool  `yaml:"phrases-from-keychain"`
+		ConfirmRecovery     *bool `yaml:"confirm-recovery"`
+		AcceptPolicy        *bool `yaml:"accept-policy"`
+		ShowSecrets         *bool `yaml:"show-secrets"`
+	}
+
+	// UnattendedRun is what a run will prompt for, to check that Answers answer all of it.
+	UnattendedRun struct {
+		VaultID                          string
+		LockAfter, FourEyes, ConfirmWord bool
+		Policy, Plan, AddressesOnly      bool
+		// Batch recovers the vaults picked on the command line, and writes their keys to files without showing them
+		Batch bool
+	}
+)
+
+// loadAnswers reads an answers file. Unknown keys are refused, so that a misspelt answer is not silently ignored.
+func loadAnswers(file string) (*Answers, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to read the answers file `%s`: %s", file, err)
+	}
+	answers := new(Answers)
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(answers); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("⚠ the answers file `%s` is not valid: %s", file, err)
+	}
+	if len(answers.Phrases) > 0 && answers.PhrasesFromKeychain {
+		return nil, fmt.Errorf("⚠ the answers file `%s` sets both phrases and phrases-from-keychain, keep one", file)
+	}
+	return answers, nil
+}
+
+// withYes answers yes to the confirmations that the answers file leaves open.
+func (a *Answers) withYes() {
+	yes := true
+	for _, answer := range []**bool{&a.ConfirmRecovery, &a.AcceptPolicy, &a.ShowSecrets} {
+		if *answer == nil {
+			*answer = &yes
+		}
+	}
+}
+
+// Check fails if a prompt of the run has no answer, or can only be answered by a person at the terminal.
+func (a *Answers) Check(run UnattendedRun) error {
+	switch {
+	case run.LockAfter:
+		return errors.New("⚠ unattended run: -lock-after asks for a session passphrase at the terminal, so it cannot be used with -yes or -answers")
+	case run.FourEyes:
+		return errors.New("⚠ unattended run: -four-eyes asks both operators for their passphrases at the terminal, so it cannot be used with -yes or -answers")
+	case run.ConfirmWord:
+		return errors.New("⚠ unattended run: -confirm-word asks for a phrase word at the terminal, so it cannot be used with -yes or -answers")
+	case run.Batch && a.VaultID != "":
+		return errors.New("⚠ unattended run: the vaults of a batch are picked with -vault-id or -all-vaults, so remove vault-id from the answers file")
+	case a.VaultID != "" && run.VaultID != "" && a.VaultID != run.VaultID:
+		return fmt.Errorf("⚠ unattended run: -vault-id %s and the vault-id %s of the answers file differ", run.VaultID, a.VaultID)
+	case a.VaultID == "" && run.VaultID == "" && !run.Batch:
+		return unanswered("vault picker", "vault-id", "or pass -vault-id")
+	case len(a.Phrases) == 0 && !a.PhrasesFromKeychain:
+		return unanswered("phrase", "phrases", "or phrases-from-keychain")
+	case run.Plan:
+		return nil
+	case a.ConfirmRecovery == nil:
+		return unanswered("recovery confirmation", "confirm-recovery", "or pass -yes")
+	case run.AddressesOnly:
+		return nil
+	case run.Policy && a.AcceptPolicy == nil:
+		return unanswered("key handling policy", "accept-policy", "or pass -yes")
+	case run.Batch:
+		return nil
+	case a.ShowSecrets == nil:
+		return unanswered("show secrets", "show-secrets", "or pass -yes")
+	}
+	return nil
+}
+
+// ConfirmChoice is the answer to the recovery confirmation. Going back is never answered.
+func (a *Answers) ConfirmChoice() ui.ConfirmChoice {
+	if *a.ConfirmRecovery {
+		return ui.ConfirmRecover
+	}
+	return ui.ConfirmCancel
+}
+
+func unanswered(prompt, key, alternative string) error {
+	return fmt.Errorf("⚠ unattended run: nothing answers the %s prompt. Set `%s` in the -answers file, %s", prompt, key, alternative)
+}
+
+// VaultsDataFiles are the backup files with the phrases of the answers, each checked like an entered phrase.
+func (a *Answers) VaultsDataFiles(files []string, contents map[string][]byte) ([]ui.VaultsDataFile, error) {
+	if a.PhrasesFromKeychain {
+		return keychainPhrases(files, contents)
+	}
+	vaultsDataFiles := make([]ui.VaultsDataFile, 0, len(files))
+	for _, file := range files {
+		mnemonics, ok := a.Phrases[file]
+		if !ok {
+			mnemonics, ok = a.Phrases[filepath.Base(file)]
+		}
+		if !ok {
+			return nil, unanswered(fmt.Sprintf("phrase of `%s`", file), "phrases", "by the file path or name")
+		}
+		vaultsDataFile := ui.VaultsDataFile{File: file, Mnemonics: ui.CleanMnemonicInput(mnemonics), Content: contents[file]}
+		if _, err := checkMnemonics(vaultsDataFile); err != nil {
+			return nil, fmt.Errorf("⚠ the answered phrase of `%s` is wrong: %s", file, strings.TrimPrefix(err.Error(), "⚠ "))
+		}
+		vaultsDataFiles = append(vaultsDataFiles, vaultsDataFile)
+	}
+	return vaultsDataFiles, nil
+}
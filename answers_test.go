@@ -0,0 +1,102 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAnswers(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "answers.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("vault-id: yz5x2a7zhwwt7r0lv4gklqns\n"+
+		"phrases:\n  new_bvn.json: "+mmNewBvn+"\n"+
+		"confirm-recovery: true\nshow-secrets: false\n"), 0600))
+	answers, err := loadAnswers(file)
+	require.NoError(t, err)
+	assert.Equal(t, "yz5x2a7zhwwt7r0lv4gklqns", answers.VaultID)
+	assert.Equal(t, mmNewBvn, answers.Phrases["new_bvn.json"])
+	assert.True(t, *answers.ConfirmRecovery)
+	assert.Nil(t, answers.AcceptPolicy)
+
+	// -yes answers the open confirmations, and not the ones already answered
+	answers.withYes()
+	assert.True(t, *answers.AcceptPolicy)
+	assert.False(t, *answers.ShowSecrets)
+	assert.Equal(t, ui.ConfirmRecover, answers.ConfirmChoice())
+
+	require.NoError(t, os.WriteFile(file, []byte("vault-id: x\nshow-secret: true\n"), 0600))
+	_, err = loadAnswers(file)
+	assert.ErrorContains(t, err, "show-secret")
+
+	require.NoError(t, os.WriteFile(file, []byte("phrases-from-keychain: true\nphrases:\n  a.json: words\n"), 0600))
+	_, err = loadAnswers(file)
+	assert.ErrorContains(t, err, "keep one")
+
+	_, err = loadAnswers(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestAnswers_Check(t *testing.T) {
+	yes := true
+	complete := Answers{VaultID: "vault-1", Phrases: map[string]string{"a.json": "words"}, ConfirmRecovery: &yes, AcceptPolicy: &yes, ShowSecrets: &yes}
+	assert.NoError(t, complete.Check(UnattendedRun{Policy: true}))
+	assert.NoError(t, complete.Check(UnattendedRun{VaultID: "vault-1"}))
+
+	tests := []struct {
+		answers Answers
+		run     UnattendedRun
+		err     string
+	}{
+		{complete, UnattendedRun{LockAfter: true}, "-lock-after"},
+		{complete, UnattendedRun{FourEyes: true}, "-four-eyes"},
+		{complete, UnattendedRun{ConfirmWord: true}, "-confirm-word"},
+		{complete, UnattendedRun{VaultID: "vault-2"}, "differ"},
+		{Answers{Phrases: complete.Phrases}, UnattendedRun{}, "`vault-id`"},
+		{Answers{VaultID: "vault-1"}, UnattendedRun{}, "`phrases`"},
+		{Answers{VaultID: "vault-1", Phrases: complete.Phrases}, UnattendedRun{}, "`confirm-recovery`"},
+		{Answers{VaultID: "vault-1", Phrases: complete.Phrases, ConfirmRecovery: &yes}, UnattendedRun{Policy: true}, "`accept-policy`"},
+		{Answers{VaultID: "vault-1", Phrases: complete.Phrases, ConfirmRecovery: &yes}, UnattendedRun{}, "`show-secrets`"},
+	}
+	for _, test := range tests {
+		assert.ErrorContains(t, test.answers.Check(test.run), test.err)
+	}
+
+	// -plan and -addresses-only stop before the later prompts
+	assert.NoError(t, (&Answers{VaultID: "vault-1", Phrases: complete.Phrases}).Check(UnattendedRun{Plan: true}))
+	assert.NoError(t, (&Answers{VaultID: "vault-1", Phrases: complete.Phrases, ConfirmRecovery: &yes}).Check(UnattendedRun{AddressesOnly: true, Policy: true}))
+
+	// a batch picks its vaults on the command line and shows no keys
+	batch := &Answers{Phrases: complete.Phrases, ConfirmRecovery: &yes, AcceptPolicy: &yes}
+	assert.NoError(t, batch.Check(UnattendedRun{Batch: true, Policy: true}))
+	assert.ErrorContains(t, (&Answers{Phrases: complete.Phrases, ConfirmRecovery: &yes}).Check(UnattendedRun{Batch: true, Policy: true}), "accept-policy")
+	assert.ErrorContains(t, complete.Check(UnattendedRun{Batch: true}), "remove vault-id from the answers file")
+}
+
+func TestAnswers_VaultsDataFiles(t *testing.T) {
+	files := []string{"./test-files/new_bvn.json", "./test-files/new_x2q.json"}
+	answers := Answers{Phrases: map[string]string{
+		"./test-files/new_bvn.json": mmNewBvn,
+		"new_x2q.json":              "  " + mmNewX2q + "\n",
+	}}
+	vaultsDataFiles, err := answers.VaultsDataFiles(files, nil)
+	require.NoError(t, err)
+	require.Len(t, vaultsDataFiles, 2)
+	assert.Equal(t, mmNewBvn, vaultsDataFiles[0].Mnemonics)
+	assert.Equal(t, mmNewX2q, vaultsDataFiles[1].Mnemonics)
+
+	_, err = answers.VaultsDataFiles(append(files, "./test-files/new_u44.json"), nil)
+	assert.ErrorContains(t, err, "new_u44.json")
+
+	answers.Phrases["new_x2q.json"] = mmNewBvn
+	_, err = answers.VaultsDataFiles(files, nil)
+	assert.ErrorContains(t, err, "answered phrase of `./test-files/new_x2q.json` is wrong")
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/bundle"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+)
+
+const (
+	unbundleCmd = "unbundle"
+
+	sealedBundleExt = ".enc"
+)
+
+// collectArtifacts finds the files written by this session, keyed by their name in the bundle. Exported files are
+// tagged with the session ID, so files of earlier runs in the same directory are left alone.
+func collectArtifacts(appConfig config.AppConfig) (map[string]string, error) {
+	artifacts := make(map[string]string)
+	if appConfig.ExportKSFile != "" && appConfig.PasswordForKS != "" {
+		if _, err := os.Stat(appConfig.ExportKSFile); err == nil {
+			artifacts[filepath.Base(appConfig.ExportKSFile)] = appConfig.ExportKSFile
+		}
+	}
+	if appConfig.ExportPEMFile != "" {
+		pkcs8File, sec1File := pemFilenames(appConfig.ExportPEMFile)
+		for _, file := range []string{pkcs8File, sec1File} {
+			if _, err := os.Stat(file); err == nil {
+				artifacts[filepath.Base(file)] = file
+			}
+		}
+	}
+	if appConfig.ExportCardanoKey != "" {
+		file := ui.SessionFilename(appConfig.ExportCardanoKey)
+		if _, err := os.Stat(file); err == nil {
+			artifacts[filepath.Base(file)] = file
+		}
+	}
+	if appConfig.ExportTSSShareDir != "" {
+		files, err := filepath.Glob(filepath.Join(appConfig.ExportTSSShareDir, "*-"+ui.SessionID+".json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			artifacts["tss-shares/"+filepath.Base(file)] = file
+		}
+	}
+	return artifacts, nil
+}
+
+// bundleArtifacts moves the files written by this session into a single ZIP in dir named with the vault and session ID,
+// sealed with the password if one is set, and returns its name and SHA-256. The files are only removed once the ZIP
+// is written.
+func bundleArtifacts(dir, vaultID string, artifacts map[string]string, password string) (filename, hash string, err error) {
+	if len(artifacts) == 0 {
+		return "", "", errors.New("⚠ no files were written in this session, so there is nothing to bundle")
+	}
+	entries := make(map[string][]byte, len(artifacts))
+	defer func() {
+		for _, content := range entries {
+			clear(content)
+		}
+	}()
+	for name, file := range artifacts {
+		if entries[name], err = os.ReadFile(file); err != nil {
+			return "", "", fmt.Errorf("⚠ could not read `%s` to bundle it: %v", file, err)
+		}
+	}
+	data, err := bundle.Zip(entries)
+	if err != nil {
+		return "", "", err
+	}
+	filename = filepath.Join(dir, ui.SessionFilename(fmt.Sprintf("recovery-%s.zip", vaultID)))
+	if password != "" {
+		if data, err = bundle.Seal(data, password, walletv3.Presets["standard"]); err != nil {
+			return "", "", err
+		}
+		filename += sealedBundleExt
+	}
+	if err = writeNewFile(filename, data); err != nil {
+		return "", "", fmt.Errorf("⚠ could not write the bundle `%s`: %v", filename, err)
+	}
+	files := make([]string, 0, len(artifacts))
+	for _, file := range artifacts {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if err = os.Remove(file); err != nil {
+			return "", "", fmt.Errorf("⚠ wrote the bundle `%s`, but could not remove `%s`: %v", filename, file, err)
+		}
+	}
+	sum := sha256.Sum256(data)
+	return filename, hex.EncodeToString(sum[:]), nil
+}
+
+// runUnbundle decrypts a bundle sealed with -bundle-password back into a regular ZIP.
+func runUnbundle(args []string) error {
+	fs := flag.NewFlagSet(unbundleCmd, flag.ContinueOnError)
+	password := fs.String("password", "", "The -bundle-password the bundle was sealed with.")
+	out := fs.String("out", "", "(Optional) Filename of the ZIP to write. Defaults to the bundle name without "+sealedBundleExt+".")
+	allowSyncedPath := fs.Bool("allow-synced-path", false, "(Optional) Allow writing the ZIP into a cloud synced folder (Dropbox, OneDrive, iCloud Drive or Google Drive).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: recovery-tool %s -password <password> recovery-<vault id>-<session id>.zip%s\n\nFlags:\n", unbundleCmd, sealedBundleExt)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("⚠ expected exactly one sealed bundle")
+	}
+	file := fs.Arg(0)
+	if *out == "" {
+		if !strings.HasSuffix(file, sealedBundleExt) {
+			return fmt.Errorf("⚠ `%s` does not end in %s; set -out for the ZIP to write", file, sealedBundleExt)
+		}
+		*out = strings.TrimSuffix(file, sealedBundleExt)
+	}
+	warnings, err := checkSyncedOutputs([]string{*out}, *allowSyncedPath)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Println(warning)
+	}
+	sealed, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("⚠ unable to read the bundle `%s`: %v", file, err)
+	}
+	data, err := bundle.Open(sealed, *password)
+	if err != nil {
+		return err
+	}
+	defer clear(data)
+	if err = writeNewFile(*out, data); err != nil {
+		return fmt.Errorf("⚠ could not write `%s`: %v", *out, err)
+	}
+	fmt.Printf("Wrote the decrypted bundle to: %s\n", *out)
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build entropytest
+
+package main
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/entropy"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifacts_Reproducible(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	label := "INC-42"
+	// with the same entropy, the keystore and the sealed bundle of it are the same bytes in every run
+	export := func() (keyfile []byte, bundleHash string) {
+		t.Cleanup(entropy.Use(entropy.NewDeterministic("artifacts")))
+		keyfile, err := encryptKeystore(ecdsaSK, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", "pw", walletv3.Presets["light"], &label)
+		require.NoError(t, err)
+		dir := t.TempDir()
+		keystore := filepath.Join(dir, ui.SessionFilename("wallet.json"))
+		require.NoError(t, os.WriteFile(keystore, keyfile, 0600))
+		_, bundleHash, err = bundleArtifacts(dir, "vault", map[string]string{filepath.Base(keystore): keystore}, "bundle password")
+		require.NoError(t, err)
+		return keyfile, bundleHash
+	}
+	firstKeyfile, firstHash := export()
+	secondKeyfile, secondHash := export()
+	assert.Equal(t, firstKeyfile, secondKeyfile)
+	assert.Equal(t, firstHash, secondHash)
+}
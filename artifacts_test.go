@@ -0,0 +1,60 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifacts_BundleAndUnbundle(t *testing.T) {
+	dir := t.TempDir()
+	tssDir := filepath.Join(dir, "tss")
+	require.NoError(t, os.MkdirAll(tssDir, 0700))
+	keystore := filepath.Join(dir, ui.SessionFilename("wallet.json"))
+	share := filepath.Join(tssDir, ui.SessionFilename("vault-party-1.json"))
+	earlierRun := filepath.Join(tssDir, "vault-party-1-00000000.json")
+	for _, file := range []string{keystore, share, earlierRun} {
+		require.NoError(t, os.WriteFile(file, []byte(`{}`), 0600))
+	}
+
+	artifacts, err := collectArtifacts(config.AppConfig{ExportKSFile: keystore, PasswordForKS: "pw", ExportTSSShareDir: tssDir})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{filepath.Base(keystore): keystore, "tss-shares/" + filepath.Base(share): share}, artifacts)
+
+	filename, hash, err := bundleArtifacts(dir, "vault", artifacts, "bundle password")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ui.SessionFilename("recovery-vault.zip"))+sealedBundleExt, filename)
+	sealed, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	sum := sha256.Sum256(sealed)
+	assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+	assert.NoFileExists(t, keystore)
+	assert.NoFileExists(t, share)
+	assert.FileExists(t, earlierRun)
+
+	assert.ErrorContains(t, runUnbundle([]string{"-password", "wrong", filename}), "password")
+	require.NoError(t, runUnbundle([]string{"-password", "bundle password", filename}))
+	zr, err := zip.OpenReader(filepath.Join(dir, ui.SessionFilename("recovery-vault.zip")))
+	require.NoError(t, err)
+	defer zr.Close()
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"tss-shares/" + filepath.Base(share), filepath.Base(keystore)}, names)
+
+	_, _, err = bundleArtifacts(dir, "vault", map[string]string{}, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+// batchConflictingFlags show or write the keys of a single vault, so they cannot be used in a batch, which writes each
+// vault's keys to its own file instead.
+var batchConflictingFlags = []string{"password", "export", "export-tss-share", "export-pem", "export-cardano-key", "bundle", "show-ur", "rotate", "sweep-params", "bip85",
+	"export-addresses", "export-watch-only", "post-hook", "lock-after", "confirm-word", "plan", "check-address",
+	"known-addresses", "output", "drill-keychain"}
+
+type (
+	// VaultIDs are the repeated -vault-id flags. More than one recovers those vaults in a batch.
+	VaultIDs []string
+
+	// BatchOutcome is how the recovery of a vault of a batch went: the file its result was written to, or its error.
+	BatchOutcome struct {
+		Vault ui.VaultPickerItem
+		File  string
+		Err   error
+	}
+)
+
+func (v *VaultIDs) String() string {
+	return strings.Join(*v, ",")
+}
+
+// Set adds one -vault-id flag.
+func (v *VaultIDs) Set(s string) error {
+	vID := strings.TrimSpace(s)
+	if vID == "" {
+		return fmt.Errorf("⚠ -vault-id needs a vault ID, e.g. -vault-id %s", "cl347wz8w00006sx3f1g23p4s")
+	}
+	for _, existing := range *v {
+		if existing == vID {
+			return fmt.Errorf("⚠ vault `%s` is given to -vault-id more than once", vID)
+		}
+	}
+	*v = append(*v, vID)
+	return nil
+}
+
+// batchConflicts are the flags set on the command line that cannot be used in a batch.
+func batchConflicts(named map[string]bool) []string {
+	conflicts := make([]string, 0)
+	for _, name := range batchConflictingFlags {
+		if named[name] {
+			conflicts = append(conflicts, "-"+name)
+		}
+	}
+	return conflicts
+}
+
+// batchVaults are the vaults of a batch, among the vaults of the backup files: all of them, or the -vault-id ones in
+// the order given. An unknown vault ID fails the batch before any vault is recovered.
+func batchVaults(listed []ui.VaultPickerItem, vaultIDs VaultIDs, all bool) ([]ui.VaultPickerItem, error) {
+	if all {
+		return listed, nil
+	}
+	vaults := make([]ui.VaultPickerItem, 0, len(vaultIDs))
+	unknown := make([]string, 0)
+	for _, vID := range vaultIDs {
+		found := false
+		for _, vault := range listed {
+			if vault.VaultID == vID {
+				vaults = append(vaults, vault)
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, vID)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("⚠ the backup files have no vault %s, so the batch was not started", strings.Join(unknown, ", "))
+	}
+	return vaults, nil
+}
+
+// batchResultFilename is the file of the result of a vault in the -batch-out directory, tagged with the session ID.
+func batchResultFilename(dir, vaultID string) string {
+	return filepath.Join(dir, ui.SessionFilename("vault-"+vaultID+".json"))
+}
+
+// batchPlannedOutputs lists what a batch will write, for the confirmation screen.
+func batchPlannedOutputs(dir string, addressesOnly, showPubKeys bool, chains ChainSelection) []string {
+	contents := "its addresses and private keys, unencrypted"
+	if addressesOnly {
+		contents = "its addresses, and no private keys"
+	}
+	if showPubKeys {
+		contents += ", and its public keys"
+	}
+	outputs := []string{fmt.Sprintf("Written to disk: one JSON file per vault in %s, with %s", dir, contents)}
+	if chains != nil {
+		outputs = append(outputs, fmt.Sprintf("Only for the chains: %s", strings.Join(chains.IDs(), ", ")))
+	}
+	return append(outputs, "Shown on screen: which vaults were recovered, and no private keys")
+}
+
+// recoverBatch recovers the vaults one after the other with the entered phrases, and writes the result of each to
+// its own file in dir, as a -output json document. A vault that fails does not stop the others.
+func recoverBatch(vaultsDataFiles []ui.VaultsDataFile, vaults []ui.VaultPickerItem, dir string, overrides VaultOverrides,
+	nonceOverride, quorumOverride *int, force bool, commitments VaultCommitments, chains ChainSelection, addressesOnly, showPubKeys bool) []BatchOutcome {
+	outcomes := make([]BatchOutcome, 0, len(vaults))
+	if err := strictWrites.Check(dir); err != nil {
+		for _, vault := range vaults {
+			outcomes = append(outcomes, BatchOutcome{Vault: vault, Err: err})
+		}
+		return outcomes
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		err = fmt.Errorf("⚠ could not create the -batch-out directory `%s`: %v", dir, err)
+		for _, vault := range vaults {
+			outcomes = append(outcomes, BatchOutcome{Vault: vault, Err: err})
+		}
+		return outcomes
+	}
+	for i, vault := range vaults {
+		ui.Printf("\nRecovering vault %d of %d: \"%s\" with ID %s\n", i+1, len(vaults), vault.Name, vault.VaultID)
+		file, err := recoverBatchVault(vaultsDataFiles, vault, dir, overrides, nonceOverride, quorumOverride, force, commitments, chains, addressesOnly, showPubKeys)
+		outcomes = append(outcomes, BatchOutcome{Vault: vault, File: file, Err: err})
+	}
+	return outcomes
+}
+
+func recoverBatchVault(vaultsDataFiles []ui.VaultsDataFile, vault ui.VaultPickerItem, dir string, overrides VaultOverrides,
+	nonceOverride, quorumOverride *int, force bool, commitments VaultCommitments, chains ChainSelection, addressesOnly, showPubKeys bool) (string, error) {
+	vaultID := vault.VaultID
+	nonce, quorum := overrides.For(vaultID, nonceOverride, quorumOverride)
+	address, ecSK, edSK, _, err := runTool(vaultsDataFiles, ToolOptions{VaultID: vaultID, NonceOverride: nonce, QuorumOverride: quorum, Force: force, Commitments: commitments})
+	defer func() {
+		clear(ecSK)
+		clear(edSK)
+	}()
+	if err != nil {
+		return "", err
+	}
+	var edPKBytes []byte
+	if edSK != nil {
+		_, edPK, err := edwards.PrivKeyFromScalar(edSK)
+		if err != nil {
+			return "", fmt.Errorf("⚠ the recovered EdDSA key of vault `%s` is not valid: %s", vaultID, err)
+		}
+		edPKBytes = edPK.SerializeCompressed()
+	}
+
+	result := new(bytes.Buffer)
+	defer func() { clear(result.Bytes()) }()
+	if err = writeRecoveryResult(result, recoveryResult(vaultID, vault.Name, address, ecSK, edSK, edPKBytes, chains, !addressesOnly, showPubKeys, nil)); err != nil {
+		return "", err
+	}
+	file := batchResultFilename(dir, vaultID)
+	if err = writeNewFile(file, result.Bytes()); err != nil {
+		return "", fmt.Errorf("⚠ failed to write the result of vault `%s`: %w", vaultID, err)
+	}
+	return file, nil
+}
+
+// printBatchOutcomes prints how each vault of the batch went, and returns how many failed.
+func printBatchOutcomes(out io.Writer, outcomes []BatchOutcome) int {
+	failed := 0
+	fmt.Fprintf(out, "\n%s%s BATCH RECOVERY %s\n\n", ui.AnsiCodes["invertOn"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	for _, outcome := range outcomes {
+		if outcome.Err != nil {
+			failed++
+			fmt.Fprintf(out, "%s \"%s\" (%s): %s\n", ui.Plain("⚠"), outcome.Vault.Name, outcome.Vault.VaultID, strings.TrimPrefix(outcome.Err.Error(), "⚠ "))
+			continue
+		}
+		fmt.Fprintf(out, "%s \"%s\" (%s): %s\n", ui.Plain("✓"), outcome.Vault.Name, outcome.Vault.VaultID, outcome.File)
+	}
+	fmt.Fprintf(out, "\n%d of %d vaults recovered.", len(outcomes)-failed, len(outcomes))
+	if len(outcomes) > failed {
+		fmt.Fprintf(out, " Keep the result files safe: they are not encrypted.")
+	}
+	fmt.Fprintln(out)
+	return failed
+}
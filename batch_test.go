@@ -0,0 +1,94 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultIDs_Set(t *testing.T) {
+	var vaultIDs VaultIDs
+	require.NoError(t, vaultIDs.Set("vault-1"))
+	require.NoError(t, vaultIDs.Set(" vault-2 "))
+	assert.Equal(t, VaultIDs{"vault-1", "vault-2"}, vaultIDs)
+	assert.ErrorContains(t, vaultIDs.Set("vault-1"), "more than once")
+	assert.Error(t, vaultIDs.Set(""))
+}
+
+func TestBatchConflicts(t *testing.T) {
+	assert.Empty(t, batchConflicts(map[string]bool{"vault-id": true, "batch-out": true, "chains": true, "policy": true}))
+	assert.Equal(t, []string{"-password", "-rotate"}, batchConflicts(map[string]bool{"rotate": true, "password": true, "yes": true}))
+}
+
+func TestBatchVaults(t *testing.T) {
+	listed := []ui.VaultPickerItem{{VaultID: "vault-1"}, {VaultID: "vault-2"}, {VaultID: "vault-3"}}
+	vaults, err := batchVaults(listed, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, listed, vaults)
+
+	vaults, err = batchVaults(listed, VaultIDs{"vault-3", "vault-1"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []ui.VaultPickerItem{{VaultID: "vault-3"}, {VaultID: "vault-1"}}, vaults)
+
+	_, err = batchVaults(listed, VaultIDs{"vault-1", "vault-9"}, false)
+	assert.ErrorContains(t, err, "no vault vault-9")
+}
+
+func TestRecoverBatch(t *testing.T) {
+	manifest, err := generateFixtures(fixtureOptions{OutDir: t.TempDir(), Vaults: 2, Parties: 3, Threshold: 2, EdDSA: true, V2: true})
+	require.NoError(t, err)
+	files := make([]ui.VaultsDataFile, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		files = append(files, ui.VaultsDataFile{File: file.File, Mnemonics: file.Mnemonics})
+	}
+	_, _, _, listed, err := runTool(files, ToolOptions{})
+	require.NoError(t, err)
+	// a vault that cannot be recovered does not stop the others
+	listed = append(listed, ui.VaultPickerItem{VaultID: "missing", Name: "Missing"})
+
+	dir := filepath.Join(t.TempDir(), "results")
+	outcomes := recoverBatch(files, listed, dir, nil, nil, nil, false, nil, nil, false, false)
+	require.Len(t, outcomes, 3)
+	for _, vault := range manifest.Vaults {
+		var outcome BatchOutcome
+		for _, o := range outcomes {
+			if o.Vault.VaultID == vault.VaultID {
+				outcome = o
+			}
+		}
+		require.NoError(t, outcome.Err)
+		assert.Equal(t, batchResultFilename(dir, vault.VaultID), outcome.File)
+
+		content, err := os.ReadFile(outcome.File)
+		require.NoError(t, err)
+		var result RecoveryResult
+		require.NoError(t, json.Unmarshal(content, &result))
+		assert.Equal(t, vault.Address, result.Address)
+		assert.Equal(t, vault.ECDSAPrivateKey, result.ECDSA.PrivateKey)
+		assert.Equal(t, vault.EdDSAPrivateKey, result.EdDSA.PrivateKey)
+	}
+	assert.Error(t, outcomes[2].Err)
+
+	out := new(bytes.Buffer)
+	assert.Equal(t, 1, printBatchOutcomes(out, outcomes))
+	assert.Contains(t, out.String(), "2 of 3 vaults recovered.")
+
+	// -addresses-only writes no keys
+	dir = filepath.Join(t.TempDir(), "addresses")
+	outcomes = recoverBatch(files, listed[:1], dir, nil, nil, nil, false, nil, nil, true, false)
+	require.NoError(t, outcomes[0].Err)
+	content, err := os.ReadFile(outcomes[0].File)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), manifest.Vaults[0].ECDSAPrivateKey)
+	assert.NotContains(t, string(content), "privateKey")
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/bip85"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// parseBIP85Indexes parses the -bip85 list of child seed indexes, e.g. "0,1,5".
+func parseBIP85Indexes(s string) ([]uint32, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var indexes []uint32
+	seen := make(map[uint32]bool)
+	for _, part := range strings.Split(s, ",") {
+		index, err := strconv.ParseUint(strings.TrimSpace(part), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("⚠ invalid BIP85 index `%s`: use indexes from 0 to 2147483647, e.g. -bip85 0,1,5", strings.TrimSpace(part))
+		}
+		if !seen[uint32(index)] {
+			seen[uint32(index)] = true
+			indexes = append(indexes, uint32(index))
+		}
+	}
+	return indexes, nil
+}
+
+// printBIP85Seeds derives BIP85 child seeds from the recovered ECDSA key, with the root and path of each one so that
+// the issued indexes can be recorded and the seeds derived again. The root is specific to this tool, which it says.
+func printBIP85Seeds(out io.Writer, ecdsaSK []byte, words int, indexes []uint32) error {
+	root, err := bip85.VaultRoot(ecdsaSK)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		clear(root.Key)
+		clear(root.ChainCode)
+	}()
+	fmt.Fprintf(out, "\n%s%s BIP85 CHILD SEEDS %s\n", ui.AnsiCodes["invertOn"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "\nThese seeds are derived from the vault ECDSA key. Keep safe and do not share.\n")
+	fmt.Fprintf(out, "%sThey can be derived again from the vault only with this tool:%s the BIP85 root below uses a chain code of this tool, not a standard one. Other BIP85 tools derive them only from this root xprv.\n",
+		ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "BIP85 root (the vault ECDSA key as a BIP32 root): %s%s%s\n", ui.AnsiCodes["bold"], root.XPRV(), ui.AnsiCodes["reset"])
+	for _, index := range indexes {
+		mnemonic, err := root.BIP39(words, index)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "  Index %d (%s): %s%s%s\n", index, bip85.Path(words, index), ui.AnsiCodes["bold"], mnemonic, ui.AnsiCodes["reset"])
+	}
+	fmt.Fprintf(out, "Record which indexes were issued to which system, so that they are never issued twice.\n")
+	return nil
+}
+
+// bip85Result records the -bip85 child seeds in the -output json result and the recovery summary: their indexes, paths
+// and word count, and the fingerprint of the root. The phrases and the root xprv are only added if showSecrets.
+func bip85Result(ecdsaSK []byte, words int, indexes []uint32, showSecrets bool) (*ResultBIP85, error) {
+	root, err := bip85.VaultRoot(ecdsaSK)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		clear(root.Key)
+		clear(root.ChainCode)
+	}()
+	result := &ResultBIP85{RootFingerprint: root.Fingerprint(), Words: words, Seeds: make([]ResultBIP85Child, 0, len(indexes))}
+	if showSecrets {
+		result.RootXPRV = root.XPRV()
+	}
+	for _, index := range indexes {
+		child := ResultBIP85Child{Index: index, Path: bip85.Path(words, index)}
+		if showSecrets {
+			if child.Mnemonic, err = root.BIP39(words, index); err != nil {
+				return nil, err
+			}
+		}
+		result.Seeds = append(result.Seeds, child)
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/bip85"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBIP85_ParseIndexes(t *testing.T) {
+	indexes, err := parseBIP85Indexes(" 0, 5,1,5 ")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{0, 5, 1}, indexes)
+
+	indexes, err = parseBIP85Indexes("")
+	require.NoError(t, err)
+	assert.Empty(t, indexes)
+
+	for _, bad := range []string{"-1", "a", "2147483648", "1,,2"} {
+		_, err = parseBIP85Indexes(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestBIP85_PrintSeeds(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	root, err := bip85.VaultRoot(ecdsaSK)
+	require.NoError(t, err)
+	seed, err := root.BIP39(12, 7)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, printBIP85Seeds(out, ecdsaSK, 12, []uint32{7}))
+	assert.Contains(t, out.String(), root.XPRV())
+	assert.Contains(t, out.String(), "Index 7 (m/83696968'/39'/0'/12'/7')")
+	assert.Contains(t, out.String(), seed)
+	assert.Contains(t, out.String(), "derived again from the vault only with this tool", "the root is not standard")
+
+	assert.Error(t, printBIP85Seeds(out, ecdsaSK, 13, []uint32{0}))
+}
+
+func TestBIP85_Result(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	root, err := bip85.VaultRoot(ecdsaSK)
+	require.NoError(t, err)
+	seed, err := root.BIP39(12, 7)
+	require.NoError(t, err)
+
+	result, err := bip85Result(ecdsaSK, 12, []uint32{7, 0}, false)
+	require.NoError(t, err)
+	assert.Equal(t, &ResultBIP85{RootFingerprint: root.Fingerprint(), Words: 12, Seeds: []ResultBIP85Child{
+		{Index: 7, Path: "m/83696968'/39'/0'/12'/7'"},
+		{Index: 0, Path: "m/83696968'/39'/0'/12'/0'"},
+	}}, result, "no phrases or root without the secrets")
+
+	result, err = bip85Result(ecdsaSK, 12, []uint32{7}, true)
+	require.NoError(t, err)
+	assert.Equal(t, root.XPRV(), result.RootXPRV)
+	assert.Equal(t, seed, result.Seeds[0].Mnemonic)
+
+	_, err = bip85Result(ecdsaSK, 13, []uint32{0}, true)
+	assert.Error(t, err)
+}
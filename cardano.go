@@ -0,0 +1,34 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/cardano"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// printCardanoAddress prints the enterprise payment address of the EdDSA key, which cardano-cli computes from the
+// -export-cardano-key file.
+func printCardanoAddress(out io.Writer, eddsaPK []byte) {
+	fmt.Fprintf(out, "It controls the Cardano address: %s%s%s\n", ui.AnsiCodes["bold"], cardano.Address(eddsaPK, false), ui.AnsiCodes["reset"])
+}
+
+// writeCardanoKey writes the recovered EdDSA key as a cardano-cli signing key file, tagged with the session ID and
+// readable by the owner only. Existing files are never overwritten.
+func writeCardanoKey(name string, eddsaSK, eddsaPK []byte) (string, error) {
+	data, err := cardano.SigningKey(eddsaSK, eddsaPK)
+	if err != nil {
+		return "", err
+	}
+	defer clear(data)
+	filename := ui.SessionFilename(name)
+	if err = writeNewFile(filename, data); err != nil {
+		return "", fmt.Errorf("⚠ failed to write the Cardano signing key file: %w", err)
+	}
+	return filename, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/cardano"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardanoKey(t *testing.T) {
+	eddsaSK := make([]byte, 32)
+	eddsaSK[31] = 7
+	_, pub, err := edwards.PrivKeyFromScalar(eddsaSK)
+	require.NoError(t, err)
+	eddsaPK := pub.SerializeCompressed()
+
+	out := new(bytes.Buffer)
+	printCardanoAddress(out, eddsaPK)
+	assert.Contains(t, out.String(), cardano.Address(eddsaPK, false))
+
+	name := filepath.Join(t.TempDir(), "payment.skey")
+	file, err := writeCardanoKey(name, eddsaSK, eddsaPK)
+	require.NoError(t, err)
+	assert.Equal(t, ui.SessionFilename(name), file)
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var envelope cardano.TextEnvelope
+	require.NoError(t, json.Unmarshal(data, &envelope))
+	assert.Equal(t, cardano.SigningKeyType, envelope.Type)
+
+	// existing files are never overwritten
+	_, err = writeCardanoKey(name, eddsaSK, eddsaPK)
+	assert.Error(t, err)
+}
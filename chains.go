@@ -0,0 +1,222 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	allChains = "all"
+
+	// settingsDirName is the directory of the tool's settings in the user config directory, and of its lock files in the user
+	// cache directory
+	settingsDirName  = "io-vault-recovery-tool"
+	settingsFileName = "settings.json"
+)
+
+// OutputChain is a chain that -chains can select, with the names of its addresses in vaultAddresses. A name ending
+// with * stands for every name it prefixes.
+type OutputChain struct {
+	ID     string
+	Chains []string
+}
+
+// Has reports whether an address of vaultAddresses, by its name, is of the chain.
+func (c OutputChain) Has(name string) bool {
+	return slices.ContainsFunc(c.Chains, func(chain string) bool {
+		prefix, wildcard := strings.CutSuffix(chain, "*")
+		return chain == name || wildcard && strings.HasPrefix(name, prefix)
+	})
+}
+
+// outputChains are the chains of the recovered keys, in output order.
+var outputChains = []OutputChain{
+	{"ethereum", []string{"Ethereum & EVM chains"}},
+	{"tron", []string{"Tron"}},
+	{"bitcoin", []string{"Bitcoin mainnet (P2WPKH)", "Bitcoin mainnet (P2TR)", "Bitcoin testnet (P2WPKH)", "Bitcoin testnet (P2TR)"}},
+	{"zcash", []string{"Zcash transparent (t1)"}},
+	{"horizen", []string{"Horizen transparent (zn)"}},
+	{"komodo", []string{"Komodo"}},
+	{"cosmos", []string{"Cosmos SDK (*"}},
+	{"solana", []string{"Solana"}},
+	{"xrpl", []string{"XRP Ledger"}},
+	{"cardano", []string{"Cardano"}},
+	{"stellar", []string{"Stellar"}},
+	{"eddsa", []string{"EdDSA public key (TON, TAO, etc.)"}},
+}
+
+type (
+	// ChainSelection is the set of chain IDs to show the addresses and keys of. Nil selects every chain.
+	ChainSelection map[string]bool
+
+	// Settings are remembered across runs in the user config directory. Nothing secret is ever stored.
+	Settings struct {
+		Chains []string `json:"chains,omitempty"`
+	}
+)
+
+// parseChains reads a comma separated list of chain IDs, or "all".
+func parseChains(option string) (ChainSelection, error) {
+	if strings.TrimSpace(strings.ToLower(option)) == allChains {
+		return nil, nil
+	}
+	selection := make(ChainSelection)
+	for _, id := range strings.Split(option, ",") {
+		id = strings.TrimSpace(strings.ToLower(id))
+		if id == "" {
+			continue
+		}
+		if !slices.ContainsFunc(outputChains, func(c OutputChain) bool { return c.ID == id }) {
+			return nil, fmt.Errorf("⚠ unknown chain `%s` in -chains, expected all or some of %s", id, strings.Join(chainIDs(), ", "))
+		}
+		selection[id] = true
+	}
+	if len(selection) == 0 {
+		return nil, fmt.Errorf("⚠ -chains needs at least one of %s, or all", strings.Join(chainIDs(), ", "))
+	}
+	return selection, nil
+}
+
+func chainIDs() []string {
+	ids := make([]string, len(outputChains))
+	for i, chain := range outputChains {
+		ids[i] = chain.ID
+	}
+	return ids
+}
+
+// Shows reports whether the outputs of any of the chains are shown.
+func (s ChainSelection) Shows(ids ...string) bool {
+	if s == nil {
+		return true
+	}
+	return slices.ContainsFunc(ids, func(id string) bool { return s[id] })
+}
+
+// ShowsChain reports whether the outputs of a chain, by its name in vaultAddresses, are shown.
+func (s ChainSelection) ShowsChain(name string) bool {
+	if s == nil {
+		return true
+	}
+	return slices.ContainsFunc(outputChains, func(chain OutputChain) bool { return s[chain.ID] && chain.Has(name) })
+}
+
+// Addresses keeps the addresses of the selected chains.
+func (s ChainSelection) Addresses(addresses []ChainAddress) []ChainAddress {
+	if s == nil {
+		return addresses
+	}
+	selected := make([]ChainAddress, 0, len(addresses))
+	for _, a := range addresses {
+		if s.ShowsChain(a.Chain) {
+			selected = append(selected, a)
+		}
+	}
+	return selected
+}
+
+// IDs are the selected chain IDs in output order, or nil for every chain.
+func (s ChainSelection) IDs() []string {
+	if s == nil {
+		return nil
+	}
+	var ids []string
+	for _, chain := range outputChains {
+		if s[chain.ID] {
+			ids = append(ids, chain.ID)
+		}
+	}
+	return ids
+}
+
+// settingsPath is the settings file in the user config directory.
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, settingsDirName, settingsFileName), nil
+}
+
+// loadSettings reads the settings file. There are no settings until they are saved.
+func loadSettings(file string) (Settings, error) {
+	var settings Settings
+	content, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("⚠ unable to read the settings file `%s`: %s", file, err)
+	}
+	if err = json.Unmarshal(content, &settings); err != nil {
+		return settings, fmt.Errorf("⚠ the settings file `%s` is not valid, fix or delete it: %s", file, err)
+	}
+	return settings, nil
+}
+
+// saveSettings replaces the settings file, readable by the user only.
+func saveSettings(file string, settings Settings) error {
+	content, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("⚠ unable to save the settings file `%s`: %s", file, err)
+	}
+	if err = os.WriteFile(file, append(content, '\n'), 0600); err != nil {
+		return fmt.Errorf("⚠ unable to save the settings file `%s`: %s", file, err)
+	}
+	return nil
+}
+
+// chainSelection is the -chains selection, or else the one saved in the settings file, which -save-chains replaces.
+// source tells where it comes from, for the notice under the outputs.
+func chainSelection(option string, save bool, settingsFile string) (selection ChainSelection, source string, err error) {
+	if option == "" {
+		if save {
+			return nil, "", errors.New("⚠ -save-chains saves the -chains selection, so give -chains too, or -chains all to forget the saved one")
+		}
+		if settingsFile == "" {
+			return nil, "", nil
+		}
+		settings, err := loadSettings(settingsFile)
+		if err != nil || len(settings.Chains) == 0 {
+			return nil, "", err
+		}
+		if selection, err = parseChains(strings.Join(settings.Chains, ",")); err != nil {
+			return nil, "", fmt.Errorf("%s, in the settings file `%s`", err, settingsFile)
+		}
+		return selection, fmt.Sprintf("saved in %s", settingsFile), nil
+	}
+	if selection, err = parseChains(option); err != nil {
+		return nil, "", err
+	}
+	if save {
+		if settingsFile == "" {
+			return nil, "", errors.New("⚠ -save-chains: there is no user config directory to save the settings in")
+		}
+		if err = saveSettings(settingsFile, Settings{Chains: selection.IDs()}); err != nil {
+			return nil, "", err
+		}
+	}
+	return selection, "set with -chains", nil
+}
+
+// chainsNotice tells which chains the outputs were limited to, so that a missing key is never a surprise.
+func chainsNotice(selection ChainSelection, source string) string {
+	if selection == nil {
+		return ""
+	}
+	return fmt.Sprintf("\nOnly the outputs of %s are shown, as %s. Run with -chains all to show every chain.\n",
+		strings.Join(selection.IDs(), ", "), source)
+}
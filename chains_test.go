@@ -0,0 +1,104 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChains(t *testing.T) {
+	selection, err := parseChains(" Bitcoin, ethereum,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ethereum", "bitcoin"}, selection.IDs())
+	assert.True(t, selection.Shows("ethereum", "tron"))
+	assert.False(t, selection.Shows("solana", "xrpl", "eddsa"))
+
+	all, err := parseChains("ALL")
+	require.NoError(t, err)
+	assert.Nil(t, all)
+	assert.True(t, all.Shows("komodo"))
+
+	_, err = parseChains("ethereum,dogecoin")
+	assert.ErrorContains(t, err, "dogecoin")
+	_, err = parseChains(" , ")
+	assert.Error(t, err)
+}
+
+func TestChainSelection_Addresses(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	addresses := vaultAddresses(ecdsaSK, make([]byte, 32))
+	selection, err := parseChains("bitcoin,solana")
+	require.NoError(t, err)
+	selected := selection.Addresses(addresses)
+	require.Len(t, selected, 5)
+	assert.Equal(t, "Bitcoin mainnet (P2WPKH)", selected[0].Chain)
+	assert.Equal(t, "Bitcoin mainnet (P2TR)", selected[1].Chain)
+	assert.Equal(t, "Bitcoin testnet (P2WPKH)", selected[2].Chain)
+	assert.Equal(t, "Bitcoin testnet (P2TR)", selected[3].Chain)
+	assert.Equal(t, "Solana", selected[4].Chain)
+	assert.Equal(t, addresses, ChainSelection(nil).Addresses(addresses))
+
+	// every address of vaultAddresses belongs to a chain that can be selected
+	everything, err := parseChains("ethereum,tron,bitcoin,zcash,horizen,komodo,cosmos,solana,xrpl,cardano,stellar,eddsa")
+	require.NoError(t, err)
+	assert.Equal(t, addresses, everything.Addresses(addresses))
+
+	out := new(bytes.Buffer)
+	komodo, _ := parseChains("komodo")
+	printUTXOChainWIFs(out, ecdsaSK, komodo)
+	assert.Contains(t, out.String(), "Komodo WIF")
+	assert.NotContains(t, out.String(), "Zcash")
+	out.Reset()
+	printUTXOChainWIFs(out, ecdsaSK, selection)
+	assert.Empty(t, out.String())
+}
+
+func TestChainSelection_Settings(t *testing.T) {
+	file := filepath.Join(t.TempDir(), settingsDirName, settingsFileName)
+
+	// nothing saved: every chain
+	selection, source, err := chainSelection("", false, file)
+	require.NoError(t, err)
+	assert.Nil(t, selection)
+	assert.Empty(t, chainsNotice(selection, source))
+
+	selection, source, err = chainSelection("tron,ethereum", true, file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ethereum", "tron"}, selection.IDs())
+	assert.Equal(t, "set with -chains", source)
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// later runs default to the saved chains, and -chains overrides them for one run
+	selection, source, err = chainSelection("", false, file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ethereum", "tron"}, selection.IDs())
+	assert.Contains(t, chainsNotice(selection, source), "Only the outputs of ethereum, tron are shown, as saved in "+file)
+	selection, _, err = chainSelection("bitcoin", false, file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bitcoin"}, selection.IDs())
+
+	// -chains all forgets them
+	_, _, err = chainSelection("all", true, file)
+	require.NoError(t, err)
+	selection, _, err = chainSelection("", false, file)
+	require.NoError(t, err)
+	assert.Nil(t, selection)
+
+	_, _, err = chainSelection("", true, file)
+	assert.ErrorContains(t, err, "give -chains too")
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"chains": ["dogecoin"]}`), 0600))
+	_, _, err = chainSelection("", false, file)
+	assert.ErrorContains(t, err, "settings file")
+}
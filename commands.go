@@ -0,0 +1,20 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+// subcommands are utility commands run as `recovery-tool <command> [-flags]` instead of a vault recovery.
+// Each one parses its own flags from args.
+var subcommands = map[string]func(args []string) error{
+	compareCmd:       runCompare,
+	corpusCheckCmd:   runCorpusCheck,
+	genFixturesCmd:   runGenFixtures,
+	genMnemonicCmd:   runGenMnemonic,
+	helpGuideCmd:     runHelpGuide,
+	keychainCmd:      runKeychain,
+	peekCmd:          runPeek,
+	supportBundleCmd: runSupportBundle,
+	unbundleCmd:      runUnbundle,
+	versionCmd:       runVersion,
+}
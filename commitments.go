@@ -0,0 +1,68 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/binance-chain/tss-lib/crypto/vss"
+)
+
+type (
+	// VaultCommitments are the public Feldman VSS polynomial commitments of each vault ID, as exported at keygen.
+	VaultCommitments map[string]CurveCommitments
+	// CurveCommitments are the commitments of a vault's curves. The first commitment of each is the vault public key.
+	CurveCommitments struct {
+		ECDSA vss.Vs `json:"ecdsa"`
+		EdDSA vss.Vs `json:"eddsa,omitempty"`
+	}
+
+	// committedShare is a share to verify against commitments, and the file it was found in.
+	committedShare struct {
+		ID, Xi *big.Int
+		File   string
+	}
+)
+
+func loadCommitments(file string) (VaultCommitments, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to read commitments file `%s`: %s", file, err)
+	}
+	commitments := make(VaultCommitments)
+	if err = json.Unmarshal(content, &commitments); err != nil {
+		return nil, fmt.Errorf("⚠ invalid commitments file `%s`: %s", file, err)
+	}
+	for vID, curves := range commitments {
+		if len(curves.ECDSA) == 0 {
+			return nil, fmt.Errorf("⚠ commitments file `%s` has no ECDSA commitments for vault %s", file, vID)
+		}
+	}
+	return commitments, nil
+}
+
+// verifyShareCommitments checks each share against the public polynomial commitments of its vault before reconstruction,
+// and names every share that is inconsistent with them: a corrupted share, or one of another vault or reshare.
+func verifyShareCommitments(ec elliptic.Curve, algorithm, vaultID string, vs vss.Vs, shares []committedShare) error {
+	threshold := len(vs) - 1
+	var invalid []string
+	for _, share := range shares {
+		vssShare := vss.Share{Threshold: threshold, ID: share.ID, Share: share.Xi}
+		if !vssShare.Verify(ec, threshold, vs) {
+			invalid = append(invalid, fmt.Sprintf("share %s from %s", share.ID, share.File))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("⚠ %d of %d %s shares of vault %s do not match the commitments: %s. "+
+			"They are corrupted, or from another vault or reshare than the commitments",
+			len(invalid), len(shares), algorithm, vaultID, strings.Join(invalid, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitments_FixtureShares(t *testing.T) {
+	outDir := t.TempDir()
+	manifest, err := generateFixtures(fixtureOptions{OutDir: outDir, Vaults: 2, Parties: 3, Threshold: 2, EdDSA: true, V2: true})
+	require.NoError(t, err)
+	commitments, err := loadCommitments(filepath.Join(outDir, fixtureCommitmentsFile))
+	require.NoError(t, err)
+	require.Len(t, commitments, 2)
+
+	files := make([]ui.VaultsDataFile, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		files = append(files, ui.VaultsDataFile{File: file.File, Mnemonics: file.Mnemonics})
+	}
+	first, second := manifest.Vaults[0].VaultID, manifest.Vaults[1].VaultID
+	address, _, _, _, err := runTool(files, ToolOptions{VaultID: first, Commitments: commitments})
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Vaults[0].Address, address)
+
+	// the commitments of another vault pinpoint every share
+	swapped := VaultCommitments{first: commitments[second]}
+	_, ecSK, _, _, err := runTool(files, ToolOptions{VaultID: first, Commitments: swapped})
+	assert.Nil(t, ecSK)
+	if assert.ErrorContains(t, err, "3 of 3 ECDSA shares") {
+		for _, file := range manifest.Files {
+			assert.Contains(t, err.Error(), file.File)
+		}
+	}
+
+	// only the EdDSA commitments are swapped
+	swapped = VaultCommitments{first: {ECDSA: commitments[first].ECDSA, EdDSA: commitments[second].EdDSA}}
+	_, _, _, _, err = runTool(files, ToolOptions{VaultID: first, Commitments: swapped})
+	assert.ErrorContains(t, err, "EdDSA shares")
+}
+
+func TestCommitments_LoadInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "commitments.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"vault": {"eddsa": []}}`), 0600))
+	_, err := loadCommitments(file)
+	assert.ErrorContains(t, err, "no ECDSA commitments")
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"vault": {"ecdsa": [{"Curve": "secp256k1", "Coords": [1, 2]}]}}`), 0600))
+	_, err = loadCommitments(file)
+	assert.ErrorContains(t, err, "invalid commitments file")
+
+	_, err = loadCommitments(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
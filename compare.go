@@ -0,0 +1,195 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const compareCmd = "compare"
+
+type (
+	// RecoveryFingerprint holds the public facts of a recovery output, in display order. No secrets are read.
+	RecoveryFingerprint struct {
+		Source string
+		Fields []FingerprintField
+	}
+	FingerprintField struct {
+		Name, Value string
+	}
+	// FingerprintDiff is a field of two recovery outputs. A field found in only one of them is not a mismatch.
+	FingerprintDiff struct {
+		Name          string
+		First, Second string
+		Match         bool
+	}
+)
+
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet(compareCmd, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: recovery-tool %s <output1> <output2>\n\n"+
+			"Each output is a wallet v3 file written with -export, or a directory of TSS share bundles written with -export-tss-share.\n", compareCmd)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("⚠ expected exactly two recovery outputs to compare")
+	}
+	first, err := loadRecoveryFingerprint(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	second, err := loadRecoveryFingerprint(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	diffs := compareFingerprints(first, second)
+	mismatches, compared := 0, 0
+	fmt.Printf("%s%s COMPARING RECOVERY OUTPUTS %s\n\n", ui.AnsiCodes["invertOn"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	fmt.Printf("First:  %s\nSecond: %s\n\n", first.Source, second.Source)
+	for _, diff := range diffs {
+		status := ui.Plain("✓")
+		switch {
+		case diff.First == "" || diff.Second == "":
+			status = "-"
+		case !diff.Match:
+			status = ui.Plain("⚠")
+			mismatches++
+			compared++
+		default:
+			compared++
+		}
+		fmt.Printf(" [%s] %s\n       %s\n       %s\n", status, diff.Name, valueOrMissing(diff.First), valueOrMissing(diff.Second))
+	}
+	fmt.Println()
+	if compared == 0 {
+		return errors.New("⚠ the two recovery outputs have no fields in common to compare")
+	}
+	if mismatches > 0 {
+		return fmt.Errorf("⚠ the recovery outputs differ in %d of %d compared field(s)", mismatches, compared)
+	}
+	fmt.Printf("The recovery outputs match in all %d compared field(s).\n", compared)
+	return nil
+}
+
+func valueOrMissing(value string) string {
+	if value == "" {
+		return "(not in this output)"
+	}
+	return value
+}
+
+// compareFingerprints pairs the fields of both outputs by name, in the order of the first output.
+func compareFingerprints(first, second RecoveryFingerprint) []FingerprintDiff {
+	diffs := make([]FingerprintDiff, 0, len(first.Fields)+len(second.Fields))
+	index := make(map[string]int, len(first.Fields))
+	for _, field := range first.Fields {
+		index[field.Name] = len(diffs)
+		diffs = append(diffs, FingerprintDiff{Name: field.Name, First: field.Value})
+	}
+	for _, field := range second.Fields {
+		if i, ok := index[field.Name]; ok {
+			diffs[i].Second = field.Value
+			continue
+		}
+		diffs = append(diffs, FingerprintDiff{Name: field.Name, Second: field.Value})
+	}
+	for i := range diffs {
+		diffs[i].Match = diffs[i].First == diffs[i].Second
+	}
+	return diffs
+}
+
+func loadRecoveryFingerprint(path string) (RecoveryFingerprint, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return RecoveryFingerprint{}, fmt.Errorf("⚠ unable to see `%s` - does it exist?: %s", path, err)
+	}
+	if info.IsDir() {
+		return tssBundlesFingerprint(path)
+	}
+	return walletFingerprint(path)
+}
+
+// walletFingerprint reads the address of a wallet v3 file. The encrypted key differs on every export, so it is not compared.
+func walletFingerprint(file string) (RecoveryFingerprint, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return RecoveryFingerprint{}, fmt.Errorf("⚠ unable to read `%s`: %s", file, err)
+	}
+	wallet := struct {
+		Address string `json:"address"`
+	}{}
+	if err = json.Unmarshal(content, &wallet); err != nil || !common.IsHexAddress(wallet.Address) {
+		return RecoveryFingerprint{}, fmt.Errorf("⚠ `%s` is not a wallet v3 file", file)
+	}
+	return RecoveryFingerprint{Source: "wallet v3 file " + file, Fields: []FingerprintField{
+		{"Ethereum address", common.HexToAddress(wallet.Address).Hex()},
+	}}, nil
+}
+
+// tssBundlesFingerprint reads the vault's configuration and public keys from a directory of TSS share bundles.
+func tssBundlesFingerprint(dir string) (RecoveryFingerprint, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*-party-*.json"))
+	if err != nil || len(files) == 0 {
+		return RecoveryFingerprint{}, fmt.Errorf("⚠ no TSS share bundles found in `%s`", dir)
+	}
+	bundles := make([]*TSSShareBundle, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return RecoveryFingerprint{}, fmt.Errorf("⚠ unable to read `%s`: %s", file, err)
+		}
+		bundle := new(TSSShareBundle)
+		if err = json.Unmarshal(content, bundle); err != nil || bundle.ECDSA == nil || bundle.ECDSA.ECDSAPub == nil {
+			return RecoveryFingerprint{}, fmt.Errorf("⚠ `%s` is not a TSS share bundle", file)
+		}
+		bundles = append(bundles, bundle)
+	}
+	first := bundles[0]
+	for _, bundle := range bundles[1:] {
+		if bundle.VaultID != first.VaultID || !bundle.ECDSA.ECDSAPub.Equals(first.ECDSA.ECDSAPub) {
+			return RecoveryFingerprint{}, fmt.Errorf("⚠ the TSS share bundles in `%s` are not all of the same vault", dir)
+		}
+	}
+
+	_, address, err := getTSSPubKeyForEthereum(first.ECDSA.ECDSAPub.X(), first.ECDSA.ECDSAPub.Y())
+	if err != nil {
+		return RecoveryFingerprint{}, err
+	}
+	shareIDs := make([]string, 0, len(bundles))
+	for _, bundle := range bundles {
+		shareIDs = append(shareIDs, shortShareID(bundle.ShareID))
+	}
+	sort.Strings(shareIDs)
+	fields := []FingerprintField{
+		{"Vault ID", first.VaultID},
+		{"Threshold", strconv.Itoa(first.Threshold)},
+		{"Reshare nonce", strconv.Itoa(first.ReShareNonce)},
+		{"Ethereum address", address},
+		{"ECDSA public key", hex.EncodeToString(first.ECDSA.ECDSAPub.ToBtcecPubKey().SerializeCompressed())},
+	}
+	if first.EdDSA != nil && first.EdDSA.EDDSAPub != nil {
+		fields = append(fields, FingerprintField{"EdDSA public key", hex.EncodeToString(first.EdDSA.EDDSAPub.ToEdwardsPubKey().SerializeCompressed())})
+	}
+	fields = append(fields, FingerprintField{"Exported shares", strings.Join(shareIDs, ", ")})
+	return RecoveryFingerprint{Source: "TSS share bundles in " + dir, Fields: fields}, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare_TSSBundlesAndWallets(t *testing.T) {
+	vaultID := "yz5x2a7zhwwt7r0lv4gklqns"
+	files := []ui.VaultsDataFile{
+		{File: "./test-files/new_bvn.json", Mnemonics: mmNewBvn},
+		{File: "./test-files/new_x2q.json", Mnemonics: mmNewX2q},
+		{File: "./test-files/new_u44.json", Mnemonics: mmNewU44},
+	}
+	firstDir, secondDir := t.TempDir(), t.TempDir()
+	address, ecSK, _, _, err := runTool(files, ToolOptions{VaultID: vaultID, ExportTSSShareDir: firstDir})
+	require.NoError(t, err)
+	_, _, _, _, err = runTool(files, ToolOptions{VaultID: vaultID, ExportTSSShareDir: secondDir})
+	require.NoError(t, err)
+
+	assert.NoError(t, runCompare([]string{firstDir, secondDir}))
+
+	// a wallet of the same key only has the address in common
+	light := walletv3.Presets["light"]
+	sameWallet := filepath.Join(t.TempDir(), "wallet.json")
+	keyfile, err := walletv3.Encrypt(ecSK, address, "password", light)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(sameWallet, keyfile, 0600))
+	assert.NoError(t, runCompare([]string{firstDir, sameWallet}))
+
+	otherKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	_, otherAddress, err := getTSSPubKeyForEthereum(otherKey.PubKey().X(), otherKey.PubKey().Y())
+	require.NoError(t, err)
+	otherWallet := filepath.Join(t.TempDir(), "wallet.json")
+	keyfile, err = walletv3.Encrypt(otherKey.Serialize(), otherAddress, "password", light)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(otherWallet, keyfile, 0600))
+	assert.ErrorContains(t, runCompare([]string{firstDir, otherWallet}), "differ in 1 of 1")
+
+	assert.Error(t, runCompare([]string{firstDir}))
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+const (
+	versionCmd = "version"
+
+	releasesURL = "https://github.com/IoFinnet/io-vault-disaster-recovery-cli/releases"
+)
+
+// BackupCompatibility is what this version of the tool can read in backup files. Anything else was written by a newer
+// platform version, so the user is told to upgrade instead of getting a parse error.
+type BackupCompatibility struct {
+	ToolVersion string
+	// ShareFormats are the encodings of the shares: 1 is plain JSON, 2 is DEFLATE compressed with the _V2_ prefix
+	ShareFormats []int
+	Ciphers      []string
+	KDFs         []string
+}
+
+var backupCompatibility = BackupCompatibility{
+	ToolVersion:  ui.Version,
+	ShareFormats: []int{1, 2},
+	Ciphers:      []string{"aes-256-gcm"},
+	KDFs:         []string{"pbkdf2-sha256"},
+}
+
+func runVersion(args []string) error {
+	fs := flag.NewFlagSet(versionCmd, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: recovery-tool %s\n\nShows the tool version and the backup file formats it can read.\n", versionCmd)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	backupCompatibility.Print(os.Stdout)
+	return nil
+}
+
+// Print shows the tool version and the backup formats it reads.
+func (c BackupCompatibility) Print(out io.Writer) {
+	formats := make([]string, len(c.ShareFormats))
+	for i, format := range c.ShareFormats {
+		formats[i] = fmt.Sprintf("V%d", format)
+	}
+	fmt.Fprintf(out, "recovery-tool %s\n\n", c.ToolVersion)
+	fmt.Fprintf(out, "Backup share formats: %s\n", strings.Join(formats, ", "))
+	fmt.Fprintf(out, "Backup ciphers:       %s\n", strings.Join(c.Ciphers, ", "))
+	fmt.Fprintf(out, "Backup phrase KDFs:   %s\n", strings.Join(c.KDFs, ", "))
+	fmt.Fprintf(out, "\nNewer versions: %s\n", releasesURL)
+}
+
+// Check refuses a backup file written in a format newer than this tool reads, from its unencrypted fields. Older files
+// have no KDF name.
+func (c BackupCompatibility) Check(file string, saveData *SavedData) error {
+	if saveData.KDF != "" && !slices.Contains(c.KDFs, saveData.KDF) {
+		return c.upgradeError(file, fmt.Sprintf("the %s phrase KDF", saveData.KDF))
+	}
+	ciphers := make(map[string]bool)
+	for _, reshares := range saveData.Vaults {
+		for _, vault := range reshares {
+			if vault.Cipher != "" && !slices.Contains(c.Ciphers, vault.Cipher) {
+				ciphers[vault.Cipher] = true
+			}
+		}
+	}
+	if len(ciphers) > 0 {
+		names := make([]string, 0, len(ciphers))
+		for cipher := range ciphers {
+			names = append(names, cipher)
+		}
+		sort.Strings(names)
+		return c.upgradeError(file, fmt.Sprintf("the %s cipher", strings.Join(names, ", ")))
+	}
+	return nil
+}
+
+// CheckShare refuses a decrypted share in a newer encoding than this tool reads, marked with a _V<n>_ prefix.
+func (c BackupCompatibility) CheckShare(share string) error {
+	if !strings.HasPrefix(share, "_V") {
+		return nil
+	}
+	digits, _, found := strings.Cut(share[len("_V"):], "_")
+	format, err := strconv.Atoi(digits)
+	if !found || err != nil || slices.Contains(c.ShareFormats, format) {
+		return nil
+	}
+	return c.upgradeError("", fmt.Sprintf("the V%d share format", format))
+}
+
+func (c BackupCompatibility) upgradeError(file, feature string) error {
+	what := "The backup file"
+	if file != "" {
+		what = fmt.Sprintf("The backup file `%s`", file)
+	}
+	return fmt.Errorf("⚠ %s uses %s, which is newer than this tool (version %s) can read. "+
+		"Download the latest recovery tool from %s and run it again. Run \"recovery-tool %s\" to see the formats this one reads",
+		what, feature, c.ToolVersion, releasesURL, versionCmd)
+}
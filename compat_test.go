@@ -0,0 +1,57 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupCompatibility_Check(t *testing.T) {
+	for _, file := range []string{"./test-files/i.json", "./test-files/v2.json", "./test-files/new_bvn.json", "./test-files/new_single.json"} {
+		saveData, err := loadSavedData(ui.VaultsDataFile{File: file})
+		require.NoError(t, err, file)
+		assert.Equal(t, "pbkdf2-sha256", saveData.KDF, file)
+	}
+
+	legacy := &SavedData{Vaults: map[string]CipheredVaultMap{"a": {0: {Cipher: "aes-256-gcm"}}}}
+	assert.NoError(t, backupCompatibility.Check("a.json", legacy))
+
+	newKDF := &SavedData{KDF: "argon2id", Vaults: legacy.Vaults}
+	err := backupCompatibility.Check("a.json", newKDF)
+	assert.ErrorContains(t, err, "`a.json` uses the argon2id phrase KDF, which is newer than this tool (version "+ui.Version+")")
+	assert.ErrorContains(t, err, releasesURL)
+
+	newCipher := &SavedData{Vaults: map[string]CipheredVaultMap{
+		"a": {0: {Cipher: "aes-256-gcm"}, 1: {Cipher: "chacha20-poly1305"}},
+		"b": {0: {Cipher: "aes-256-gcm-siv"}},
+	}}
+	assert.ErrorContains(t, backupCompatibility.Check("a.json", newCipher), "uses the aes-256-gcm-siv, chacha20-poly1305 cipher")
+
+	_, err = loadSavedData(ui.VaultsDataFile{File: "new.json", Content: []byte(`{"kdf": "argon2id", "vaults": {}}`)})
+	assert.ErrorContains(t, err, "Download the latest recovery tool")
+}
+
+func TestBackupCompatibility_CheckShare(t *testing.T) {
+	assert.NoError(t, backupCompatibility.CheckShare(`{"Xi": 1}`))
+	assert.NoError(t, backupCompatibility.CheckShare("_V2_123_abcd"))
+	assert.NoError(t, backupCompatibility.CheckShare("_Vault_"))
+	assert.ErrorContains(t, backupCompatibility.CheckShare("_V3_123_abcd"), "uses the V3 share format")
+
+	_, err := inflateSharesForCurve[struct{}]([]string{"_V3_123_abcd"}, "vault-1", nil)
+	assert.ErrorContains(t, err, "newer than this tool")
+}
+
+func TestBackupCompatibility_Print(t *testing.T) {
+	out := new(bytes.Buffer)
+	backupCompatibility.Print(out)
+	assert.Contains(t, out.String(), "recovery-tool "+ui.Version)
+	assert.Contains(t, out.String(), "Backup share formats: V1, V2")
+	assert.Contains(t, out.String(), "Backup ciphers:       aes-256-gcm")
+}
@@ -0,0 +1,193 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+const (
+	corpusCheckCmd = "corpus-check"
+
+	// corpusManifestFile describes the backup format archived in each directory of the corpus.
+	corpusManifestFile = "corpus.json"
+)
+
+type (
+	// CorpusManifest is an archived backup format: backup files as shipped, with their test phrases, and what listing
+	// and recovering them must produce. Relative file paths are relative to the manifest.
+	CorpusManifest struct {
+		Format string        `json:"format"`
+		Note   string        `json:"note"`
+		Files  []FixtureFile `json:"files"`
+		// VaultIDs are all the vaults listing must find, in order
+		VaultIDs []string `json:"vaultIds"`
+		// Vaults are the vaults to recover, with their expected keys. The Address and EdDSA key are optional.
+		Vaults []FixtureVault `json:"vaults"`
+	}
+
+	// CorpusResult is the outcome of one format of the corpus. Skipped is set when it cannot be run without phrases.
+	CorpusResult struct {
+		Format  string
+		Dir     string
+		Checks  int
+		Skipped string
+		Err     error
+	}
+)
+
+func runCorpusCheck(args []string) error {
+	fs := flag.NewFlagSet(corpusCheckCmd, flag.ContinueOnError)
+	dir := fs.String("dir", filepath.Join("test-files", "corpus"), "Corpus directory, with one directory per backup format holding a "+corpusManifestFile+" manifest.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: recovery-tool %s [-dir test-files/corpus]\n\n"+
+			"Lists and recovers the archived backup files of every historical format in the corpus, and reports which pass.\n\nFlags:\n", corpusCheckCmd)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// the share processing messages of every format would drown the report
+	ui.ConfigureOutput(true, false)
+	results, err := checkCorpus(*dir)
+	if err != nil {
+		return err
+	}
+	return printCorpusResults(os.Stdout, results)
+}
+
+// checkCorpus runs every format of the corpus directory, in name order.
+func checkCorpus(dir string) ([]CorpusResult, error) {
+	manifests, err := filepath.Glob(filepath.Join(dir, "*", corpusManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("⚠ no `%s` manifests found in the directories of `%s`", corpusManifestFile, dir)
+	}
+	slices.Sort(manifests)
+	results := make([]CorpusResult, 0, len(manifests))
+	for _, manifest := range manifests {
+		results = append(results, checkCorpusFormat(manifest))
+	}
+	return results, nil
+}
+
+// checkCorpusFormat lists and recovers the backup files of one format. A panic while parsing them fails this format
+// only, so that the others are still reported.
+func checkCorpusFormat(manifestFile string) (result CorpusResult) {
+	dir := filepath.Dir(manifestFile)
+	result = CorpusResult{Format: filepath.Base(dir), Dir: dir}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Err = fmt.Errorf("⚠ panic: %v", r)
+		}
+	}()
+	data, err := os.ReadFile(manifestFile)
+	if err != nil {
+		result.Err = fmt.Errorf("⚠ could not read `%s`: %v", manifestFile, err)
+		return result
+	}
+	var manifest CorpusManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		result.Err = fmt.Errorf("⚠ `%s` is not a corpus manifest: %v", manifestFile, err)
+		return result
+	}
+	if manifest.Format != "" {
+		result.Format = manifest.Format
+	}
+	if len(manifest.Files) == 0 {
+		result.Err = fmt.Errorf("⚠ `%s` lists no backup files", manifestFile)
+		return result
+	}
+	files := make([]ui.VaultsDataFile, len(manifest.Files))
+	for i, file := range manifest.Files {
+		if strings.TrimSpace(file.Mnemonics) == "" {
+			result.Skipped = fmt.Sprintf("no test phrase for %s", file.File)
+			return result
+		}
+		path := filepath.FromSlash(file.File)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		files[i] = ui.VaultsDataFile{File: path, Mnemonics: file.Mnemonics}
+	}
+
+	_, _, _, listed, err := runTool(files, ToolOptions{})
+	if err != nil {
+		result.Err = fmt.Errorf("listing: %v", err)
+		return result
+	}
+	if len(manifest.VaultIDs) > 0 {
+		if ids := vaultIDsOf(listed); !slices.Equal(ids, manifest.VaultIDs) {
+			result.Err = fmt.Errorf("listing: found vaults %s, expected %s", strings.Join(ids, ", "), strings.Join(manifest.VaultIDs, ", "))
+			return result
+		}
+		result.Checks++
+	}
+
+	for _, expected := range manifest.Vaults {
+		vaultID := expected.VaultID
+		address, ecSK, edSK, _, err := runTool(files, ToolOptions{VaultID: vaultID})
+		ecHex, edHex := hex.EncodeToString(ecSK), hex.EncodeToString(edSK)
+		clear(ecSK)
+		clear(edSK)
+		switch {
+		case err != nil:
+			result.Err = fmt.Errorf("recovering %s: %v", vaultID, err)
+		case ecHex != expected.ECDSAPrivateKey:
+			result.Err = fmt.Errorf("recovering %s: wrong ECDSA private key", vaultID)
+		case expected.EdDSAPrivateKey != "" && edHex != expected.EdDSAPrivateKey:
+			result.Err = fmt.Errorf("recovering %s: wrong EdDSA private key", vaultID)
+		case expected.Address != "" && address != expected.Address:
+			result.Err = fmt.Errorf("recovering %s: recovered address %s, expected %s", vaultID, address, expected.Address)
+		}
+		if result.Err != nil {
+			return result
+		}
+		result.Checks++
+	}
+	return result
+}
+
+func vaultIDsOf(vaults []ui.VaultPickerItem) []string {
+	ids := make([]string, len(vaults))
+	for i, vault := range vaults {
+		ids[i] = vault.VaultID
+	}
+	return ids
+}
+
+// printCorpusResults prints the pass or fail of each format, and returns an error if any failed.
+func printCorpusResults(out io.Writer, results []CorpusResult) error {
+	failed := 0
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Fprintf(out, " [%s] FAIL %s\n       %s\n", ui.Plain("⚠"), result.Format, ui.Plain(result.Err.Error()))
+		case result.Skipped != "":
+			fmt.Fprintf(out, " [-] SKIP %s\n       %s\n", result.Format, result.Skipped)
+		default:
+			fmt.Fprintf(out, " [%s] PASS %s\n       %d checks passed\n", ui.Plain("✓"), result.Format, result.Checks)
+		}
+	}
+	fmt.Fprintln(out)
+	if failed > 0 {
+		return fmt.Errorf("⚠ %d of %d backup formats failed", failed, len(results))
+	}
+	fmt.Fprintf(out, "All %d backup formats passed.\n", len(results))
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCorpus(t *testing.T) {
+	results, err := checkCorpus(filepath.Join("test-files", "corpus"))
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	for _, result := range results {
+		assert.NoError(t, result.Err, result.Format)
+		assert.Empty(t, result.Skipped, result.Format)
+		assert.Equal(t, 2, result.Checks, result.Format)
+	}
+	out := new(bytes.Buffer)
+	require.NoError(t, printCorpusResults(out, results))
+	assert.Contains(t, out.String(), "PASS legacy-v1")
+	assert.Contains(t, out.String(), "All 4 backup formats passed.")
+}
+
+func TestCheckCorpus_Failures(t *testing.T) {
+	dir := t.TempDir()
+	v2, err := filepath.Abs(filepath.Join("test-files", "v2.json"))
+	require.NoError(t, err)
+	formats := map[string]string{
+		"a-wrong-key": `{"files": [{"file": "` + filepath.ToSlash(v2) + `", "mnemonics": "` + mmV2 + `"}],
+			"vaults": [{"vaultId": "yjanjbgmbrptwwa9i5v9c20x", "ecdsaPrivateKey": "00"}]}`,
+		"b-no-phrase":    `{"format": "archived", "files": [{"file": "old.json"}]}`,
+		"c-wrong-vaults": `{"files": [{"file": "` + filepath.ToSlash(v2) + `", "mnemonics": "` + mmV2 + `"}], "vaultIds": ["x"]}`,
+		"d-not-json":     `{`,
+	}
+	for format, manifest := range formats {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, format), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, format, corpusManifestFile), []byte(manifest), 0o600))
+	}
+
+	results, err := checkCorpus(dir)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.ErrorContains(t, results[0].Err, "wrong ECDSA private key")
+	assert.Equal(t, "archived", results[1].Format)
+	assert.Equal(t, "no test phrase for old.json", results[1].Skipped)
+	assert.ErrorContains(t, results[2].Err, "found vaults yjanjbgmbrptwwa9i5v9c20x, expected x")
+	assert.ErrorContains(t, results[3].Err, "is not a corpus manifest")
+
+	out := new(bytes.Buffer)
+	assert.EqualError(t, printCorpusResults(out, results), "⚠ 3 of 4 backup formats failed")
+	assert.Contains(t, out.String(), "SKIP archived")
+
+	_, err = checkCorpus(t.TempDir())
+	assert.ErrorContains(t, err, "no `corpus.json` manifests")
+}
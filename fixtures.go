@@ -0,0 +1,331 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	crypto2 "github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/crypto"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/data"
+	"github.com/binance-chain/tss-lib/common"
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/binance-chain/tss-lib/crypto/vss"
+	ecdsa_keygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	eddsa_keygen "github.com/binance-chain/tss-lib/eddsa/keygen"
+	"github.com/binance-chain/tss-lib/tss"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	genFixturesCmd = "gen-fixtures"
+
+	fixtureManifestFile    = "fixtures.json"
+	fixtureCommitmentsFile = "commitments.json"
+	fixtureVaultIDChars    = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+type (
+	// FixtureManifest describes a set of generated fixture files, and the keys that recovering them must produce.
+	FixtureManifest struct {
+		Note   string         `json:"note"`
+		Files  []FixtureFile  `json:"files"`
+		Vaults []FixtureVault `json:"vaults"`
+	}
+	FixtureFile struct {
+		File      string `json:"file"`
+		Mnemonics string `json:"mnemonics"`
+	}
+	FixtureVault struct {
+		VaultID         string `json:"vaultId"`
+		Name            string `json:"name"`
+		Threshold       int    `json:"threshold"`
+		Parties         int    `json:"parties"`
+		V2              bool   `json:"v2"`
+		Address         string `json:"address"`
+		ECDSAPrivateKey string `json:"ecdsaPrivateKey"`
+		EdDSAPrivateKey string `json:"eddsaPrivateKey,omitempty"`
+	}
+
+	fixtureOptions struct {
+		OutDir    string
+		Vaults    int
+		Parties   int
+		Threshold int
+		EdDSA     bool
+		V2        bool
+		// MnemonicSeed derives the phrases of the files like gen-mnemonic -seed, instead of at random
+		MnemonicSeed string
+	}
+)
+
+func runGenFixtures(args []string) error {
+	fs := flag.NewFlagSet(genFixturesCmd, flag.ContinueOnError)
+	outDir := fs.String("out", "fixtures", "Directory to write the fixture files and manifest to.")
+	vaults := fs.Int("vaults", 1, "Number of vaults to generate.")
+	parties := fs.Int("parties", 3, "Number of parties (backup files) holding a share of each vault.")
+	threshold := fs.Int("threshold", 2, "Vault quorum (threshold): the number of shares needed to recover each vault.")
+	curves := fs.String("curves", "ecdsa,eddsa", "Comma separated curves to generate shares for. ECDSA is always required.")
+	v2 := fs.Bool("v2", true, "Compress the shares with the V2 (DEFLATE) format.")
+	mnemonicSeed := fs.String("mnemonic-seed", "", "Derive the phrases of the files from this seed, as gen-mnemonic -seed does, instead of at random. The keys are still random.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := fixtureOptions{
+		OutDir:       *outDir,
+		Vaults:       *vaults,
+		Parties:      *parties,
+		Threshold:    *threshold,
+		V2:           *v2,
+		MnemonicSeed: *mnemonicSeed,
+	}
+	hasECDSA := false
+	for _, curve := range strings.Split(*curves, ",") {
+		switch strings.ToUpper(strings.TrimSpace(curve)) {
+		case "ECDSA":
+			hasECDSA = true
+		case "EDDSA":
+			opts.EdDSA = true
+		default:
+			return fmt.Errorf("⚠ unknown curve `%s`, expected ecdsa or eddsa", curve)
+		}
+	}
+	if !hasECDSA {
+		return errors.New("⚠ the ecdsa curve is required in -curves")
+	}
+
+	manifest, err := generateFixtures(opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n\n", manifest.Note)
+	for _, file := range manifest.Files {
+		fmt.Printf("%s\n  %s\n", file.File, file.Mnemonics)
+	}
+	fmt.Printf("\nWrote %d vaults over %d fixture files. Expected keys are in: %s. Share commitments are in: %s.\n",
+		len(manifest.Vaults), len(manifest.Files), filepath.Join(opts.OutDir, fixtureManifestFile), filepath.Join(opts.OutDir, fixtureCommitmentsFile))
+	return nil
+}
+
+// generateFixtures creates synthetic backup files for brand-new random keys, one file per party, protected by freshly generated mnemonics.
+// The shares only contain what recovery needs: the Paillier and NTilde pre-params are left out, so they are unusable for signing.
+func generateFixtures(opts fixtureOptions) (*FixtureManifest, error) {
+	if opts.Vaults < 1 || opts.Parties < 1 {
+		return nil, errors.New("⚠ at least one vault and one party are required")
+	}
+	if opts.Threshold < 1 || opts.Threshold > opts.Parties {
+		return nil, fmt.Errorf("⚠ threshold must be between 1 and the number of parties (%d)", opts.Parties)
+	}
+	if err := os.MkdirAll(opts.OutDir, 0700); err != nil {
+		return nil, fmt.Errorf("⚠ could not create the fixtures directory `%s`: %v", opts.OutDir, err)
+	}
+
+	manifest := &FixtureManifest{
+		Note:   "TEST FIXTURES ONLY. These keys and mnemonics are not secret and must never hold funds.",
+		Files:  make([]FixtureFile, opts.Parties),
+		Vaults: make([]FixtureVault, 0, opts.Vaults),
+	}
+	commitments := make(VaultCommitments, opts.Vaults)
+	aesKeys := make([][]byte, opts.Parties)
+	savedDatas := make([]*SavedData, opts.Parties)
+	for i := range savedDatas {
+		var err error
+		if aesKeys[i], err = testMnemonicEntropy(opts.MnemonicSeed, i); err != nil {
+			return nil, err
+		}
+		mnemonics, err := bip39.NewMnemonic(aesKeys[i])
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files[i] = FixtureFile{File: filepath.Join(opts.OutDir, fmt.Sprintf("fixture-party-%d.json", i+1)), Mnemonics: mnemonics}
+		savedDatas[i] = &SavedData{Timestamp: time.Now().UTC().Format(time.RFC3339Nano), Vaults: make(map[string]CipheredVaultMap, opts.Vaults)}
+		// as in newer exports, so that phrases can be verified and paired without decrypting the vaults
+		mnemonicCheck, err := encryptFixtureVault(aesKeys[i], []byte(fmt.Sprintf("fixture-party-%d", i+1)))
+		if err != nil {
+			return nil, err
+		}
+		savedDatas[i].MnemonicCheck = &mnemonicCheck
+	}
+
+	for v := 0; v < opts.Vaults; v++ {
+		vID, err := randomFixtureVaultID()
+		if err != nil {
+			return nil, err
+		}
+		fixtureVault := FixtureVault{
+			VaultID:   vID,
+			Name:      fmt.Sprintf("Fixture Vault %d", v+1),
+			Threshold: opts.Threshold,
+			Parties:   opts.Parties,
+			V2:        opts.V2,
+		}
+
+		// ECDSA
+		ecdsaSK, vsECDSA, sharesECDSA, err := fixtureShares(tss.S256(), opts)
+		if err != nil {
+			return nil, err
+		}
+		fixtureVault.ECDSAPrivateKey = hex.EncodeToString(leftPadTo32Bytes(ecdsaSK))
+		if _, fixtureVault.Address, err = getTSSPubKeyForEthereum(vsECDSA[0].X(), vsECDSA[0].Y()); err != nil {
+			return nil, err
+		}
+		ecdsaPK := hex.EncodeToString(vsECDSA[0].ToBtcecPubKey().SerializeUncompressed())
+		partySharesECDSA := make([]string, opts.Parties)
+		for i := range sharesECDSA {
+			saveData := ecdsa_keygen.NewLocalPartySaveData(opts.Parties)
+			saveData.Xi, saveData.ShareID = sharesECDSA[i].Share, sharesECDSA[i].ID
+			for j, share := range sharesECDSA {
+				saveData.Ks[j] = share.ID
+				saveData.BigXj[j] = crypto.ScalarBaseMult(tss.S256(), share.Share)
+			}
+			saveData.ECDSAPub = vsECDSA[0]
+			if partySharesECDSA[i], err = encodeFixtureShare(saveData.ShareID, saveData, opts.V2); err != nil {
+				return nil, err
+			}
+		}
+
+		vaultCommitments := CurveCommitments{ECDSA: vsECDSA}
+
+		// EDDSA
+		var partySharesEDDSA []string
+		var eddsaPK string
+		if opts.EdDSA {
+			eddsaSK, vsEDDSA, sharesEDDSA, err := fixtureShares(tss.Edwards(), opts)
+			if err != nil {
+				return nil, err
+			}
+			fixtureVault.EdDSAPrivateKey = hex.EncodeToString(leftPadTo32Bytes(eddsaSK))
+			eddsaPK = hex.EncodeToString(vsEDDSA[0].ToEdwardsPubKey().SerializeCompressed())
+			vaultCommitments.EdDSA = vsEDDSA
+			partySharesEDDSA = make([]string, opts.Parties)
+			for i := range sharesEDDSA {
+				saveData := eddsa_keygen.NewLocalPartySaveData(opts.Parties)
+				saveData.Xi, saveData.ShareID = sharesEDDSA[i].Share, sharesEDDSA[i].ID
+				for j, share := range sharesEDDSA {
+					saveData.Ks[j] = share.ID
+					saveData.BigXj[j] = crypto.ScalarBaseMult(tss.Edwards(), share.Share)
+				}
+				saveData.EDDSAPub = vsEDDSA[0]
+				if partySharesEDDSA[i], err = encodeFixtureShare(saveData.ShareID, saveData, opts.V2); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		// encrypt each party's view of the vault into its own file
+		for i, savedData := range savedDatas {
+			clearVault := ClearVault{
+				Name:   fixtureVault.Name,
+				Quroum: opts.Threshold,
+				Curves: []ClearVaultCurve{{Algorithm: "ECDSA", Curve: "Secp256k1", PublicKey: ecdsaPK, Shares: []string{partySharesECDSA[i]}}},
+			}
+			if opts.EdDSA {
+				clearVault.Curves = append(clearVault.Curves, ClearVaultCurve{Algorithm: "EDDSA", Curve: "Edwards", PublicKey: eddsaPK, Shares: []string{partySharesEDDSA[i]}})
+			}
+			plainload, err := json.Marshal(clearVault)
+			if err != nil {
+				return nil, err
+			}
+			cipheredVault, err := encryptFixtureVault(aesKeys[i], plainload)
+			if err != nil {
+				return nil, err
+			}
+			savedData.Vaults[vID] = CipheredVaultMap{0: cipheredVault}
+		}
+		manifest.Vaults = append(manifest.Vaults, fixtureVault)
+		commitments[vID] = vaultCommitments
+	}
+
+	for i, savedData := range savedDatas {
+		bz, err := json.MarshalIndent(savedData, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err = os.WriteFile(manifest.Files[i].File, bz, 0600); err != nil {
+			return nil, fmt.Errorf("⚠ could not write the fixture file `%s`: %v", manifest.Files[i].File, err)
+		}
+	}
+	bz, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(opts.OutDir, fixtureManifestFile), bz, 0600); err != nil {
+		return nil, fmt.Errorf("⚠ could not write the fixture manifest: %v", err)
+	}
+	if bz, err = json.MarshalIndent(commitments, "", "  "); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(opts.OutDir, fixtureCommitmentsFile), bz, 0600); err != nil {
+		return nil, fmt.Errorf("⚠ could not write the fixture commitments: %v", err)
+	}
+	return manifest, nil
+}
+
+// fixtureShares splits a new random secret key into one VSS share per party.
+func fixtureShares(ec elliptic.Curve, opts fixtureOptions) (*big.Int, vss.Vs, vss.Shares, error) {
+	secret := common.GetRandomPositiveInt(ec.Params().N)
+	ids := make([]*big.Int, opts.Parties)
+	for i := range ids {
+		ids[i] = common.GetRandomPositiveInt(ec.Params().N)
+	}
+	vs, shares, err := vss.Create(ec, opts.Threshold-1, secret, ids)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return secret, vs, shares, nil
+}
+
+// encodeFixtureShare encodes a share's save data as JSON, or in the compressed "V2" format that inflateSharesForCurve reads.
+func encodeFixtureShare(shareID *big.Int, saveData any, v2 bool) (string, error) {
+	bz, err := json.Marshal(saveData)
+	if err != nil {
+		return "", err
+	}
+	if !v2 {
+		return string(bz), nil
+	}
+	deflated, err := data.DeflateSaveDataJSON(bz)
+	if err != nil {
+		return "", err
+	}
+	return v2MagicPrefix + shareID.String() + "_" + base64.StdEncoding.EncodeToString(deflated), nil
+}
+
+// encryptFixtureVault produces the AES-256-GCM envelope of a vault, as found in real backup files.
+func encryptFixtureVault(aesKey32, plainload []byte) (CipheredVault, error) {
+	env, err := crypto2.SealEnvelope(aesKey32, plainload)
+	if err != nil {
+		return CipheredVault{}, err
+	}
+	return CipheredVault{
+		CipherTextB64: env.CipherTextB64,
+		CipherParams:  CipherParams{IV: env.IV, Tag: env.Tag},
+		Cipher:        "aes-256-gcm",
+		Hash:          env.Hash,
+	}, nil
+}
+
+func randomFixtureVaultID() (string, error) {
+	bz := make([]byte, 24)
+	if _, err := rand.Read(bz); err != nil {
+		return "", err
+	}
+	for i := range bz {
+		bz[i] = fixtureVaultIDChars[int(bz[i])%len(fixtureVaultIDChars)]
+	}
+	return string(bz), nil
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixtures_GenerateAndRecover(t *testing.T) {
+	for _, v2 := range []bool{true, false} {
+		opts := fixtureOptions{OutDir: t.TempDir(), Vaults: 2, Parties: 3, Threshold: 2, EdDSA: true, V2: v2}
+		manifest, err := generateFixtures(opts)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if !assert.Len(t, manifest.Files, 3) || !assert.Len(t, manifest.Vaults, 2) {
+			return
+		}
+
+		// a quorum of the files is enough to recover
+		files := make([]ui.VaultsDataFile, 0, opts.Threshold)
+		for _, file := range manifest.Files[:opts.Threshold] {
+			files = append(files, ui.VaultsDataFile{File: file.File, Mnemonics: file.Mnemonics})
+		}
+		_, _, _, vaultsFormData, err := runTool(files, ToolOptions{})
+		if !assert.NoError(t, err) || !assert.Len(t, vaultsFormData, 2) {
+			return
+		}
+		for _, vault := range manifest.Vaults {
+			address, ecSK, edSK, _, err := runTool(files, ToolOptions{VaultID: vault.VaultID})
+			if !assert.NoError(t, err) {
+				return
+			}
+			if !assert.Equal(t, vault.Address, address) ||
+				!assert.Equal(t, vault.ECDSAPrivateKey, hex.EncodeToString(ecSK)) ||
+				!assert.Equal(t, vault.EdDSAPrivateKey, hex.EncodeToString(edSK)) {
+				return
+			}
+		}
+	}
+}
+
+func TestFixtures_BadThreshold(t *testing.T) {
+	_, err := generateFixtures(fixtureOptions{OutDir: t.TempDir(), Vaults: 1, Parties: 2, Threshold: 3})
+	assert.Error(t, err)
+}
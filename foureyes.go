@@ -0,0 +1,54 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// Operator is one of the two people who must both confirm a -four-eyes recovery before any secret is shown or written.
+type Operator struct {
+	Name       string
+	Passphrase []byte
+}
+
+// newOperators checks that the two operators set at session start are really two people with their own passphrases.
+func newOperators(first, second Operator) ([]Operator, error) {
+	if strings.EqualFold(strings.TrimSpace(first.Name), strings.TrimSpace(second.Name)) {
+		return nil, fmt.Errorf("⚠ the two operators must be different people, but both are named %q", strings.TrimSpace(first.Name))
+	}
+	if subtle.ConstantTimeCompare(first.Passphrase, second.Passphrase) == 1 {
+		return nil, fmt.Errorf("⚠ the two operators must choose different passphrases")
+	}
+	return []Operator{first, second}, nil
+}
+
+// verifyOperators checks the passphrases entered by the operators before the keys are recovered, in order. The
+// operator whose passphrase is wrong is named, so that the other one cannot simply try again for them.
+func verifyOperators(operators []Operator, entered [][]byte) error {
+	if len(entered) != len(operators) {
+		return fmt.Errorf("⚠ both operators must confirm the recovery")
+	}
+	for i, operator := range operators {
+		if subtle.ConstantTimeCompare(operator.Passphrase, entered[i]) != 1 {
+			return fmt.Errorf("⚠ the passphrase of operator %s is wrong, so the recovery was not confirmed", operator.Name)
+		}
+	}
+	return nil
+}
+
+// fourEyesAcknowledgement is the line printed once both operators confirmed, so that saved logs of the session record it.
+func fourEyesAcknowledgement(operators []Operator, at time.Time) string {
+	names := make([]string, 0, len(operators))
+	for _, operator := range operators {
+		names = append(names, operator.Name)
+	}
+	return fmt.Sprintf("Recovery confirmed by operators %s at %s in session %s.", strings.Join(names, " and "), at.UTC().Format(time.RFC3339), ui.SessionID)
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFourEyes(t *testing.T) {
+	alice := Operator{"Alice", []byte("alice passphrase")}
+	bob := Operator{"Bob", []byte("bob passphrase")}
+
+	_, err := newOperators(alice, Operator{" alice ", []byte("other passphrase")})
+	assert.ErrorContains(t, err, "different people")
+	_, err = newOperators(alice, Operator{"Bob", []byte("alice passphrase")})
+	assert.ErrorContains(t, err, "different passphrases")
+
+	operators, err := newOperators(alice, bob)
+	require.NoError(t, err)
+	assert.NoError(t, verifyOperators(operators, [][]byte{[]byte("alice passphrase"), []byte("bob passphrase")}))
+	assert.ErrorContains(t, verifyOperators(operators, [][]byte{[]byte("alice passphrase"), []byte("alice passphrase")}), "operator Bob")
+	assert.ErrorContains(t, verifyOperators(operators, [][]byte{[]byte("bob passphrase"), []byte("alice passphrase")}), "operator Alice")
+	assert.Error(t, verifyOperators(operators, [][]byte{[]byte("alice passphrase")}))
+
+	ack := fourEyesAcknowledgement(operators, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "Recovery confirmed by operators Alice and Bob at 2024-05-01T12:00:00Z in session "+ui.SessionID+".", ack)
+}
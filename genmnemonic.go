@@ -0,0 +1,82 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"flag"
+	"fmt"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	genMnemonicCmd = "gen-mnemonic"
+
+	// testMnemonicKey is the HMAC key deriving the entropy of the test phrases of a seed
+	testMnemonicKey  = "io.finnet test mnemonic"
+	testMnemonicNote = "TEST ONLY. These phrases are for fixtures, drills and training: never protect real backups or funds with them."
+	maxTestMnemonics = 1000
+)
+
+func runGenMnemonic(args []string) error {
+	fs := flag.NewFlagSet(genMnemonicCmd, flag.ContinueOnError)
+	count := fs.Int("count", 1, "Number of phrases to generate.")
+	seed := fs.String("seed", "", "Derive the phrases from this seed instead of at random, to generate the same phrases again, e.g. for a training class. Anyone with the seed has the phrases.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	phrases, err := generateTestMnemonics(*count, *seed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n\n", testMnemonicNote)
+	for i, phrase := range phrases {
+		fmt.Printf("%d. %s\n", i+1, phrase)
+	}
+	if *seed != "" {
+		fmt.Printf("\nDerived from the seed: gen-fixtures -mnemonic-seed with the same seed protects its files with these phrases, in order.\n")
+	}
+	return nil
+}
+
+// generateTestMnemonics generates 24 word BIP39 test phrases, at random or derived from the seed.
+func generateTestMnemonics(count int, seed string) ([]string, error) {
+	if count < 1 || count > maxTestMnemonics {
+		return nil, fmt.Errorf("⚠ -count must be between 1 and %d", maxTestMnemonics)
+	}
+	phrases := make([]string, count)
+	for i := range phrases {
+		entropy, err := testMnemonicEntropy(seed, i)
+		if err != nil {
+			return nil, err
+		}
+		phrases[i], err = bip39.NewMnemonic(entropy)
+		clear(entropy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return phrases, nil
+}
+
+// testMnemonicEntropy is the 32 bytes of entropy of the test phrase at the index: random without a seed, or else
+// HMAC-SHA256(key=testMnemonicKey, msg=seed || index as 4 big endian bytes).
+func testMnemonicEntropy(seed string, index int) ([]byte, error) {
+	if seed == "" {
+		entropy := make([]byte, 32)
+		if _, err := rand.Read(entropy); err != nil {
+			return nil, err
+		}
+		return entropy, nil
+	}
+	mac := hmac.New(sha256.New, []byte(testMnemonicKey))
+	mac.Write([]byte(seed))
+	mac.Write(binary.BigEndian.AppendUint32(nil, uint32(index)))
+	return mac.Sum(nil), nil
+}
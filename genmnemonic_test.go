@@ -0,0 +1,53 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tyler-smith/go-bip39"
+)
+
+func TestGenerateTestMnemonics(t *testing.T) {
+	random, err := generateTestMnemonics(3, "")
+	require.NoError(t, err)
+	require.Len(t, random, 3)
+	for _, phrase := range random {
+		assert.True(t, bip39.IsMnemonicValid(phrase))
+		assert.Len(t, strings.Fields(phrase), ui.WORDS)
+	}
+	assert.NotEqual(t, random[0], random[1])
+
+	// the same seed always gives the same phrases, and each index its own
+	seeded, err := generateTestMnemonics(2, "training-2025")
+	require.NoError(t, err)
+	again, err := generateTestMnemonics(2, "training-2025")
+	require.NoError(t, err)
+	assert.Equal(t, seeded, again)
+	assert.NotEqual(t, seeded[0], seeded[1])
+	other, err := generateTestMnemonics(1, "training-2026")
+	require.NoError(t, err)
+	assert.NotEqual(t, seeded[0], other[0])
+
+	_, err = generateTestMnemonics(0, "")
+	assert.ErrorContains(t, err, "-count")
+}
+
+func TestGenerateFixtures_MnemonicSeed(t *testing.T) {
+	phrases, err := generateTestMnemonics(2, "drill")
+	require.NoError(t, err)
+	manifest, err := generateFixtures(fixtureOptions{OutDir: t.TempDir(), Vaults: 1, Parties: 2, Threshold: 2, MnemonicSeed: "drill"})
+	require.NoError(t, err)
+	assert.Equal(t, phrases[0], manifest.Files[0].Mnemonics)
+	assert.Equal(t, phrases[1], manifest.Files[1].Mnemonics)
+
+	vaults, err := checkMnemonics(ui.VaultsDataFile{File: manifest.Files[1].File, Mnemonics: phrases[1]})
+	require.NoError(t, err)
+	assert.Equal(t, 1, vaults)
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	_ "embed"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	helpGuideCmd = "help-guide"
+
+	// maxGuideWidth keeps the guide readable on wide terminals
+	maxGuideWidth = 100
+)
+
+// userGuide is the README, embedded so that the guide can be read on an air-gapped machine.
+//
+//go:embed README.md
+var userGuide string
+
+// GuideTopic is a section of the user guide, from a ## or ### heading to the next one.
+type GuideTopic struct {
+	Slug  string
+	Title string
+	Body  string
+}
+
+var (
+	guideLink  = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	guideImage = regexp.MustCompile(`!\[([^\]]*)\]\([^)]+\)`)
+	guideBold  = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	guideCode  = regexp.MustCompile("`([^`]+)`")
+	guideSlug  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func runHelpGuide(args []string) error {
+	fs := flag.NewFlagSet(helpGuideCmd, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: recovery-tool %s [topic]\n\n"+
+			"Shows the user guide offline. Without a topic, lists the topics. A topic can be shortened to the start of its words, e.g. \"bitcoin\" or \"xrp\".\n", helpGuideCmd)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	topics := guideTopics(userGuide)
+	width := maxGuideWidth
+	if w, _, err := term.GetSize(os.Stdout.Fd()); err == nil && w > 0 {
+		width = min(w, maxGuideWidth)
+	}
+	if fs.NArg() == 0 {
+		printGuideTopics(os.Stdout, topics)
+		return nil
+	}
+	query := strings.Join(fs.Args(), " ")
+	matches := findGuideTopics(topics, query)
+	switch len(matches) {
+	case 0:
+		printGuideTopics(os.Stdout, topics)
+		return fmt.Errorf("⚠ no topic of the guide matches %q", query)
+	case 1:
+		fmt.Print(renderGuide(matches[0], width))
+	default:
+		fmt.Printf("Several topics match %q:\n\n", query)
+		printGuideTopics(os.Stdout, matches)
+	}
+	return nil
+}
+
+// guideTopics splits the guide into its sections. The text before the first heading is the introduction.
+func guideTopics(guide string) []GuideTopic {
+	topics := []GuideTopic{{Slug: "introduction", Title: "Introduction"}}
+	var body strings.Builder
+	inCode := false
+	for _, line := range strings.Split(guide, "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+		title, isHeading := strings.CutPrefix(line, "### ")
+		if !isHeading {
+			title, isHeading = strings.CutPrefix(line, "## ")
+		}
+		if isHeading && !inCode {
+			topics[len(topics)-1].Body = strings.TrimSpace(body.String())
+			body.Reset()
+			title = strings.TrimSpace(title)
+			topics = append(topics, GuideTopic{Slug: strings.Trim(guideSlug.ReplaceAllString(strings.ToLower(title), "-"), "-"), Title: title})
+			continue
+		}
+		if strings.HasPrefix(line, "# ") && !inCode {
+			continue
+		}
+		body.WriteString(line + "\n")
+	}
+	topics[len(topics)-1].Body = strings.TrimSpace(body.String())
+	return topics
+}
+
+// findGuideTopics returns the topic with this exact slug, or else the topics with a title word starting with each word
+// of the query.
+func findGuideTopics(topics []GuideTopic, query string) []GuideTopic {
+	query = strings.ToLower(strings.TrimSpace(query))
+	for _, topic := range topics {
+		if topic.Slug == query {
+			return []GuideTopic{topic}
+		}
+	}
+	var matches []GuideTopic
+	for _, topic := range topics {
+		words := strings.Fields(guideSlug.ReplaceAllString(strings.ToLower(topic.Title), " "))
+		matched := true
+		for _, q := range strings.Fields(guideSlug.ReplaceAllString(query, " ")) {
+			found := false
+			for _, word := range words {
+				if strings.HasPrefix(word, q) {
+					found = true
+					break
+				}
+			}
+			matched = matched && found
+		}
+		if matched {
+			matches = append(matches, topic)
+		}
+	}
+	return matches
+}
+
+func printGuideTopics(out io.Writer, topics []GuideTopic) {
+	width := 0
+	for _, topic := range topics {
+		width = max(width, len(topic.Slug))
+	}
+	for _, topic := range topics {
+		fmt.Fprintf(out, "  %-*s  %s\n", width, topic.Slug, topic.Title)
+	}
+	fmt.Fprintf(out, "\nShow a topic with: recovery-tool %s <topic>\n", helpGuideCmd)
+}
+
+// renderGuide renders the markdown of a topic for the terminal: headings and emphasis in bold, links with their URL
+// spelled out, images by their description, code blocks indented and paragraphs wrapped to the width.
+func renderGuide(topic GuideTopic, width int) string {
+	bold := lipgloss.NewStyle().Bold(true)
+	var out strings.Builder
+	out.WriteString(bold.Underline(true).Render(topic.Title) + "\n\n")
+	inCode := false
+	for _, line := range strings.Split(topic.Body, "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out.WriteString("    " + line + "\n")
+			continue
+		}
+		prefix := ""
+		if quoted, ok := strings.CutPrefix(line, ">"); ok {
+			prefix, line = "│ ", strings.TrimPrefix(quoted, " ")
+		}
+		if heading, ok := strings.CutPrefix(line, "### "); ok {
+			line = bold.Render(heading)
+		}
+		line = guideImage.ReplaceAllString(line, "[image: $1]")
+		line = guideLink.ReplaceAllString(line, "$1 ($2)")
+		line = guideBold.ReplaceAllStringFunc(line, func(s string) string { return bold.Render(guideBold.FindStringSubmatch(s)[1]) })
+		line = guideCode.ReplaceAllStringFunc(line, func(s string) string { return bold.Render(guideCode.FindStringSubmatch(s)[1]) })
+		if line == "" {
+			out.WriteString(strings.TrimRight(prefix, " ") + "\n")
+			continue
+		}
+		wrapped := lipgloss.NewStyle().Width(width - len([]rune(prefix))).Render(line)
+		for _, w := range strings.Split(wrapped, "\n") {
+			out.WriteString(prefix + strings.TrimRight(w, " ") + "\n")
+		}
+	}
+	return out.String()
+}
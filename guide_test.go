@@ -0,0 +1,77 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuideTopics(t *testing.T) {
+	topics := guideTopics(userGuide)
+	require.Greater(t, len(topics), 10)
+	assert.Equal(t, "introduction", topics[0].Slug)
+	assert.Contains(t, topics[0].Body, "air gapped")
+
+	slugs := make([]string, len(topics))
+	for i, topic := range topics {
+		slugs[i] = topic.Slug
+		assert.NotEmpty(t, topic.Body, topic.Title)
+	}
+	for _, slug := range []string{"bitcoin-recovery", "tron-recovery", "xrp-ledger-recovery", "ethereum-ethereum-like-recovery", "others-sol-ton-tao-etc"} {
+		assert.Contains(t, slugs, slug)
+	}
+
+	for query, title := range map[string]string{
+		"bitcoin":          "Bitcoin Recovery",
+		"XRP":              "XRP Ledger Recovery",
+		"eth":              "Ethereum & Ethereum-Like Recovery",
+		"sol":              "Others (SOL, TON, TAO, etc.)",
+		"tron-recovery":    "Tron Recovery",
+		"zcash horizen":    "Zcash, Horizen & Komodo Recovery",
+		"strict-writes":    "Strict Writes",
+		"pem openssl":      "PEM Keys for OpenSSL and HSMs",
+		"usage":            "Usage",
+		"cloud synced":     "Cloud Synced Folders",
+		"screen":           "Screen Lock",
+		"showing private":  "Showing the Private Keys",
+		"historical":       "Historical Format Corpus",
+		"bitcoin wallet":   "",
+		"backup-format-co": "Backup Format Compatibility",
+	} {
+		matches := findGuideTopics(topics, query)
+		if title == "" {
+			assert.Empty(t, matches, query)
+			continue
+		}
+		require.Len(t, matches, 1, query)
+		assert.Equal(t, title, matches[0].Title, query)
+	}
+	assert.Greater(t, len(findGuideTopics(topics, "recovery")), 3)
+	assert.Empty(t, findGuideTopics(topics, "monero wallet"))
+}
+
+func TestRenderGuide(t *testing.T) {
+	topic := GuideTopic{Title: "Example", Body: "See the [Releases area](https://example.com/releases) and run `tool -x`.\n\n" +
+		"![Screenshot](https://example.com/a.png)\n\n> **Important**: read this\n\n```\n$ tool -x file.json\n```\n\n" + strings.Repeat("word ", 40)}
+	rendered := renderGuide(topic, 60)
+	assert.Contains(t, rendered, "Example\n\n")
+	assert.Contains(t, rendered, "See the Releases area (https://example.com/releases) and run\ntool -x.")
+	assert.Contains(t, rendered, "[image: Screenshot]")
+	assert.Contains(t, rendered, "│ Important: read this")
+	assert.Contains(t, rendered, "\n    $ tool -x file.json\n")
+	assert.NotContains(t, rendered, "```")
+	for _, line := range strings.Split(rendered, "\n") {
+		assert.LessOrEqual(t, len(line), 60, line)
+	}
+
+	out := new(bytes.Buffer)
+	printGuideTopics(out, []GuideTopic{{Slug: "a", Title: "A"}, {Slug: "long-slug", Title: "Long"}})
+	assert.Contains(t, out.String(), "  a          A\n  long-slug  Long\n")
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package address encodes recovered public keys as the addresses of the chains the vaults are used on.
+// Only public keys are taken, so nothing here can leak a private key.
+package address
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // needed for Bitcoin and XRPL hash160
+)
+
+const (
+	tronVersion = 0x41
+	xrplVersion = 0x00
+	// xrplEd25519Prefix marks an Ed25519 public key in XRPL, as opposed to a secp256k1 one
+	xrplEd25519Prefix = 0xED
+)
+
+// Hash160 is RIPEMD-160 of SHA-256, the key hash of Bitcoin-like and XRPL addresses and of P2WPKH outputs.
+func Hash160(b []byte) []byte {
+	sha := sha256.Sum256(b)
+	h := ripemd160.New()
+	h.Write(sha[:])
+	return h.Sum(nil)
+}
+
+// Tron returns the Tron address of the 20 byte Ethereum address of the same key.
+func Tron(ethAddress []byte) string {
+	return base58Check([]byte{tronVersion}, ethAddress, bitcoinAlphabet)
+}
+
+// BitcoinP2WPKH returns the native SegWit (bc1/tb1) address of a compressed secp256k1 public key, as Electrum
+// uses for a WIF imported with the p2wpkh: prefix.
+func BitcoinP2WPKH(compressedPK []byte, testnet bool) string {
+	hrp := "bc"
+	if testnet {
+		hrp = "tb"
+	}
+	return segwit(hrp, 0, Hash160(compressedPK))
+}
+
+// Cosmos returns the bech32 address of a compressed secp256k1 public key on a Cosmos SDK chain, whose human-readable
+// part is e.g. cosmos, osmo or celestia. It is the same 20 byte key hash on every chain.
+func Cosmos(compressedPK []byte, hrp string) string {
+	return bech32(hrp, convertBits(Hash160(compressedPK), 8, 5), bech32Const)
+}
+
+// ValidHRP reports whether hrp can prefix a bech32 address: 1 to 83 lowercase letters and digits.
+func ValidHRP(hrp string) bool {
+	if len(hrp) < 1 || len(hrp) > 83 {
+		return false
+	}
+	for _, c := range hrp {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// BitcoinP2TR returns the Taproot (bc1p/tb1p) address of a compressed secp256k1 public key, with the key as the
+// internal key and no script tree (BIP-86), as wallets derive for a WIF imported as a tr() descriptor.
+func BitcoinP2TR(compressedPK []byte, testnet bool) (string, error) {
+	hrp := "bc"
+	if testnet {
+		hrp = "tb"
+	}
+	pk, err := secp256k1.ParsePubKey(compressedPK)
+	if err != nil {
+		return "", err
+	}
+	// the internal key is the point with the same x and an even y
+	var internal, tweakG, output secp256k1.JacobianPoint
+	pk.AsJacobian(&internal)
+	if internal.Y.IsOdd() {
+		internal.Y.Negate(1).Normalize()
+	}
+	internalX := internal.X.Bytes()
+	var tweak secp256k1.ModNScalar
+	if overflow := tweak.SetByteSlice(taggedHash("TapTweak", internalX[:])); overflow {
+		return "", errors.New("the taproot tweak of the public key is out of range")
+	}
+	secp256k1.ScalarBaseMultNonConst(&tweak, &tweakG)
+	secp256k1.AddNonConst(&internal, &tweakG, &output)
+	if output.Z.IsZero() {
+		return "", errors.New("the taproot output key of the public key is the point at infinity")
+	}
+	output.ToAffine()
+	outputX := output.X.Bytes()
+	return segwit(hrp, 1, outputX[:]), nil
+}
+
+// taggedHash is the tagged hash of BIP-340: SHA-256(SHA-256(tag) || SHA-256(tag) || msg).
+func taggedHash(tag string, msg []byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// UTXOChain is a Bitcoin derivative with pay-to-public-key-hash addresses and WIF private keys, which only differ from
+// Bitcoin's in their version bytes.
+type UTXOChain struct {
+	Name         string
+	P2PKHVersion []byte
+	WIFVersion   []byte
+}
+
+// UTXOChains are the Bitcoin derivatives that the vault ECDSA key can be used on as is.
+var UTXOChains = []UTXOChain{
+	{Name: "Zcash transparent (t1)", P2PKHVersion: []byte{0x1c, 0xb8}, WIFVersion: []byte{0x80}},
+	{Name: "Horizen transparent (zn)", P2PKHVersion: []byte{0x20, 0x89}, WIFVersion: []byte{0x80}},
+	{Name: "Komodo", P2PKHVersion: []byte{0x3c}, WIFVersion: []byte{0xbc}},
+}
+
+// P2PKH returns the pay-to-public-key-hash address of a compressed secp256k1 public key on the chain.
+func (c UTXOChain) P2PKH(compressedPK []byte) string {
+	return base58Check(c.P2PKHVersion, Hash160(compressedPK), bitcoinAlphabet)
+}
+
+// Solana returns the Solana address of a 32 byte Ed25519 public key.
+func Solana(edPK []byte) string {
+	return base58(edPK, bitcoinAlphabet)
+}
+
+// XRPL returns the XRP Ledger classic address of a 32 byte Ed25519 public key.
+func XRPL(edPK []byte) string {
+	return base58Check([]byte{xrplVersion}, Hash160(append([]byte{xrplEd25519Prefix}, edPK...)), rippleAlphabet)
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestBitcoinP2WPKH(t *testing.T) {
+	// BIP-173 test vector
+	pk := mustHex(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	assert.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", BitcoinP2WPKH(pk, false))
+	assert.Equal(t, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", BitcoinP2WPKH(pk, true))
+}
+
+func TestBitcoinP2TR(t *testing.T) {
+	// BIP-86 test vector of m/86'/0'/0'/0/0, whose internal key has an even y
+	pk := mustHex(t, "02cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+	addr, err := BitcoinP2TR(pk, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", addr)
+	// the same x with an odd y has the same internal key
+	pk[0] = 0x03
+	addr, err = BitcoinP2TR(pk, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", addr)
+
+	// BIP-86 test vector of m/86'/0'/0'/0/1
+	addr, err = BitcoinP2TR(mustHex(t, "0283dfe85a3151d2517290da461fe2815591ef69f2b18a2ce63f01697a8b313145"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh", addr)
+
+	addr, err = BitcoinP2TR(pk, true)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(addr, "tb1p"), addr)
+
+	_, err = BitcoinP2TR([]byte{0x02, 0x01}, false)
+	assert.Error(t, err)
+}
+
+func TestCosmos(t *testing.T) {
+	// the key hash of the BIP-173 test vector, under the prefixes of Cosmos SDK chains
+	pk := mustHex(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	assert.Equal(t, "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c", Cosmos(pk, "cosmos"))
+	assert.Equal(t, "osmo1w508d6qejxtdg4y5r3zarvary0c5xw7kjxy2e2", Cosmos(pk, "osmo"))
+	assert.Equal(t, "celestia1w508d6qejxtdg4y5r3zarvary0c5xw7kthx244", Cosmos(pk, "celestia"))
+
+	assert.True(t, ValidHRP("osmo"))
+	assert.True(t, ValidHRP("terra2"))
+	for _, hrp := range []string{"", "Cosmos", "cosmos1-", strings.Repeat("a", 84)} {
+		assert.False(t, ValidHRP(hrp), hrp)
+	}
+}
+
+func TestTron(t *testing.T) {
+	// the key with private key 1
+	assert.Equal(t, "TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC", Tron(mustHex(t, "7e5f4552091a69125d5dfcb7b8c2659029395bdf")))
+}
+
+func TestSolana(t *testing.T) {
+	assert.Equal(t, "11111111111111111111111111111111", Solana(make([]byte, 32)))
+}
+
+func TestXRPL(t *testing.T) {
+	// ripple-keypairs ed25519 test vector
+	pk := mustHex(t, "01fa53fa5a7e77798f882ece20b1abc00bb358a9e55a202d0d0676bd0ce37a63")
+	assert.Equal(t, "rLUEXYuLiQptky37CqLcm9USQpPiz5rkpD", XRPL(pk))
+}
+
+func TestUTXOChains_P2PKH(t *testing.T) {
+	// the key with private key 1, whose Bitcoin P2PKH address is 1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH
+	pk := mustHex(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	bitcoin := UTXOChain{Name: "Bitcoin", P2PKHVersion: []byte{0x00}}
+	assert.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", bitcoin.P2PKH(pk))
+
+	expected := map[string]string{
+		"Zcash transparent (t1)":   "t1UYsZVJkLPeMjxEtACvSxfWuNmddpWfxzs",
+		"Horizen transparent (zn)": "znbmBYaXE1eNNkRTX56LpjASXHcMERfcigj",
+		"Komodo":                   "RKxTdfmtxtfLDKZBgx6SvNkBtNu9jRYnLh",
+	}
+	for _, chain := range UTXOChains {
+		assert.Equal(t, expected[chain.Name], chain.P2PKH(pk), chain.Name)
+	}
+}
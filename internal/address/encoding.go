@@ -0,0 +1,143 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package address
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"strings"
+)
+
+const (
+	bitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	rippleAlphabet  = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+	bech32Charset   = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// base58 encodes b with the given alphabet. Each leading zero byte is encoded as the first character of the alphabet.
+func base58(b []byte, alphabet string) string {
+	x := new(big.Int).SetBytes(b)
+	base, mod := big.NewInt(58), new(big.Int)
+	var out []byte
+	for x.Sign() > 0 {
+		x.QuoRem(x, base, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for _, v := range b {
+		if v != 0 {
+			break
+		}
+		out = append(out, alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// base58Check prefixes payload with the version bytes and appends the first 4 bytes of its double SHA-256 before
+// encoding. Most chains use a single version byte; some Bitcoin derivatives such as Zcash use two.
+func base58Check(version, payload []byte, alphabet string) string {
+	b := append(append([]byte{}, version...), payload...)
+	hash1 := sha256.Sum256(b)
+	hash2 := sha256.Sum256(hash1[:])
+	return base58(append(b, hash2[:4]...), alphabet)
+}
+
+// Base58Check encodes payload, which starts with its version bytes, in the bitcoin alphabet with a 4 byte checksum,
+// e.g. for BIP32 extended keys.
+func Base58Check(payload []byte) string {
+	return base58Check(nil, payload, bitcoinAlphabet)
+}
+
+// The checksum constants of bech32 (BIP-173), for version 0 witness programs, and of bech32m (BIP-350), for later ones.
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// segwit encodes a witness program as a bech32 address for version 0 (BIP-173), and as a bech32m one for later
+// versions (BIP-350).
+func segwit(hrp string, version byte, program []byte) string {
+	data := append([]byte{version}, convertBits(program, 8, 5)...)
+	constant := uint32(bech32Const)
+	if version > 0 {
+		constant = bech32mConst
+	}
+	return bech32(hrp, data, constant)
+}
+
+// bech32 encodes 5 bit data under the human-readable part hrp, with the checksum constant of bech32 or bech32m.
+func bech32(hrp string, data []byte, constant uint32) string {
+	checksum := bech32Checksum(hrp, data, constant)
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range append(data, checksum...) {
+		sb.WriteByte(bech32Charset[v])
+	}
+	return sb.String()
+}
+
+// Bech32 encodes 8 bit data as bech32 (BIP-173) under the human-readable part hrp, without the 90 character limit of
+// Bitcoin addresses, e.g. for Cardano addresses.
+func Bech32(hrp string, data []byte) string {
+	return bech32(hrp, convertBits(data, 8, 5), bech32Const)
+}
+
+func convertBits(data []byte, from, to uint) []byte {
+	var (
+		acc  uint
+		bits uint
+		out  []byte
+	)
+	maxv := uint(1)<<to - 1
+	for _, v := range data {
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if bits > 0 {
+		out = append(out, byte(acc<<(to-bits)&maxv))
+	}
+	return out
+}
+
+func bech32Checksum(hrp string, data []byte, constant uint32) []byte {
+	values := make([]byte, 0, len(hrp)*2+1+len(data)+6)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	values = append(values, data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ constant
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte(polymod >> (5 * (5 - i)) & 31)
+	}
+	return checksum
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
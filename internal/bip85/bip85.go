@@ -0,0 +1,146 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package bip85 derives BIP85 child seeds from a recovered vault key.
+//
+// BIP85 derives from a BIP32 root key, which needs a chain code that vault keys do not have. The root is therefore
+// the vault key itself with the chain code HMAC-SHA256(key="io.finnet vault BIP85 root", msg=vault key). This chain
+// code is specific to this tool and no standard, so the seeds can be derived again from the vault key only with this
+// tool. Other BIP85 tools derive the same seeds only when given the root xprv.
+package bip85
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/address"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	hardened = 1 << 31
+
+	purpose     = 83696968
+	appBIP39    = 39
+	langEnglish = 0
+
+	rootChainCodeKey = "io.finnet vault BIP85 root"
+	entropyKey       = "bip-entropy-from-k"
+)
+
+// xprvVersion is the BIP32 version bytes of mainnet extended private keys.
+var xprvVersion = []byte{0x04, 0x88, 0xad, 0xe4}
+
+// Root is a BIP32 root key.
+type Root struct {
+	Key, ChainCode []byte
+}
+
+// VaultRoot builds the BIP85 root of a 32 byte vault ECDSA private key.
+func VaultRoot(vaultSK []byte) (Root, error) {
+	if len(vaultSK) != 32 {
+		return Root{}, fmt.Errorf("⚠ expected a 32 byte private key, got %d bytes", len(vaultSK))
+	}
+	mac := hmac.New(sha256.New, []byte(rootChainCodeKey))
+	mac.Write(vaultSK)
+	return Root{Key: append([]byte(nil), vaultSK...), ChainCode: mac.Sum(nil)}, nil
+}
+
+// XPRV serializes the root as a BIP32 extended private key.
+func (r Root) XPRV() string {
+	payload := make([]byte, 0, 78)
+	payload = append(payload, xprvVersion...)
+	// depth, parent fingerprint and child number are all zero for a root key
+	payload = append(payload, make([]byte, 1+4+4)...)
+	payload = append(payload, r.ChainCode...)
+	payload = append(payload, 0)
+	payload = append(payload, r.Key...)
+	return address.Base58Check(payload)
+}
+
+// Fingerprint is the BIP32 fingerprint of the root, the first 4 bytes of the hash160 of its public key, in hex. It
+// tells roots apart without revealing them, e.g. in records of the issued indexes.
+func (r Root) Fingerprint() string {
+	var k secp256k1.ModNScalar
+	k.SetByteSlice(r.Key)
+	pk := secp256k1.NewPrivateKey(&k).PubKey()
+	k.Zero()
+	return hex.EncodeToString(address.Hash160(pk.SerializeCompressed())[:4])
+}
+
+// Path is the BIP85 derivation path of a BIP39 seed, e.g. m/83696968'/39'/0'/24'/0'.
+func Path(words int, index uint32) string {
+	return fmt.Sprintf("m/%d'/%d'/%d'/%d'/%d'", purpose, appBIP39, langEnglish, words, index)
+}
+
+// BIP39 derives the English BIP39 mnemonic of 12, 18 or 24 words at the index.
+func (r Root) BIP39(words int, index uint32) (string, error) {
+	var entropyLen int
+	switch words {
+	case 12, 18, 24:
+		entropyLen = words * 4 / 3
+	default:
+		return "", fmt.Errorf("⚠ BIP85 seeds have 12, 18 or 24 words, not %d", words)
+	}
+	if index >= hardened {
+		return "", fmt.Errorf("⚠ the BIP85 index must be below %d", uint32(hardened))
+	}
+	entropy, err := r.Entropy(purpose, appBIP39, langEnglish, uint32(words), index)
+	if err != nil {
+		return "", err
+	}
+	defer clear(entropy)
+	return bip39.NewMnemonic(entropy[:entropyLen])
+}
+
+// Entropy derives the 64 bytes of BIP85 entropy at the hardened path.
+func (r Root) Entropy(path ...uint32) ([]byte, error) {
+	key, err := r.derive(path)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	mac := hmac.New(sha512.New, []byte(entropyKey))
+	mac.Write(key)
+	return mac.Sum(nil), nil
+}
+
+// derive returns the private key at the path, with every index hardened.
+func (r Root) derive(path []uint32) ([]byte, error) {
+	key := append([]byte(nil), r.Key...)
+	chainCode := append([]byte(nil), r.ChainCode...)
+	for _, index := range path {
+		data := make([]byte, 0, 37)
+		data = append(data, 0)
+		data = append(data, key...)
+		data = binary.BigEndian.AppendUint32(data, index|hardened)
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		clear(data)
+		sum := mac.Sum(nil)
+
+		var il, k secp256k1.ModNScalar
+		if overflow := il.SetByteSlice(sum[:32]); overflow {
+			return nil, errors.New("⚠ invalid BIP32 child key, try the next index")
+		}
+		k.SetByteSlice(key)
+		k.Add(&il)
+		if k.IsZero() {
+			return nil, errors.New("⚠ invalid BIP32 child key, try the next index")
+		}
+		clear(key)
+		k.PutBytesUnchecked(key)
+		il.Zero()
+		k.Zero()
+		copy(chainCode, sum[32:])
+		clear(sum)
+	}
+	return key, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package bip85
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the root key of the BIP85 test vectors
+const testXPRV = "xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb"
+
+func decodeXPRV(t *testing.T, xprv string) Root {
+	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	x := new(big.Int)
+	for _, c := range xprv {
+		x.Mul(x, big.NewInt(58))
+		x.Add(x, big.NewInt(int64(strings.IndexRune(alphabet, c))))
+	}
+	b := x.Bytes()
+	require.Len(t, b, 82)
+	return Root{ChainCode: b[13:45], Key: b[46:78]}
+}
+
+func TestBIP85_TestVectors(t *testing.T) {
+	root := decodeXPRV(t, testXPRV)
+	assert.Equal(t, testXPRV, root.XPRV())
+
+	key, err := root.derive([]uint32{83696968, 0, 0})
+	require.NoError(t, err)
+	assert.Equal(t, "cca20ccb0e9a90feb0912870c3323b24874b0ca3d8018c4b96d0b97c0e82ded0", hex.EncodeToString(key))
+	entropy, err := root.Entropy(83696968, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "efecfbccffea313214232d29e71563d941229afb4338c21f9517c41aaa0d16f00b83d2a09ef747e7a64e8e2bd5a14869e693da66ce94ac2da570ab7ee48618f7", hex.EncodeToString(entropy))
+
+	mnemonic, err := root.BIP39(12, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose", mnemonic)
+	mnemonic, err = root.BIP39(24, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "puppy ocean match cereal symbol another shed magic wrap hammer bulb intact gadget divorce twin tonight reason outdoor destroy simple truth cigar social volcano", mnemonic)
+
+	_, err = root.BIP39(15, 0)
+	assert.Error(t, err)
+	assert.Equal(t, "m/83696968'/39'/0'/24'/3'", Path(24, 3))
+}
+
+// TestBIP85_VaultRootVector pins the vault root: its chain code is specific to this tool, so a change to it would
+// silently derive other seeds than the ones already issued, which no other tool can derive from the vault key.
+func TestBIP85_VaultRootVector(t *testing.T) {
+	vaultSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	root, err := VaultRoot(vaultSK)
+	require.NoError(t, err)
+	assert.Equal(t, "2582d27016d7e6c14db277a9433c297b9e9026b3ff15078db69487e3b5eeb3c9", hex.EncodeToString(root.ChainCode))
+	assert.Equal(t, "xprv9s21ZrQH143K2S1zY5JnWJF2gkD6QdCYEFnJdHCR6o2AgBPvLVBEZyUe3CEYyWZbW9Ju1WSPz8hvfqRtWF4U4B8qXZ9YAoFUYGS28XirwVj", root.XPRV())
+
+	mnemonic, err := root.BIP39(12, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "essence inquiry diagram wood course decide tragic predict stage minor toy portion", mnemonic)
+	mnemonic, err = root.BIP39(24, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "vicious master isolate exist brisk survey shoe number develop chase input unfold ivory light kind grain nurse glance champion speed moral juice glance across", mnemonic)
+}
+
+func TestBIP85_VaultRoot(t *testing.T) {
+	vaultSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	root, err := VaultRoot(vaultSK)
+	require.NoError(t, err)
+	assert.Equal(t, vaultSK, root.Key)
+	assert.Len(t, root.ChainCode, 32)
+	assert.True(t, strings.HasPrefix(root.XPRV(), "xprv"))
+
+	// deterministic, and different per index
+	again, err := VaultRoot(vaultSK)
+	require.NoError(t, err)
+	first, err := root.BIP39(24, 0)
+	require.NoError(t, err)
+	same, err := again.BIP39(24, 0)
+	require.NoError(t, err)
+	second, err := root.BIP39(24, 1)
+	require.NoError(t, err)
+	assert.Equal(t, first, same)
+	assert.NotEqual(t, first, second)
+
+	_, err = VaultRoot(vaultSK[1:])
+	assert.Error(t, err)
+}
+
+func TestBIP85_Fingerprint(t *testing.T) {
+	// the master key of BIP32 test vector 1
+	root := decodeXPRV(t, "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi")
+	assert.Equal(t, "3442193e", root.Fingerprint())
+}
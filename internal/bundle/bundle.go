@@ -0,0 +1,161 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package bundle packs files into a single ZIP, optionally sealed with a password, so that outputs travel together.
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/entropy"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	sealedVersion = 1
+	keyLen        = 32
+)
+
+// ErrWrongPassword is returned by Open when the password is wrong or the sealed bundle was altered.
+var ErrWrongPassword = errors.New("⚠ could not decrypt the bundle: the password is wrong or the file is corrupted")
+
+type (
+	sealedJSON struct {
+		Version    int           `json:"version"`
+		KDF        string        `json:"kdf"`
+		KDFParams  kdfParamsJSON `json:"kdfparams"`
+		Cipher     string        `json:"cipher"`
+		IV         string        `json:"iv"`
+		CipherText string        `json:"ciphertext"`
+	}
+	kdfParamsJSON struct {
+		N    int    `json:"n"`
+		R    int    `json:"r"`
+		P    int    `json:"p"`
+		Salt string `json:"salt"`
+	}
+)
+
+// Zip creates a ZIP of the entries, keyed by their name in the ZIP. Entries are written in name order, so that the same
+// entries always produce the same listing.
+func Zip(entries map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = w.Write(entries[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Seal encrypts data with AES-256-GCM under a key derived from the password with scrypt. The result is JSON that records
+// the KDF parameters, so that Open needs nothing but the password. Its salt and IV are read from the entropy package.
+func Seal(data []byte, password string, kdf walletv3.KDFParams) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("⚠ the bundle password must not be empty")
+	}
+	if err := kdf.Validate(); err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 32)
+	if err := entropy.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(password, salt, kdf)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if err = entropy.Read(iv); err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealedJSON{
+		Version: sealedVersion,
+		KDF:     "scrypt",
+		KDFParams: kdfParamsJSON{
+			N:    kdf.N,
+			R:    kdf.R,
+			P:    kdf.P,
+			Salt: hex.EncodeToString(salt),
+		},
+		Cipher:     "aes-256-gcm",
+		IV:         hex.EncodeToString(iv),
+		CipherText: base64.StdEncoding.EncodeToString(gcm.Seal(nil, iv, data, nil)),
+	})
+}
+
+// Open decrypts a bundle sealed with Seal.
+func Open(sealed []byte, password string) ([]byte, error) {
+	var s sealedJSON
+	if err := json.Unmarshal(sealed, &s); err != nil {
+		return nil, fmt.Errorf("⚠ not a sealed bundle: %v", err)
+	}
+	if s.Version != sealedVersion || s.KDF != "scrypt" || s.Cipher != "aes-256-gcm" {
+		return nil, fmt.Errorf("⚠ unsupported sealed bundle: version %d, kdf %q, cipher %q", s.Version, s.KDF, s.Cipher)
+	}
+	kdf := walletv3.KDFParams{N: s.KDFParams.N, R: s.KDFParams.R, P: s.KDFParams.P}
+	if err := kdf.Validate(); err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(s.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ invalid salt in sealed bundle: %v", err)
+	}
+	iv, err := hex.DecodeString(s.IV)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ invalid iv in sealed bundle: %v", err)
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(s.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ invalid ciphertext in sealed bundle: %v", err)
+	}
+	gcm, err := newGCM(password, salt, kdf)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != gcm.NonceSize() {
+		return nil, fmt.Errorf("⚠ invalid iv length %d in sealed bundle", len(iv))
+	}
+	data, err := gcm.Open(nil, iv, cipherText, nil)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	return data, nil
+}
+
+func newGCM(password string, salt []byte, kdf walletv3.KDFParams) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, kdf.N, kdf.R, kdf.P, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
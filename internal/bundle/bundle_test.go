@@ -0,0 +1,51 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_ZipSealOpen(t *testing.T) {
+	entries := map[string][]byte{
+		"wallet.json":             []byte(`{"version":3}`),
+		"tss-shares/party-1.json": []byte(`{"shareId":"1"}`),
+	}
+	zipped, err := Zip(entries)
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(zipped), int64(len(zipped)))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 2)
+	assert.Equal(t, "tss-shares/party-1.json", zr.File[0].Name)
+	rc, err := zr.File[1].Open()
+	require.NoError(t, err)
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, entries["wallet.json"], content)
+
+	sealed, err := Seal(zipped, "correct horse", walletv3.Presets["light"])
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "shareId")
+
+	opened, err := Open(sealed, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, zipped, opened)
+
+	_, err = Open(sealed, "wrong")
+	assert.ErrorIs(t, err, ErrWrongPassword)
+
+	_, err = Seal(zipped, "", walletv3.Presets["light"])
+	assert.Error(t, err)
+	_, err = Open([]byte("not json"), "correct horse")
+	assert.Error(t, err)
+}
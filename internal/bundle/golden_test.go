@@ -0,0 +1,40 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build entropytest
+
+package bundle
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/entropy"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestBundle_Golden(t *testing.T) {
+	t.Cleanup(entropy.Use(entropy.NewDeterministic("bundle")))
+	zipped, err := Zip(map[string][]byte{
+		"wallet.json":             []byte(`{"version":3}`),
+		"tss-shares/party-1.json": []byte(`{"shareId":"1"}`),
+	})
+	require.NoError(t, err)
+	sealed, err := Seal(zipped, "correct horse", walletv3.Presets["light"])
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "sealed-bundle.golden.json")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, sealed, 0644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(sealed))
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package canonical encodes JSON deterministically and signs it, so that downstream systems can verify the integrity
+// and origin of the files the tool writes.
+package canonical
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marshal encodes v as canonical JSON: no insignificant whitespace, object keys sorted by their bytes, no HTML escaping,
+// integers as is and other numbers in their shortest form. Equal values always encode to the same bytes.
+func Marshal(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic any
+	if err = dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err = encode(buf, generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encode(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := encode(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, el := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encode(buf, el); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case json.Number:
+		buf.WriteString(formatNumber(v))
+	case string:
+		return encodeString(buf, v)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("unexpected JSON value %T", v)
+	}
+	return nil
+}
+
+func encodeString(buf *bytes.Buffer, s string) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	// Encode appends a newline
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+// formatNumber keeps integers as they are, as they may be too large for a float64, and formats other numbers in their
+// shortest form.
+func formatNumber(n json.Number) string {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		return s
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return s
+	}
+	if f == float64(int64(f)) && f < 1e21 && f > -1e21 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// LoadSigningKey reads an Ed25519 private key from a PKCS#8 PEM file, e.g. as created by `openssl genpkey -algorithm ed25519`.
+func LoadSigningKey(file string) (ed25519.PrivateKey, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to read signing key `%s`: %s", file, err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("⚠ signing key `%s` is not a PKCS#8 PEM private key", file)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ invalid signing key `%s`: %s", file, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("⚠ the signing key must be an Ed25519 key")
+	}
+	return edKey, nil
+}
+
+// Fingerprint identifies a signing key by the SHA-256 of its public key, to check against the key a downstream system trusts.
+func Fingerprint(key ed25519.PrivateKey) string {
+	hash := sha256.Sum256(key.Public().(ed25519.PublicKey))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package canonical
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	type inner struct {
+		Zeta  string `json:"zeta"`
+		Alpha int    `json:"alpha"`
+	}
+	v := struct {
+		B     []inner        `json:"b"`
+		A     map[string]any `json:"a"`
+		HTML  string         `json:"html"`
+		Float float64        `json:"float"`
+		Whole float64        `json:"whole"`
+		Nil   *inner         `json:"nil"`
+	}{
+		B:     []inner{{Zeta: "z", Alpha: 1}},
+		A:     map[string]any{"y": true, "x": 12345678901234567},
+		HTML:  "<a & b>",
+		Float: 0.5,
+		Whole: 3.0,
+	}
+	bz, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{"x":12345678901234567,"y":true},"b":[{"alpha":1,"zeta":"z"}],"float":0.5,"html":"<a & b>","nil":null,"whole":3}`, string(bz))
+
+	// deterministic
+	again, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, bz, again)
+
+	bz, err = Marshal(map[string]float64{"n": 1e-7})
+	require.NoError(t, err)
+	assert.Equal(t, `{"n":1e-07}`, string(bz))
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "signing.pem")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	loaded, err := LoadSigningKey(file)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+	assert.Len(t, Fingerprint(loaded), 64)
+
+	require.NoError(t, os.WriteFile(file, []byte("not a key"), 0600))
+	_, err = LoadSigningKey(file)
+	assert.ErrorContains(t, err, "PKCS#8")
+}
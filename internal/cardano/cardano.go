@@ -0,0 +1,73 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package cardano converts the recovered Ed25519 key of a vault into its Cardano enterprise payment address (CIP-19)
+// and into a signing key file that cardano-cli can sign transactions with.
+package cardano
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/address"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// the CIP-19 header bytes of enterprise addresses, paid to a key hash with no staking part
+	enterpriseMainnet = 0x61
+	enterpriseTestnet = 0x60
+
+	// SigningKeyType is the text envelope type of extended payment signing keys, as read by cardano-cli
+	SigningKeyType = "PaymentExtendedSigningKeyShelley_ed25519_bip32"
+)
+
+// Address returns the enterprise payment address of a 32 byte Ed25519 public key, addr1... on mainnet and
+// addr_test1... on the testnets.
+func Address(edPK []byte, testnet bool) string {
+	hash, _ := blake2b.New(28, nil)
+	hash.Write(edPK)
+	header, hrp := byte(enterpriseMainnet), "addr"
+	if testnet {
+		header, hrp = enterpriseTestnet, "addr_test"
+	}
+	return address.Bech32(hrp, append([]byte{header}, hash.Sum(nil)...))
+}
+
+// TextEnvelope is the JSON format of cardano-cli key files.
+type TextEnvelope struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	CborHex     string `json:"cborHex"`
+}
+
+// SigningKey encodes the vault's Ed25519 key as a cardano-cli extended signing key file. The vault key is a scalar
+// rather than the seed of a regular Ed25519 key, which an extended (BIP32-Ed25519) key holds as is: its left half is
+// the scalar in little-endian, and its right half is the nonce secret, derived here from the scalar. The key is not
+// meant for derivation, so its chain code is zero. edSK is the big-endian scalar and edPK its 32 byte public key.
+func SigningKey(edSK, edPK []byte) ([]byte, error) {
+	if len(edSK) != 32 || len(edPK) != 32 {
+		return nil, errors.New("⚠ the Cardano signing key needs a 32 byte Ed25519 private scalar and public key")
+	}
+	// CBOR byte string of 128 bytes: scalar, nonce secret, public key and chain code
+	key := make([]byte, 0, 2+128)
+	key = append(key, 0x58, 0x80)
+	for i := len(edSK) - 1; i >= 0; i-- {
+		key = append(key, edSK[i])
+	}
+	nonce := sha512.Sum512(edSK)
+	key = append(key, nonce[32:]...)
+	clear(nonce[:])
+	key = append(key, edPK...)
+	key = append(key, make([]byte, 32)...)
+	defer clear(key)
+
+	return json.MarshalIndent(TextEnvelope{
+		Type:        SigningKeyType,
+		Description: "Payment Signing Key",
+		CborHex:     hex.EncodeToString(key),
+	}, "", "    ")
+}
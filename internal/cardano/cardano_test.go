@@ -0,0 +1,51 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package cardano
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddress(t *testing.T) {
+	// CIP-19 test vector, addr_vk1w0l2sr2zgfm26ztc6nl9xy8ghsk5sh6ldwemlpmp9xylzy4dtf7st80zhd
+	pk, _ := hex.DecodeString("73fea80d424276ad0978d4fe5310e8bc2d485f5f6bb3bf87612989f112ad5a7d")
+	assert.Equal(t, "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8", Address(pk, false))
+	assert.Equal(t, "addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz", Address(pk, true))
+}
+
+func TestSigningKey(t *testing.T) {
+	sk := make([]byte, 32)
+	sk[31] = 1
+	_, pub, err := edwards.PrivKeyFromScalar(sk)
+	require.NoError(t, err)
+	pk := pub.SerializeCompressed()
+
+	file, err := SigningKey(sk, pk)
+	require.NoError(t, err)
+	var envelope TextEnvelope
+	require.NoError(t, json.Unmarshal(file, &envelope))
+	assert.Equal(t, SigningKeyType, envelope.Type)
+
+	key, err := hex.DecodeString(envelope.CborHex)
+	require.NoError(t, err)
+	require.Len(t, key, 2+128)
+	assert.Equal(t, []byte{0x58, 0x80}, key[:2])
+	key = key[2:]
+	// the scalar is stored little-endian
+	assert.Equal(t, byte(1), key[0])
+	assert.Equal(t, make([]byte, 31), key[1:32])
+	assert.NotEqual(t, make([]byte, 32), key[32:64], "the nonce secret is set")
+	assert.Equal(t, pk, key[64:96])
+	assert.Equal(t, make([]byte, 32), key[96:])
+
+	_, err = SigningKey(sk[:31], pk)
+	assert.Error(t, err)
+}
@@ -5,9 +5,32 @@
 package config
 
 type AppConfig struct {
-	Filenames      []string
-	NonceOverride  int
-	QuorumOverride int
-	ExportKSFile   string
-	PasswordForKS  string
+	Filenames         []string
+	NonceOverride     int
+	QuorumOverride    int
+	ExportKSFile      string
+	PasswordForKS     string
+	ExportTSSShareDir string
+	// ExportPEMFile is the name of the PKCS#8 PEM file of the ECDSA key, written with a SEC1 one next to it
+	ExportPEMFile string
+	// ExportCardanoKey is the name of the cardano-cli signing key file of the EdDSA key
+	ExportCardanoKey string
+	// QRInput means that Filenames are directories of QR code images rather than JSON files
+	QRInput bool
+	// ReadOnlySource requires that the tool cannot write to any of the input locations
+	ReadOnlySource bool
+	// Bundle moves the written files into a single ZIP after recovery, sealed with BundlePassword if set
+	Bundle         bool
+	BundlePassword string
+	// BIP38Password also shows the Bitcoin mainnet key encrypted with it as a BIP38 key
+	BIP38Password string
+	// ExportAddressesFile is the secrets-free address book to write after recovery, in CSV or JSON
+	ExportAddressesFile string
+	// ExportWatchOnlyFile is the secrets-free Electrum watch-only wallet to write after recovery, with a descriptor
+	ExportWatchOnlyFile string
+	// ExportSummaryFile is the secrets-free recovery summary to write after recovery, in Markdown or HTML
+	ExportSummaryFile string
+	// PostHook is a command run after recovery with the written files, given the private keys only with PassSecretsFD
+	PostHook      string
+	PassSecretsFD bool
 }
@@ -0,0 +1,115 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Errors returned by OpenEnvelope. They are wrapped with the underlying cause, so use errors.Is to check for them.
+var (
+	ErrDecodeIV         = errors.New("invalid iv encoding")
+	ErrDecodeTag        = errors.New("invalid tag encoding")
+	ErrDecodeCiphertext = errors.New("invalid ciphertext encoding")
+	ErrDecodeHash       = errors.New("invalid hash encoding")
+	ErrCipherInit       = errors.New("cipher init failed")
+	ErrDecrypt          = errors.New("decryption failed, the key may be wrong or the data corrupted")
+	ErrHashMismatch     = errors.New("hash mismatch")
+)
+
+// Envelope is an AES-256-GCM ciphertext as stored in backup files: the GCM tag is kept apart from the ciphertext,
+// and a SHA-512 hash of the plaintext is stored alongside for an integrity check after decryption.
+type Envelope struct {
+	CipherTextB64 string
+	IV            string
+	Tag           string
+	Hash          string
+}
+
+// OpenEnvelope decrypts an envelope with a 32 byte key and verifies the hash of the plaintext in constant time.
+func OpenEnvelope(aesKey32 []byte, env Envelope) ([]byte, error) {
+	aesNonce, err := hex.DecodeString(env.IV)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDecodeIV, err)
+	}
+	aesTag, err := hex.DecodeString(env.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDecodeTag, err)
+	}
+	aesCT, err := base64.StdEncoding.DecodeString(env.CipherTextB64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDecodeCiphertext, err)
+	}
+	expHash, err := hex.DecodeString(env.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDecodeHash, err)
+	}
+
+	aesGCM, err := newGCM(aesKey32, len(aesNonce), len(aesTag))
+	if err != nil {
+		return nil, err
+	}
+
+	// append the tag to the ciphertext, which is what golang's GCM implementation expects
+	sealed := make([]byte, 0, len(aesCT)+len(aesTag))
+	sealed = append(append(sealed, aesCT...), aesTag...)
+	plainload, err := aesGCM.Open(nil, aesNonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDecrypt, err)
+	}
+	hash := sha512.Sum512(plainload)
+	if subtle.ConstantTimeCompare(hash[:], expHash) != 1 {
+		clear(plainload)
+		return nil, ErrHashMismatch
+	}
+	return plainload, nil
+}
+
+// SealEnvelope encrypts a plaintext with a 32 byte key and a random IV, in the format read by OpenEnvelope.
+func SealEnvelope(aesKey32, plainload []byte) (Envelope, error) {
+	aesGCM, err := newGCM(aesKey32, 12, 16)
+	if err != nil {
+		return Envelope{}, err
+	}
+	aesNonce := make([]byte, aesGCM.NonceSize())
+	if _, err = rand.Read(aesNonce); err != nil {
+		return Envelope{}, err
+	}
+	sealed := aesGCM.Seal(nil, aesNonce, plainload, nil)
+	aesCT, aesTag := sealed[:len(sealed)-aesGCM.Overhead()], sealed[len(sealed)-aesGCM.Overhead():]
+	hash := sha512.Sum512(plainload)
+	return Envelope{
+		CipherTextB64: base64.StdEncoding.EncodeToString(aesCT),
+		IV:            hex.EncodeToString(aesNonce),
+		Tag:           hex.EncodeToString(aesTag),
+		Hash:          hex.EncodeToString(hash[:]),
+	}, nil
+}
+
+func newGCM(aesKey32 []byte, nonceSize, tagSize int) (cipher.AEAD, error) {
+	if len(aesKey32) != 32 {
+		return nil, fmt.Errorf("%w: key must be 32 bytes, got %d", ErrCipherInit, len(aesKey32))
+	}
+	aesBlk, err := aes.NewCipher(aesKey32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCipherInit, err)
+	}
+	aesGCM, err := cipher.NewGCMWithNonceSize(aesBlk, nonceSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCipherInit, err)
+	}
+	if tagSize != aesGCM.Overhead() {
+		return nil, fmt.Errorf("%w: tag must be %d bytes, got %d", ErrDecodeTag, aesGCM.Overhead(), tagSize)
+	}
+	return aesGCM, nil
+}
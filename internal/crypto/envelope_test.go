@@ -0,0 +1,81 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testKey       = bytes.Repeat([]byte{0x42}, 32)
+	testPlainload = []byte(`{"name":"test vault","threshold":2}`)
+)
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	env, err := SealEnvelope(testKey, testPlainload)
+	if !assert.NoError(t, err) {
+		return
+	}
+	plainload, err := OpenEnvelope(testKey, env)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, testPlainload, plainload)
+}
+
+func TestEnvelope_Corrupted(t *testing.T) {
+	env, err := SealEnvelope(testKey, testPlainload)
+	if !assert.NoError(t, err) {
+		return
+	}
+	flipFirstHexByte := func(s string) string {
+		bz, _ := hex.DecodeString(s)
+		bz[0] ^= 0x01
+		return hex.EncodeToString(bz)
+	}
+	ct, _ := base64.StdEncoding.DecodeString(env.CipherTextB64)
+	ct[0] ^= 0x01
+	otherHash, err := SealEnvelope(testKey, []byte("other"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name    string
+		key     []byte
+		mutate  func(e *Envelope)
+		wantErr error
+	}{
+		{"wrong key", bytes.Repeat([]byte{0x43}, 32), func(e *Envelope) {}, ErrDecrypt},
+		{"short key", testKey[:16], func(e *Envelope) {}, ErrCipherInit},
+		{"flipped tag bit", testKey, func(e *Envelope) { e.Tag = flipFirstHexByte(e.Tag) }, ErrDecrypt},
+		{"truncated tag", testKey, func(e *Envelope) { e.Tag = e.Tag[:16] }, ErrDecodeTag},
+		{"empty tag", testKey, func(e *Envelope) { e.Tag = "" }, ErrDecodeTag},
+		{"non-hex tag", testKey, func(e *Envelope) { e.Tag = "zz" + e.Tag[2:] }, ErrDecodeTag},
+		{"flipped iv bit", testKey, func(e *Envelope) { e.IV = flipFirstHexByte(e.IV) }, ErrDecrypt},
+		{"empty iv", testKey, func(e *Envelope) { e.IV = "" }, ErrCipherInit},
+		{"non-hex iv", testKey, func(e *Envelope) { e.IV = "xyz" }, ErrDecodeIV},
+		{"flipped ciphertext bit", testKey, func(e *Envelope) { e.CipherTextB64 = base64.StdEncoding.EncodeToString(ct) }, ErrDecrypt},
+		{"non-base64 ciphertext", testKey, func(e *Envelope) { e.CipherTextB64 = "!!" }, ErrDecodeCiphertext},
+		{"non-hex hash", testKey, func(e *Envelope) { e.Hash = "not hex" }, ErrDecodeHash},
+		{"other hash", testKey, func(e *Envelope) { e.Hash = otherHash.Hash }, ErrHashMismatch},
+		{"truncated hash", testKey, func(e *Envelope) { e.Hash = e.Hash[:64] }, ErrHashMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corrupted := env
+			tt.mutate(&corrupted)
+			plainload, err := OpenEnvelope(tt.key, corrupted)
+			assert.Nil(t, plainload)
+			assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+		})
+	}
+}
@@ -32,3 +32,20 @@ func InflateSaveDataJSON(compressed []byte) ([]byte, error) {
 	}
 	return decompressed, reader.Close()
 }
+
+// DeflateSaveDataJSON compresses TSS save data in JSON format using the DEFLATE algorithm using a custom dictionary.
+// It is the inverse of InflateSaveDataJSON and is used to produce V2 test fixtures.
+func DeflateSaveDataJSON(uncompressed []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := flate.NewWriterDict(&buf, flate.BestCompression, []byte(deflateCommonJSONDict))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flate writer: %v", err)
+	}
+	if _, err = writer.Write(uncompressed); err != nil {
+		return nil, fmt.Errorf("failed to write to flate writer: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close flate writer: %v", err)
+	}
+	return buf.Bytes(), nil
+}
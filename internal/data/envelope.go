@@ -0,0 +1,61 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// backupKey is the top-level key of the signer data of a backup file.
+const backupKey = "vaults"
+
+// UnwrapBackup returns the backup file JSON in content. Backups shared from the mobile app's "share sheet" wrap the
+// signer data in an envelope object, under another top-level key next to metadata about the export, either as an
+// object or as a JSON string of it. wrapped is the key the backup was found under, or empty for a plain backup file.
+// Content that is neither is returned as is, for the caller to report.
+func UnwrapBackup(content []byte) (backup []byte, wrapped string, err error) {
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(content, &fields); err != nil {
+		return content, "", nil
+	}
+	if _, ok := fields[backupKey]; ok {
+		return content, "", nil
+	}
+	var keys []string
+	for key, raw := range fields {
+		if payload, ok := signerData(raw); ok {
+			backup, keys = payload, append(keys, key)
+		}
+	}
+	switch len(keys) {
+	case 0:
+		return content, "", nil
+	case 1:
+		return backup, keys[0], nil
+	}
+	sort.Strings(keys)
+	return nil, "", fmt.Errorf("⚠ the mobile app envelope holds more than one backup, under %s: share each backup file on its own",
+		strings.Join(keys, ", "))
+}
+
+// signerData is the backup JSON in an envelope field, given as an object or as a JSON string of one.
+func signerData(raw json.RawMessage) ([]byte, bool) {
+	payload := []byte(raw)
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		payload = []byte(text)
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(payload, &fields) != nil {
+		return nil, false
+	}
+	if _, ok := fields[backupKey]; !ok {
+		return nil, false
+	}
+	return payload, true
+}
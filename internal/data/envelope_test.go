@@ -0,0 +1,35 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnwrapBackup(t *testing.T) {
+	const plain = `{"timestamp":"2024-12-24T13:57:57Z","vaults":{"vault-1":{}}}`
+	tests := []struct {
+		name, content, want, wrapped string
+	}{
+		{"plain backup", plain, plain, ""},
+		{"envelope", `{"app":"io.vault","exportedAt":"2025-01-02","signerData":` + plain + `}`, plain, "signerData"},
+		{"stringified envelope", `{"app":"io.vault","payload":"{\"vaults\":{}}"}`, `{"vaults":{}}`, "payload"},
+		{"not a backup", `{"address":"0x00"}`, `{"address":"0x00"}`, ""},
+		{"not json", `vaults`, `vaults`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup, wrapped, err := UnwrapBackup([]byte(tt.content))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(backup))
+			assert.Equal(t, tt.wrapped, wrapped)
+		})
+	}
+
+	_, _, err := UnwrapBackup([]byte(`{"first":` + plain + `,"second":` + plain + `}`))
+	assert.ErrorContains(t, err, "under first, second")
+}
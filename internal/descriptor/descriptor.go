@@ -0,0 +1,74 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package descriptor writes Bitcoin output script descriptors (BIP380) of single public keys, for watch-only wallets.
+package descriptor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	inputCharset    = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	checksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+var generator = [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+
+// WPKH is the checksummed native SegWit (P2WPKH) descriptor of a compressed public key. It describes the same address
+// on mainnet and testnet.
+func WPKH(compressedPK []byte) (string, error) {
+	if len(compressedPK) != 33 || (compressedPK[0] != 0x02 && compressedPK[0] != 0x03) {
+		return "", fmt.Errorf("⚠ a wpkh descriptor needs a 33 byte compressed public key")
+	}
+	return AddChecksum("wpkh(" + hex.EncodeToString(compressedPK) + ")")
+}
+
+// AddChecksum appends the BIP380 checksum to a descriptor.
+func AddChecksum(desc string) (string, error) {
+	symbols := make([]uint64, 0, len(desc)*4/3+9)
+	groups := make([]uint64, 0, 3)
+	for _, c := range desc {
+		v := strings.IndexRune(inputCharset, c)
+		if v < 0 {
+			return "", fmt.Errorf("⚠ invalid character %q in descriptor", c)
+		}
+		symbols = append(symbols, uint64(v&31))
+		groups = append(groups, uint64(v>>5))
+		if len(groups) == 3 {
+			symbols = append(symbols, groups[0]*9+groups[1]*3+groups[2])
+			groups = groups[:0]
+		}
+	}
+	switch len(groups) {
+	case 1:
+		symbols = append(symbols, groups[0])
+	case 2:
+		symbols = append(symbols, groups[0]*3+groups[1])
+	}
+	symbols = append(symbols, 0, 0, 0, 0, 0, 0, 0, 0)
+	checksum := polymod(symbols) ^ 1
+	var sb strings.Builder
+	sb.WriteString(desc + "#")
+	for i := 0; i < 8; i++ {
+		sb.WriteByte(checksumCharset[(checksum>>(5*(7-i)))&31])
+	}
+	return sb.String(), nil
+}
+
+func polymod(symbols []uint64) uint64 {
+	chk := uint64(1)
+	for _, value := range symbols {
+		top := chk >> 35
+		chk = (chk&0x7ffffffff)<<5 ^ value
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package descriptor
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddChecksum(t *testing.T) {
+	// test vector of BIP380
+	desc, err := AddChecksum("raw(deadbeef)")
+	require.NoError(t, err)
+	assert.Equal(t, "raw(deadbeef)#89f8spxm", desc)
+
+	_, err = AddChecksum("raw(dead€)")
+	assert.Error(t, err)
+}
+
+func TestWPKH(t *testing.T) {
+	// the public key of private key 1
+	pk, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	desc, err := WPKH(pk)
+	require.NoError(t, err)
+	assert.Equal(t, "wpkh(0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798)#ucxz0gak", desc)
+
+	_, err = WPKH(append([]byte{0x04}, pk[1:]...))
+	assert.Error(t, err)
+	_, err = WPKH(pk[:32])
+	assert.Error(t, err)
+}
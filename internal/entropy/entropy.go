@@ -0,0 +1,65 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package entropy is the source of the random bytes of exported files: keystore IDs, salts and IVs. It is crypto/rand,
+// except in golden tests built with the entropytest tag, which swap in a Deterministic source with Use to compare
+// exports byte for byte with golden files. Without the tag, as in every release build, it cannot be replaced.
+package entropy
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+)
+
+// source is read by every export path.
+var source io.Reader = rand.Reader
+
+// Read fills b from the source.
+func Read(b []byte) error {
+	_, err := io.ReadFull(source, b)
+	return err
+}
+
+// Reader reads from the source, for APIs that take an io.Reader.
+func Reader() io.Reader {
+	return reader{}
+}
+
+type reader struct{}
+
+func (reader) Read(p []byte) (int, error) {
+	return io.ReadFull(source, p)
+}
+
+// Deterministic is a reproducible stream of bytes: SHA-256 of the seed and a block counter. Anyone who knows the seed
+// knows every byte, so it must never be used for real exports.
+type Deterministic struct {
+	seed    []byte
+	counter uint64
+	block   []byte
+}
+
+// NewDeterministic starts a stream from seed. Two streams of the same seed read the same bytes.
+func NewDeterministic(seed string) *Deterministic {
+	return &Deterministic{seed: []byte(seed)}
+}
+
+func (d *Deterministic) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(d.block) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], d.counter)
+			d.counter++
+			block := sha256.Sum256(append(append([]byte{}, d.seed...), counter[:]...))
+			d.block = block[:]
+		}
+		copied := copy(p[n:], d.block)
+		d.block = d.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
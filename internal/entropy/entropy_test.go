@@ -0,0 +1,36 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package entropy
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministic(t *testing.T) {
+	// the stream does not depend on how it is read
+	whole := make([]byte, 100)
+	_, err := NewDeterministic("golden").Read(whole)
+	require.NoError(t, err)
+	pieces := NewDeterministic("golden")
+	read := make([]byte, 0, 100)
+	for _, size := range []int{1, 31, 32, 36} {
+		piece := make([]byte, size)
+		n, err := pieces.Read(piece)
+		require.NoError(t, err)
+		assert.Equal(t, size, n)
+		read = append(read, piece...)
+	}
+	assert.Equal(t, whole, read)
+
+	first := sha256.Sum256([]byte("golden\x00\x00\x00\x00\x00\x00\x00\x00"))
+	assert.Equal(t, first[:], whole[:32])
+	other := make([]byte, 100)
+	_, _ = NewDeterministic("other").Read(other)
+	assert.NotEqual(t, whole, other)
+}
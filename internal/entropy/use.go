@@ -0,0 +1,17 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build entropytest
+
+package entropy
+
+import "io"
+
+// Use replaces the source until the returned function restores the previous one, e.g. t.Cleanup(entropy.Use(...)).
+// It only exists in builds with the entropytest tag, so that no release build can be made to export with known bytes.
+func Use(s io.Reader) (restore func()) {
+	previous := source
+	source = s
+	return func() { source = previous }
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build entropytest
+
+package entropy
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUse(t *testing.T) {
+	restore := Use(NewDeterministic("golden"))
+	a := make([]byte, 16)
+	require.NoError(t, Read(a))
+	restore()
+	assert.Equal(t, rand.Reader, source)
+
+	t.Cleanup(Use(NewDeterministic("golden")))
+	b := make([]byte, 16)
+	require.NoError(t, Read(b))
+	assert.Equal(t, a, b)
+
+	// Reader reads the same source
+	t.Cleanup(Use(NewDeterministic("golden")))
+	c := make([]byte, 16)
+	_, err := Reader().Read(c)
+	require.NoError(t, err)
+	assert.Equal(t, a, c)
+}
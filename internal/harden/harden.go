@@ -0,0 +1,68 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package harden tightens the process environment at startup, so that secrets are less likely to leak through
+// world-readable output files or core dumps, and warns about environments that are unsuitable for a recovery.
+package harden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cloudDMIVendors maps (parts of) DMI vendor, product and asset tag strings to the cloud provider that sets them.
+var cloudDMIVendors = []struct{ marker, provider string }{
+	{"amazon", "AWS"},
+	{"google", "Google Cloud"},
+	{"7783-7084-3265-9085-8269-3286-77", "Azure"}, // Azure's chassis asset tag, Hyper-V desktops report Microsoft too
+	{"oraclecloud", "Oracle Cloud"},
+	{"alibaba", "Alibaba Cloud"},
+	{"digitalocean", "DigitalOcean"},
+	{"hetzner", "Hetzner"},
+	{"openstack", "OpenStack"},
+}
+
+// Apply hardens the current process and returns warnings about the environment for the operator.
+// It does not talk to the network: cloud VMs are detected from local firmware (DMI) data only.
+func Apply() (warnings []string) {
+	warnings = append(warnings, applyProcessLimits()...)
+	warnings = append(warnings, platformHardening()...)
+	return warnings
+}
+
+// cloudVendorFromDMI returns the cloud provider named in the DMI files under dmiDir, if any.
+func cloudVendorFromDMI(dmiDir string) string {
+	for _, name := range []string{"sys_vendor", "bios_vendor", "bios_version", "product_name", "chassis_asset_tag"} {
+		content, err := os.ReadFile(filepath.Join(dmiDir, name))
+		if err != nil {
+			continue
+		}
+		value := strings.ToLower(string(content))
+		for _, vendor := range cloudDMIVendors {
+			if strings.Contains(value, vendor.marker) {
+				return vendor.provider
+			}
+		}
+	}
+	return ""
+}
+
+// sudoUser returns the user and group IDs of the user who started the tool with sudo, from the SUDO_UID and SUDO_GID
+// variables that sudo sets. ok is false when the tool was not started with sudo, or by root itself.
+func sudoUser(getenv func(string) string) (uid, gid int, ok bool, err error) {
+	uidValue, gidValue := getenv("SUDO_UID"), getenv("SUDO_GID")
+	if uidValue == "" && gidValue == "" {
+		return 0, 0, false, nil
+	}
+	if uid, err = strconv.Atoi(uidValue); err != nil || uid < 0 {
+		return 0, 0, false, fmt.Errorf("invalid SUDO_UID %q", uidValue)
+	}
+	if gid, err = strconv.Atoi(gidValue); err != nil || gid < 0 {
+		return 0, 0, false, fmt.Errorf("invalid SUDO_GID %q", gidValue)
+	}
+	return uid, gid, uid != 0, nil
+}
@@ -0,0 +1,27 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build linux
+
+package harden
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+const dmiDir = "/sys/class/dmi/id"
+
+// platformHardening stops other processes of the same user from attaching to this one (ptrace) and looks for a cloud VM.
+func platformHardening() (warnings []string) {
+	if err := unix.Prctl(unix.PR_SET_DUMPABLE, 0, 0, 0, 0); err != nil {
+		warnings = append(warnings, fmt.Sprintf("⚠ Could not protect the process memory from debuggers: %s.", err))
+	}
+	if provider := cloudVendorFromDMI(dmiDir); provider != "" {
+		warnings = append(warnings, fmt.Sprintf("⚠ This machine looks like a %s cloud VM. Recovery should be done on an air gapped device you control, "+
+			"as cloud VMs can be snapshotted and their metadata services reached by anything running on them.", provider))
+	}
+	return warnings
+}
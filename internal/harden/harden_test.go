@@ -0,0 +1,69 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package harden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHarden_CloudVendorFromDMI(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"no dmi", map[string]string{}, ""},
+		{"laptop", map[string]string{"sys_vendor": "LENOVO\n", "product_name": "20XW0055US\n"}, ""},
+		{"hyper-v desktop", map[string]string{"sys_vendor": "Microsoft Corporation\n", "chassis_asset_tag": "None\n"}, ""},
+		{"aws nitro", map[string]string{"sys_vendor": "Amazon EC2\n"}, "AWS"},
+		{"aws xen", map[string]string{"sys_vendor": "Xen\n", "bios_version": "4.11.amazon\n"}, "AWS"},
+		{"gce", map[string]string{"sys_vendor": "Google\n", "product_name": "Google Compute Engine\n"}, "Google Cloud"},
+		{"azure", map[string]string{"sys_vendor": "Microsoft Corporation\n", "chassis_asset_tag": "7783-7084-3265-9085-8269-3286-77\n"}, "Azure"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if !assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)) {
+					return
+				}
+			}
+			assert.Equal(t, tt.want, cloudVendorFromDMI(dir))
+		})
+	}
+}
+
+func TestHarden_SudoUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		uid, gid int
+		ok       bool
+		err      string
+	}{
+		{"no sudo", map[string]string{}, 0, 0, false, ""},
+		{"sudo", map[string]string{"SUDO_UID": "1000", "SUDO_GID": "1001"}, 1000, 1001, true, ""},
+		{"sudo by root", map[string]string{"SUDO_UID": "0", "SUDO_GID": "0"}, 0, 0, false, ""},
+		{"no gid", map[string]string{"SUDO_UID": "1000"}, 0, 0, false, "invalid SUDO_GID"},
+		{"bad uid", map[string]string{"SUDO_UID": "alice", "SUDO_GID": "1000"}, 0, 0, false, "invalid SUDO_UID"},
+		{"negative gid", map[string]string{"SUDO_UID": "1000", "SUDO_GID": "-1"}, 0, 0, false, "invalid SUDO_GID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid, gid, ok, err := sudoUser(func(key string) string { return tt.env[key] })
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, []any{tt.uid, tt.gid, tt.ok}, []any{uid, gid, ok})
+			}
+		})
+	}
+}
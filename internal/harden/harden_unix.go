@@ -0,0 +1,73 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build !windows
+
+package harden
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyProcessLimits makes created files private to the user (0600), disables core dumps and, when started with sudo,
+// drops root privileges to the user who ran sudo.
+func applyProcessLimits() (warnings []string) {
+	unix.Umask(0077)
+	if err := unix.Setrlimit(unix.RLIMIT_CORE, &unix.Rlimit{Cur: 0, Max: 0}); err != nil {
+		warnings = append(warnings, fmt.Sprintf("⚠ Could not disable core dumps: %s. Secrets could end up on disk if the tool crashes.", err))
+	}
+	if os.Geteuid() != 0 {
+		return warnings
+	}
+	uid, gid, ok, err := sudoUser(os.Getenv)
+	switch {
+	case err != nil:
+		warnings = append(warnings, fmt.Sprintf("⚠ Running as root, and could not tell which user ran sudo: %s. Run the tool as a regular user so that its output files are not owned by root.", err))
+	case !ok:
+		warnings = append(warnings, "⚠ Running as root. There is no need to: run the tool as a regular user so that its output files are not owned by root.")
+	default:
+		if err = dropPrivileges(uid, gid); err != nil {
+			warnings = append(warnings, fmt.Sprintf("⚠ Running as root: could not drop the root privileges of sudo to user %d: %s. Run the tool as a regular user instead.", uid, err))
+		}
+	}
+	return warnings
+}
+
+// dropPrivileges switches the process to the user and group who ran sudo, with their supplementary groups and home
+// directory, so that the files it writes are theirs and it cannot do more than they could. It cannot be undone.
+func dropPrivileges(uid, gid int) error {
+	groups := []int{gid}
+	if u, err := user.LookupId(strconv.Itoa(uid)); err == nil {
+		if ids, err := u.GroupIds(); err == nil {
+			groups = groups[:0]
+			for _, id := range ids {
+				if g, err := strconv.Atoi(id); err == nil {
+					groups = append(groups, g)
+				}
+			}
+		}
+		// sudo may have set HOME to root's, where the settings file would then be written
+		_ = os.Setenv("HOME", u.HomeDir)
+	}
+	// the groups first, as dropping the user takes away the right to change them
+	if err := syscall.Setgroups(groups); err != nil {
+		return err
+	}
+	if err := syscall.Setgid(gid); err != nil {
+		return err
+	}
+	if err := syscall.Setuid(uid); err != nil {
+		return err
+	}
+	if os.Geteuid() == 0 {
+		return fmt.Errorf("still root after setuid(%d)", uid)
+	}
+	return nil
+}
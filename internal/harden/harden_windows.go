@@ -0,0 +1,12 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build windows
+
+package harden
+
+// applyProcessLimits is a no-op: Windows has no umask, and crash dumps are configured system wide.
+func applyProcessLimits() []string {
+	return nil
+}
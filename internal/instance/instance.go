@@ -0,0 +1,155 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package instance keeps two runs of the tool from writing to the same outputs at the same time.
+//
+// Each output is locked with an advisory lock on a lock file named after the output's absolute path. The operating
+// system releases the lock when the run exits, even if it crashes, so a lock file left behind never blocks a later run.
+// The lock file holds the process ID and start time of its owner, to point at the run that holds it.
+package instance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// errLocked is returned by tryLock if another open file holds the lock.
+var errLocked = errors.New("locked")
+
+type (
+	// Lock is held on an output for as long as the run writes to it.
+	Lock struct {
+		file *os.File
+		path string
+	}
+
+	// Owner is the run that holds a lock.
+	Owner struct {
+		PID     int       `json:"pid"`
+		Started time.Time `json:"started"`
+	}
+
+	// HeldError is returned if another run holds the lock of an output.
+	HeldError struct {
+		Output string
+		// Owner is nil if the lock file does not tell
+		Owner *Owner
+	}
+)
+
+func (e *HeldError) Error() string {
+	if e.Owner == nil {
+		return fmt.Sprintf("⚠ another run of the recovery tool is writing to `%s`. Wait for it to finish, or write somewhere else", e.Output)
+	}
+	return fmt.Sprintf("⚠ another run of the recovery tool (process %d, started %s) is writing to `%s`. Wait for it to finish, or write somewhere else",
+		e.Owner.PID, e.Owner.Started.Local().Format(time.DateTime), e.Output)
+}
+
+// Dir is the directory of the lock files, in the user cache directory, or else in the temporary directory.
+func Dir(name string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, name, "locks")
+}
+
+// Acquire locks an output file or directory, with a lock file in dir. It returns a *HeldError if another run holds it.
+func Acquire(dir, output string) (*Lock, error) {
+	path, err := lockFile(dir, output)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to lock `%s`: %s", output, err)
+	}
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("⚠ unable to lock `%s`: %s", output, err)
+	}
+
+	// a lock file removed by its owner between opening and locking it is locked again, under its new inode
+	for {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("⚠ unable to lock `%s`: %s", output, err)
+		}
+		if err = tryLock(file); err != nil {
+			_ = file.Close()
+			if errors.Is(err, errLocked) {
+				return nil, &HeldError{Output: output, Owner: readOwner(path)}
+			}
+			return nil, fmt.Errorf("⚠ unable to lock `%s`: %s", output, err)
+		}
+		opened, err1 := file.Stat()
+		current, err2 := os.Stat(path)
+		if err1 != nil || err2 != nil || !os.SameFile(opened, current) {
+			_ = file.Close()
+			continue
+		}
+		owner, err := json.Marshal(Owner{PID: os.Getpid(), Started: time.Now().UTC().Truncate(time.Second)})
+		if err == nil {
+			err = file.Truncate(0)
+		}
+		if err == nil {
+			_, err = file.WriteAt(owner, 0)
+		}
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("⚠ unable to lock `%s`: %s", output, err)
+		}
+		return &Lock{file: file, path: path}, nil
+	}
+}
+
+// AcquireAll locks the outputs in order, each once. If one is held, the locks already acquired are released.
+func AcquireAll(dir string, outputs []string) ([]*Lock, error) {
+	locks := make([]*Lock, 0, len(outputs))
+	locked := make(map[string]bool)
+	for _, output := range outputs {
+		// an output named twice is locked once
+		if path, err := lockFile(dir, output); err == nil && locked[path] {
+			continue
+		}
+		lock, err := Acquire(dir, output)
+		if err != nil {
+			ReleaseAll(locks)
+			return nil, err
+		}
+		locks = append(locks, lock)
+		locked[lock.path] = true
+	}
+	return locks, nil
+}
+
+// ReleaseAll releases the locks.
+func ReleaseAll(locks []*Lock) {
+	for _, lock := range locks {
+		lock.Release()
+	}
+}
+
+// lockFile is the lock file of an output, named after its absolute path.
+func lockFile(dir, output string) (string, error) {
+	abs, err := filepath.Abs(output)
+	if err != nil {
+		return "", err
+	}
+	name := sha256.Sum256([]byte(abs))
+	return filepath.Join(dir, hex.EncodeToString(name[:8])+".lock"), nil
+}
+
+func readOwner(path string) *Owner {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	owner := new(Owner)
+	if err = json.Unmarshal(content, owner); err != nil || owner.PID == 0 {
+		return nil
+	}
+	return owner
+}
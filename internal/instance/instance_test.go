@@ -0,0 +1,81 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package instance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire_HeldByAnotherRun(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := Acquire(dir, "wallet.json")
+	require.NoError(t, err)
+
+	// the same output, by another path, is held
+	abs, err := filepath.Abs("wallet.json")
+	require.NoError(t, err)
+	_, err = Acquire(dir, abs)
+	var held *HeldError
+	require.ErrorAs(t, err, &held)
+	assert.Equal(t, abs, held.Output)
+	require.NotNil(t, held.Owner)
+	assert.Equal(t, os.Getpid(), held.Owner.PID)
+	assert.ErrorContains(t, err, "another run of the recovery tool (process ")
+	assert.ErrorContains(t, err, "is writing to `"+abs+"`")
+
+	// other outputs are not
+	other, err := Acquire(dir, "other.json")
+	require.NoError(t, err)
+	other.Release()
+
+	lock.Release()
+	lock, err = Acquire(dir, abs)
+	require.NoError(t, err)
+	lock.Release()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAcquire_LeftoverLockFile(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := Acquire(dir, "shares")
+	require.NoError(t, err)
+	// as left behind by a run that exited without releasing it
+	require.NoError(t, lock.file.Close())
+
+	lock, err = Acquire(dir, "shares")
+	require.NoError(t, err)
+	lock.Release()
+}
+
+func TestAcquireAll_ReleasesOnConflict(t *testing.T) {
+	dir := t.TempDir()
+	held, err := Acquire(dir, "b")
+	require.NoError(t, err)
+	defer held.Release()
+
+	locks, err := AcquireAll(dir, []string{"a", "./a", "c"})
+	require.NoError(t, err)
+	assert.Len(t, locks, 2)
+	ReleaseAll(locks)
+
+	_, err = AcquireAll(dir, []string{"a", "b"})
+	assert.ErrorContains(t, err, "is writing to `b`")
+	// a was released
+	lock, err := Acquire(dir, "a")
+	require.NoError(t, err)
+	lock.Release()
+}
+
+func TestHeldError_UnknownOwner(t *testing.T) {
+	err := &HeldError{Output: "wallet.json"}
+	assert.Equal(t, "⚠ another run of the recovery tool is writing to `wallet.json`. Wait for it to finish, or write somewhere else", err.Error())
+}
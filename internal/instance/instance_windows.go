@@ -0,0 +1,31 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build windows
+
+package instance
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLock locks a byte far past the end of the file, without waiting for it, so that the owner stays readable.
+func tryLock(file *os.File) error {
+	overlapped := &windows.Overlapped{Offset: 0xffffffff, OffsetHigh: 0x7fffffff}
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, overlapped)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLocked
+	}
+	return err
+}
+
+// Release unlocks the lock file, then removes it. Windows cannot remove an open file, and a file opened by another run
+// meanwhile is left in place.
+func (l *Lock) Release() {
+	_ = l.file.Close()
+	_ = os.Remove(l.path)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package keychain keeps the phrases of backup files in the OS keychain (macOS Keychain, Windows Credential Manager
+// or the Secret Service on Linux), for unattended recovery drills on dedicated DR machines. It is never used unless asked for.
+package keychain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+const service = "io-vault-recovery-drill"
+
+// ErrNotFound is returned when no phrase is stored for a backup file.
+var ErrNotFound = errors.New("no phrase stored in the keychain")
+
+// account is the keychain account of a backup file. It is the file's SHA-256, so a renamed or moved copy still finds its phrase.
+func account(fileSHA256 string) string {
+	return "backup-" + fileSHA256
+}
+
+// Store saves the phrase of the backup file with the given SHA-256, replacing any phrase stored before.
+func Store(fileSHA256, mnemonics string) error {
+	if err := keyring.Set(service, account(fileSHA256), mnemonics); err != nil {
+		return fmt.Errorf("⚠ could not store the phrase in the keychain: %w", err)
+	}
+	return nil
+}
+
+// Load returns the phrase of the backup file with the given SHA-256.
+func Load(fileSHA256 string) (string, error) {
+	mnemonics, err := keyring.Get(service, account(fileSHA256))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("⚠ could not read the keychain: %w", err)
+	}
+	return mnemonics, nil
+}
+
+// Delete removes the phrase of the backup file with the given SHA-256. Deleting a phrase that is not stored is not an error.
+func Delete(fileSHA256 string) error {
+	if err := keyring.Delete(service, account(fileSHA256)); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("⚠ could not delete the phrase from the keychain: %w", err)
+	}
+	return nil
+}
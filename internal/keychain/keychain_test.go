@@ -0,0 +1,31 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package keychain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestKeychain_StoreLoadDelete(t *testing.T) {
+	keyring.MockInit()
+	hash := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+
+	_, err := Load(hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, Store(hash, "word1 word2"))
+	mnemonics, err := Load(hash)
+	require.NoError(t, err)
+	assert.Equal(t, "word1 word2", mnemonics)
+
+	require.NoError(t, Delete(hash))
+	require.NoError(t, Delete(hash), "deleting twice is fine")
+	_, err = Load(hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package lock keeps recovered keys on screen only while the session is attended.
+package lock
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	// clearScreen moves the cursor home, clears the screen and the terminal's scrollback buffer
+	clearScreen = "\x1b[H\x1b[2J\x1b[3J"
+
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyBackspace = 0x08
+	keyDelete    = 0x7f
+)
+
+// Hold shows secrets until the user presses Enter, then clears the screen.
+// If no key is pressed for idle, the screen is cleared and the passphrase must be typed to show the secrets again.
+// The terminal is in raw mode while the secrets are held, so the passphrase is not echoed.
+func Hold(in, out *os.File, secrets, passphrase []byte, idle time.Duration) error {
+	if !term.IsTerminal(in.Fd()) {
+		return fmt.Errorf("⚠ the screen lock needs an interactive terminal")
+	}
+	state, err := term.MakeRaw(in.Fd())
+	if err != nil {
+		return fmt.Errorf("⚠ could not set up the terminal for the screen lock: %s", err)
+	}
+	defer func() { _ = term.Restore(in.Fd(), state) }()
+
+	keys := make(chan byte)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 1)
+		for {
+			if _, err := in.Read(buf); err != nil {
+				return
+			}
+			keys <- buf[0]
+		}
+	}()
+	hold(keys, out, secrets, passphrase, idle)
+	return nil
+}
+
+func hold(keys <-chan byte, out io.Writer, secrets, passphrase []byte, idle time.Duration) {
+	// raw mode does not translate newlines
+	shown := bytes.ReplaceAll(secrets, []byte("\n"), []byte("\r\n"))
+	defer clear(shown)
+	typed := make([]byte, 0, 64)
+	defer func() { clear(typed[:cap(typed)]) }()
+
+	show := func() {
+		_, _ = io.WriteString(out, clearScreen)
+		_, _ = out.Write(shown)
+		_, _ = fmt.Fprintf(out, "\r\nPress Enter to clear the screen and exit. The screen locks after %s without a key press.\r\n", idle)
+	}
+	prompt := func() {
+		_, _ = io.WriteString(out, "Screen locked. Type the session passphrase and press Enter to show the keys again, or press Ctrl+C to exit.\r\n")
+	}
+
+	locked := false
+	show()
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if !locked {
+				locked = true
+				_, _ = io.WriteString(out, clearScreen)
+				prompt()
+			}
+		case key, ok := <-keys:
+			if !ok || key == keyCtrlC || key == keyCtrlD {
+				_, _ = io.WriteString(out, clearScreen)
+				return
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(idle)
+
+			enter := key == '\r' || key == '\n'
+			switch {
+			case !locked && enter:
+				_, _ = io.WriteString(out, clearScreen)
+				return
+			case !locked:
+			case enter:
+				if subtle.ConstantTimeCompare(typed, passphrase) == 1 {
+					locked = false
+					show()
+				} else {
+					_, _ = io.WriteString(out, "⚠ Wrong passphrase.\r\n")
+					prompt()
+				}
+				clear(typed)
+				typed = typed[:0]
+			case key == keyBackspace || key == keyDelete:
+				if len(typed) > 0 {
+					typed = typed[:len(typed)-1]
+				}
+			default:
+				typed = append(typed, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package lock
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock_EnterClearsAndExits(t *testing.T) {
+	keys := make(chan byte, 1)
+	out := new(bytes.Buffer)
+	keys <- '\r'
+	hold(keys, out, []byte("secret key\n"), []byte("pass"), time.Minute)
+
+	assert.Contains(t, out.String(), "secret key\r\n")
+	assert.True(t, strings.HasSuffix(out.String(), clearScreen))
+	assert.NotContains(t, out.String(), "Screen locked")
+}
+
+func TestLock_IdleLocksAndPassphraseUnlocks(t *testing.T) {
+	keys := make(chan byte)
+	out := new(syncBuffer)
+	done := make(chan struct{})
+	go func() {
+		hold(keys, out, []byte("secret key"), []byte("pass"), 20*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return strings.Contains(out.String(), "Screen locked") }, time.Second, 5*time.Millisecond)
+	for _, key := range []byte("paxs\rpax\x7fss\r") {
+		keys <- key
+	}
+	// shown on the initial display and again after unlocking, but not after the wrong passphrase
+	assert.Eventually(t, func() bool { return strings.Count(out.String(), "secret key") == 2 }, time.Second, 5*time.Millisecond)
+	assert.Contains(t, out.String(), "Wrong passphrase")
+
+	keys <- keyCtrlC
+	<-done
+	assert.True(t, strings.HasSuffix(out.String(), clearScreen))
+}
+
+// syncBuffer is a bytes.Buffer that can be read while hold writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package observe looks, best effort, for running programs that may capture the recovered keys from the screen or the
+// clipboard: clipboard history managers, screen recorders, and screen sharing or remote desktop sessions. It only
+// reads the local process list and session settings, and runs no other program.
+package observe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Kind is what an observer may capture.
+type Kind string
+
+const (
+	ClipboardManager Kind = "clipboard history manager"
+	ScreenRecorder   Kind = "screen recorder"
+	ScreenSharing    Kind = "screen sharing or remote desktop"
+
+	// maxProcessName is the length that Linux truncates process names to
+	maxProcessName = 15
+)
+
+// Observer is a program or session that may capture what is shown or copied.
+type Observer struct {
+	Name string
+	Kind Kind
+}
+
+// knownPrograms are the process names of common observers, lower case and without .exe. Meeting apps are listed as
+// they share the screen while running, e.g. a recovery done during a recorded call.
+var knownPrograms = []struct {
+	process, name string
+	kind          Kind
+}{
+	{"copyq", "CopyQ", ClipboardManager},
+	{"clipit", "ClipIt", ClipboardManager},
+	{"parcellite", "Parcellite", ClipboardManager},
+	{"diodon", "Diodon", ClipboardManager},
+	{"gpaste-daemon", "GPaste", ClipboardManager},
+	{"klipper", "Klipper", ClipboardManager},
+	{"xfce4-clipman", "Clipman", ClipboardManager},
+	{"cliphist", "cliphist", ClipboardManager},
+	{"greenclip", "greenclip", ClipboardManager},
+	{"clipmenud", "clipmenu", ClipboardManager},
+	{"maccy", "Maccy", ClipboardManager},
+	{"flycut", "Flycut", ClipboardManager},
+	{"copyclip", "CopyClip", ClipboardManager},
+	{"paste", "Paste", ClipboardManager},
+	{"pastebot", "Pastebot", ClipboardManager},
+	{"ditto", "Ditto", ClipboardManager},
+	{"clipboardfusion", "ClipboardFusion", ClipboardManager},
+	{"clipclip", "ClipClip", ClipboardManager},
+
+	{"obs", "OBS Studio", ScreenRecorder},
+	{"obs64", "OBS Studio", ScreenRecorder},
+	{"simplescreenrecorder", "SimpleScreenRecorder", ScreenRecorder},
+	{"kazam", "Kazam", ScreenRecorder},
+	{"peek", "Peek", ScreenRecorder},
+	{"vokoscreenng", "vokoscreenNG", ScreenRecorder},
+	{"recordmydesktop", "recordMyDesktop", ScreenRecorder},
+	{"gpu-screen-recorder", "GPU Screen Recorder", ScreenRecorder},
+	{"wf-recorder", "wf-recorder", ScreenRecorder},
+	{"kooha", "Kooha", ScreenRecorder},
+	{"loom", "Loom", ScreenRecorder},
+	{"camtasia", "Camtasia", ScreenRecorder},
+	{"bdcam", "Bandicam", ScreenRecorder},
+	{"sharex", "ShareX", ScreenRecorder},
+	{"screenflow", "ScreenFlow", ScreenRecorder},
+	{"screencapture", "macOS screen capture", ScreenRecorder},
+
+	{"zoom", "Zoom", ScreenSharing},
+	{"zoom.us", "Zoom", ScreenSharing},
+	{"cpthost", "Zoom screen sharing", ScreenSharing},
+	{"teams", "Microsoft Teams", ScreenSharing},
+	{"ms-teams", "Microsoft Teams", ScreenSharing},
+	{"webex", "Webex", ScreenSharing},
+	{"ciscowebexstart", "Webex", ScreenSharing},
+	{"discord", "Discord", ScreenSharing},
+	{"teamviewer", "TeamViewer", ScreenSharing},
+	{"teamviewerd", "TeamViewer", ScreenSharing},
+	{"teamviewer_service", "TeamViewer", ScreenSharing},
+	{"anydesk", "AnyDesk", ScreenSharing},
+	{"rustdesk", "RustDesk", ScreenSharing},
+	{"parsecd", "Parsec", ScreenSharing},
+	{"x11vnc", "x11vnc", ScreenSharing},
+	{"x0vncserver", "TigerVNC", ScreenSharing},
+	{"xvnc", "VNC server", ScreenSharing},
+	{"vncserver", "VNC server", ScreenSharing},
+	{"winvnc", "VNC server", ScreenSharing},
+	{"tvnserver", "TightVNC", ScreenSharing},
+	{"xrdp", "xrdp", ScreenSharing},
+	{"remoting_host", "Chrome Remote Desktop", ScreenSharing},
+}
+
+// Detect returns the observers found. It is best effort: programs it does not know, or that run under another name or
+// on another machine, are not found, and nothing is returned if the process list cannot be read.
+func Detect() []Observer {
+	names, _ := processNames()
+	observers := matchProcesses(names)
+	observers = append(observers, sessionObservers(os.Getenv)...)
+	return append(observers, platformObservers()...)
+}
+
+// matchProcesses returns the known observers among the process names, once each, in the order of knownPrograms.
+func matchProcesses(names []string) []Observer {
+	running := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(filepath.Base(strings.TrimSpace(name)))
+		running[strings.TrimSuffix(name, ".exe")] = true
+	}
+	var observers []Observer
+	seen := make(map[Observer]bool)
+	for _, program := range knownPrograms {
+		observer := Observer{Name: program.name, Kind: program.kind}
+		if seen[observer] || !(running[program.process] || running[truncate(program.process)]) {
+			continue
+		}
+		seen[observer] = true
+		observers = append(observers, observer)
+	}
+	return observers
+}
+
+// truncate shortens a process name the way Linux does, so that long names are still matched.
+func truncate(process string) string {
+	if len(process) <= maxProcessName {
+		return ""
+	}
+	return process[:maxProcessName]
+}
+
+// sessionObservers detects a remote desktop session from the environment of this process.
+func sessionObservers(getenv func(string) string) []Observer {
+	var observers []Observer
+	if strings.HasPrefix(strings.ToUpper(getenv("SESSIONNAME")), "RDP-") {
+		observers = append(observers, Observer{Name: "Remote Desktop session", Kind: ScreenSharing})
+	}
+	if getenv("XRDP_SESSION") != "" {
+		observers = append(observers, Observer{Name: "xrdp session", Kind: ScreenSharing})
+	}
+	return observers
+}
@@ -0,0 +1,29 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build darwin
+
+package observe
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// processNames reads the name of every process from the kernel.
+func processNames() ([]string, error) {
+	procs, err := unix.SysctlKinfoProcSlice("kern.proc.all")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(procs))
+	for _, proc := range procs {
+		names = append(names, unix.ByteSliceToString(proc.Proc.P_comm[:]))
+	}
+	return names, nil
+}
+
+// platformObservers has nothing more to detect on macOS, which keeps no clipboard history.
+func platformObservers() []Observer {
+	return nil
+}
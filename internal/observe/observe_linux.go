@@ -0,0 +1,35 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build linux
+
+package observe
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const procDir = "/proc"
+
+// processNames reads the name of every process this user can see.
+func processNames() ([]string, error) {
+	comms, err := filepath.Glob(filepath.Join(procDir, "[0-9]*", "comm"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(comms))
+	for _, comm := range comms {
+		// processes may exit while they are listed
+		if name, err := os.ReadFile(comm); err == nil {
+			names = append(names, string(name))
+		}
+	}
+	return names, nil
+}
+
+// platformObservers has nothing more to detect on Linux.
+func platformObservers() []Observer {
+	return nil
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build !linux && !darwin && !windows
+
+package observe
+
+// processNames is not implemented on this platform, so only the session is checked.
+func processNames() ([]string, error) {
+	return nil, nil
+}
+
+func platformObservers() []Observer {
+	return nil
+}
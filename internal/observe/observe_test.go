@@ -0,0 +1,43 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package observe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserve_MatchProcesses(t *testing.T) {
+	assert.Empty(t, matchProcesses([]string{"bash\n", "systemd", "firefox", "obsidian", "zoomer"}))
+	assert.Equal(t, []Observer{
+		{"CopyQ", ClipboardManager},
+		{"OBS Studio", ScreenRecorder},
+		{"SimpleScreenRecorder", ScreenRecorder},
+		{"Zoom", ScreenSharing},
+		{"TeamViewer", ScreenSharing},
+	}, matchProcesses([]string{
+		"copyq\n",
+		"OBS64.EXE", "obs",
+		// Linux truncates process names to 15 characters
+		"simplescreenrec",
+		`C:\Program Files\Zoom\bin\Zoom.exe`, "zoom.us",
+		"TeamViewer_Service.exe",
+	}))
+}
+
+func TestObserve_SessionObservers(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+	assert.Empty(t, sessionObservers(env(map[string]string{"SESSIONNAME": "Console"})))
+	assert.Equal(t, []Observer{{"Remote Desktop session", ScreenSharing}}, sessionObservers(env(map[string]string{"SESSIONNAME": "RDP-Tcp#3"})))
+	assert.Equal(t, []Observer{{"xrdp session", ScreenSharing}}, sessionObservers(env(map[string]string{"XRDP_SESSION": "1"})))
+}
+
+func TestObserve_Detect(t *testing.T) {
+	// whatever runs on the test machine, detection must not fail
+	assert.NotPanics(t, func() { Detect() })
+}
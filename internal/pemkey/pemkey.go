@@ -0,0 +1,137 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package pemkey encodes recovered EC private keys as SEC1 and PKCS#8 PEM, for OpenSSL based tooling and HSM import
+// utilities. crypto/x509 only knows the NIST curves, so the ASN.1 structures are built here for secp256k1 as well.
+package pemkey
+
+import (
+	"crypto/ecdh"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	// SEC1BlockType is the PEM type of SEC1 keys, as written by `openssl ec`
+	SEC1BlockType = "EC PRIVATE KEY"
+	// PKCS8BlockType is the PEM type of unencrypted PKCS#8 keys, as written by `openssl pkcs8 -nocrypt`
+	PKCS8BlockType = "PRIVATE KEY"
+)
+
+var oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+// Curve is a named curve that private keys can be encoded for.
+type Curve struct {
+	Name string
+	OID  asn1.ObjectIdentifier
+	// publicKey derives the uncompressed public key of a private scalar
+	publicKey func(d []byte) ([]byte, error)
+}
+
+var (
+	Secp256k1 = Curve{"secp256k1", asn1.ObjectIdentifier{1, 3, 132, 0, 10}, func(d []byte) ([]byte, error) {
+		return secp256k1.PrivKeyFromBytes(d).PubKey().SerializeUncompressed(), nil
+	}}
+	P256 = Curve{"P-256", asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, func(d []byte) ([]byte, error) {
+		key, err := ecdh.P256().NewPrivateKey(d)
+		if err != nil {
+			return nil, err
+		}
+		return key.PublicKey().Bytes(), nil
+	}}
+
+	curves = []Curve{Secp256k1, P256}
+)
+
+// ecPrivateKey is the SEC1 (RFC 5915) ECPrivateKey structure.
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// pkcs8 is the PKCS#8 (RFC 5208) PrivateKeyInfo structure.
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+func marshalECPrivateKey(c Curve, d []byte, oid asn1.ObjectIdentifier) ([]byte, error) {
+	if len(d) != 32 {
+		return nil, fmt.Errorf("⚠ a %s private key must be 32 bytes, got %d", c.Name, len(d))
+	}
+	pub, err := c.publicKey(d)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ invalid %s private key: %v", c.Name, err)
+	}
+	return asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    d,
+		NamedCurveOID: oid,
+		PublicKey:     asn1.BitString{Bytes: pub, BitLength: 8 * len(pub)},
+	})
+}
+
+// MarshalSEC1 encodes a private scalar as a DER SEC1 ECPrivateKey, with the curve and the public key.
+func MarshalSEC1(c Curve, d []byte) ([]byte, error) {
+	return marshalECPrivateKey(c, d, c.OID)
+}
+
+// MarshalPKCS8 encodes a private scalar as a DER PKCS#8 PrivateKeyInfo. Like crypto/x509, the curve is only named in
+// the algorithm identifier.
+func MarshalPKCS8(c Curve, d []byte) ([]byte, error) {
+	sec1, err := marshalECPrivateKey(c, d, nil)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(c.OID)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs8{
+		Algo:       pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: params}},
+		PrivateKey: sec1,
+	})
+}
+
+// EncodeSEC1 returns the PEM encoding of MarshalSEC1.
+func EncodeSEC1(c Curve, d []byte) ([]byte, error) {
+	der, err := MarshalSEC1(c, d)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: SEC1BlockType, Bytes: der}), nil
+}
+
+// EncodePKCS8 returns the PEM encoding of MarshalPKCS8.
+func EncodePKCS8(c Curve, d []byte) ([]byte, error) {
+	der, err := MarshalPKCS8(c, d)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PKCS8BlockType, Bytes: der}), nil
+}
+
+// ParseSEC1 decodes a DER SEC1 ECPrivateKey of a known curve, to check exported keys.
+func ParseSEC1(der []byte) (Curve, []byte, error) {
+	key := new(ecPrivateKey)
+	if rest, err := asn1.Unmarshal(der, key); err != nil {
+		return Curve{}, nil, err
+	} else if len(rest) > 0 {
+		return Curve{}, nil, errors.New("trailing data after the EC private key")
+	}
+	for _, c := range curves {
+		if key.NamedCurveOID.Equal(c.OID) {
+			return c, key.PrivateKey, nil
+		}
+	}
+	return Curve{}, nil, fmt.Errorf("unknown curve %s", key.NamedCurveOID)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package pemkey
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func p256Key(t *testing.T, d []byte) *ecdsa.PrivateKey {
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	key.Curve = elliptic.P256()
+	key.X, key.Y = key.Curve.ScalarBaseMult(d)
+	return key
+}
+
+func TestPEMKey_P256MatchesX509(t *testing.T) {
+	d, _ := hex.DecodeString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	key := p256Key(t, d)
+
+	sec1, err := MarshalSEC1(P256, d)
+	require.NoError(t, err)
+	expected, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	assert.Equal(t, expected, sec1)
+	parsed, err := x509.ParseECPrivateKey(sec1)
+	require.NoError(t, err)
+	assert.Equal(t, d, parsed.D.FillBytes(make([]byte, 32)))
+
+	p8, err := MarshalPKCS8(P256, d)
+	require.NoError(t, err)
+	expected, err = x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	assert.Equal(t, expected, p8)
+	parsedAny, err := x509.ParsePKCS8PrivateKey(p8)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsedAny))
+}
+
+func TestPEMKey_Secp256k1(t *testing.T) {
+	d, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+
+	encoded, err := EncodeSEC1(Secp256k1, d)
+	require.NoError(t, err)
+	block, rest := pem.Decode(encoded)
+	require.NotNil(t, block)
+	assert.Empty(t, rest)
+	assert.Equal(t, SEC1BlockType, block.Type)
+	curve, parsed, err := ParseSEC1(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, "secp256k1", curve.Name)
+	assert.Equal(t, d, parsed)
+	// the structure only differs from a P-256 key in its curve and public key, as produced by `openssl ecparam -name secp256k1`
+	assert.Equal(t, "30740201010420"+hex.EncodeToString(d)+"a00706052b8104000aa144034200"+
+		hex.EncodeToString(secp256k1.PrivKeyFromBytes(d).PubKey().SerializeUncompressed()), hex.EncodeToString(block.Bytes))
+
+	// crypto/x509 does not know secp256k1, but reads the envelope of the PKCS#8 key up to the curve
+	encoded, err = EncodePKCS8(Secp256k1, d)
+	require.NoError(t, err)
+	block, _ = pem.Decode(encoded)
+	assert.Equal(t, PKCS8BlockType, block.Type)
+	_, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.ErrorContains(t, err, "unknown elliptic curve")
+
+	_, err = MarshalSEC1(Secp256k1, d[1:])
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package qr reads backup files that were printed as a series of QR codes and scanned back in as images.
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ur"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// ReadBackup decodes the QR code images in dir and reassembles the backup file JSON they contain.
+// The QR codes hold either the whole JSON document, or the parts of a multi-part "bytes" UR in any order.
+func ReadBackup(dir string) ([]byte, error) {
+	payloads, err := DecodeDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(payloads) == 1 && strings.HasPrefix(strings.TrimSpace(payloads[0]), "{") {
+		return []byte(payloads[0]), nil
+	}
+	for i, payload := range payloads {
+		if !ur.IsUR(payload) {
+			return nil, fmt.Errorf("⚠ QR code %d of %d in `%s` is neither JSON nor a UR part", i+1, len(payloads), dir)
+		}
+	}
+	content, err := ur.Decode(payloads)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to reassemble the QR codes in `%s`: %s", dir, err)
+	}
+	return content, nil
+}
+
+// DecodeDir decodes every PNG and JPEG image in dir, in filename order, and returns the text of their QR codes.
+func DecodeDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to read QR code directory `%s`: %s", dir, err)
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".png", ".jpg", ".jpeg":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("⚠ no PNG or JPEG images found in `%s`", dir)
+	}
+	sort.Strings(files)
+
+	payloads := make([]string, len(files))
+	for i, file := range files {
+		if payloads[i], err = DecodeImage(file); err != nil {
+			return nil, err
+		}
+	}
+	return payloads, nil
+}
+
+// DecodeImage returns the text of the QR code in an image file.
+func DecodeImage(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("⚠ unable to open image `%s`: %s", file, err)
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", fmt.Errorf("⚠ unable to decode image `%s`: %s", file, err)
+	}
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", fmt.Errorf("⚠ unable to read image `%s`: %s", file, err)
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+	result, err := qrcode.NewQRCodeReader().Decode(bmp, hints)
+	if err != nil {
+		return "", fmt.Errorf("⚠ no readable QR code found in `%s`: %s", file, err)
+	}
+	if result.GetText() == "" {
+		return "", errors.New("⚠ empty QR code in " + file)
+	}
+	return result.GetText(), nil
+}
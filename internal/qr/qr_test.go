@@ -0,0 +1,71 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package qr
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ur"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestImages writes each payload as a QR code PNG into dir.
+func writeTestImages(t *testing.T, dir string, payloads []string) {
+	for i, payload := range payloads {
+		matrix, err := qrcode.NewQRCodeWriter().Encode(payload, gozxing.BarcodeFormat_QR_CODE, 800, 800, nil)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("part-%02d.png", i+1)))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.NoError(t, png.Encode(f, matrix))
+		assert.NoError(t, f.Close())
+	}
+}
+
+func TestQR_ReadBackup_MultiPartUR(t *testing.T) {
+	content, err := os.ReadFile("../../test-files/new_single.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	dir := t.TempDir()
+	parts := ur.Encode(content, 400)
+	writeTestImages(t, dir, parts)
+
+	decoded, err := ReadBackup(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, content, decoded)
+}
+
+func TestQR_ReadBackup_PlainJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeTestImages(t, dir, []string{`{"vaults":{}}`})
+
+	decoded, err := ReadBackup(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `{"vaults":{}}`, string(decoded))
+}
+
+func TestQR_ReadBackup_Errors(t *testing.T) {
+	_, err := ReadBackup(t.TempDir())
+	assert.ErrorContains(t, err, "no PNG or JPEG images")
+
+	dir := t.TempDir()
+	writeTestImages(t, dir, []string{"hello", "world"})
+	_, err = ReadBackup(dir)
+	assert.ErrorContains(t, err, "neither JSON nor a UR part")
+}
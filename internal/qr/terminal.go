@@ -0,0 +1,44 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package qr
+
+import (
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// Terminal renders text as a QR code made of half-block characters, two module rows per line.
+// Light modules are drawn as blocks, so the code scans on the usual light-on-dark terminal.
+func Terminal(text string) (string, error) {
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_MARGIN: 2}
+	matrix, err := qrcode.NewQRCodeWriter().Encode(text, gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+	if err != nil {
+		return "", err
+	}
+	width, height := matrix.GetWidth(), matrix.GetHeight()
+	light := func(x, y int) bool {
+		return y >= height || !matrix.Get(x, y)
+	}
+
+	var sb strings.Builder
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			switch top, bottom := light(x, y), light(x, y+1); {
+			case top && bottom:
+				sb.WriteString("█")
+			case top:
+				sb.WriteString("▀")
+			case bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
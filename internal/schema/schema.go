@@ -0,0 +1,257 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package schema validates the structure of JSON documents, reporting the path and byte offset of the first malformed
+// value instead of a generic unmarshal error.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Kind is the expected kind of a JSON value.
+type Kind int
+
+const (
+	// Object has known fields: the others are not validated.
+	Object Kind = iota
+	// Map has arbitrary keys, with values of the same schema.
+	Map
+	Array
+	String
+	Number
+	Integer
+	Bool
+)
+
+type (
+	// Node is the schema of a JSON value.
+	Node struct {
+		Kind Kind
+		// Fields are the known fields of an Object, and Required those that must be present
+		Fields   map[string]*Node
+		Required []string
+		// Elem is the schema of the values of a Map, or of the elements of an Array
+		Elem *Node
+		// Key checks the keys of a Map
+		Key func(string) error
+		// Format checks the value of a String
+		Format   func(string) error
+		Nullable bool
+	}
+
+	// Error is the first malformed value of a document.
+	Error struct {
+		// Path is the dotted path of the value, e.g. vaults.abc123.0.cipherparams.iv
+		Path string
+		// Pattern is the path with the keys of maps replaced by *, for reports that must not include them
+		Pattern string
+		// Offset is the byte offset of the value in the document
+		Offset  int64
+		Problem string
+	}
+)
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s at offset %d", e.where(e.Path), e.Problem, e.Offset)
+}
+
+// Anonymized is the error with the map keys of its path left out.
+func (e *Error) Anonymized() string {
+	return fmt.Sprintf("%s: %s at offset %d", e.where(e.Pattern), e.Problem, e.Offset)
+}
+
+func (e *Error) where(path string) string {
+	if path == "" {
+		return "document"
+	}
+	return path
+}
+
+var kindNames = map[Kind]string{
+	Object: "an object", Map: "an object", Array: "an array", String: "a string", Number: "a number", Integer: "an integer",
+	Bool: "a boolean",
+}
+
+// Validate checks the document against the schema. It returns an *Error for the first malformed value.
+func Validate(content []byte, root *Node) error {
+	v := &validator{content: content, dec: json.NewDecoder(bytes.NewReader(content))}
+	v.dec.UseNumber()
+	if err := v.value(root, nil, nil); err != nil {
+		return err
+	}
+	offset := v.valueOffset()
+	if _, err := v.dec.Token(); !errors.Is(err, io.EOF) {
+		return &Error{Offset: offset, Problem: "unexpected data after the document"}
+	}
+	return nil
+}
+
+type validator struct {
+	content []byte
+	dec     *json.Decoder
+}
+
+// valueOffset is the offset of the next value, after the separators that the decoder has not consumed yet.
+func (v *validator) valueOffset() int64 {
+	offset := v.dec.InputOffset()
+	for offset < int64(len(v.content)) && strings.IndexByte(" \t\r\n:,", v.content[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func (v *validator) fail(path, pattern []string, offset int64, problem string) *Error {
+	return &Error{Path: strings.Join(path, "."), Pattern: strings.Join(pattern, "."), Offset: offset, Problem: problem}
+}
+
+func (v *validator) token(path, pattern []string) (json.Token, int64, error) {
+	offset := v.valueOffset()
+	tok, err := v.dec.Token()
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr) && syntaxErr.Error() != "unexpected end of JSON input":
+			return nil, syntaxErr.Offset, v.fail(path, pattern, syntaxErr.Offset, syntaxErr.Error())
+		case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return nil, offset, v.fail(path, pattern, offset, "unexpected end of the document")
+		}
+		return nil, offset, v.fail(path, pattern, offset, err.Error())
+	}
+	return tok, offset, nil
+}
+
+func (v *validator) value(node *Node, path, pattern []string) error {
+	tok, offset, err := v.token(path, pattern)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return v.skip(tok, path, pattern)
+	}
+	if tok == nil {
+		if node.Nullable {
+			return nil
+		}
+		return v.fail(path, pattern, offset, fmt.Sprintf("expected %s, got null", kindNames[node.Kind]))
+	}
+	mismatch := func() error {
+		return v.fail(path, pattern, offset, fmt.Sprintf("expected %s, got %s", kindNames[node.Kind], tokenKind(tok)))
+	}
+
+	switch node.Kind {
+	case Object, Map:
+		if tok != json.Delim('{') {
+			return mismatch()
+		}
+		seen := make(map[string]bool)
+		for v.dec.More() {
+			keyTok, keyOffset, err := v.token(path, pattern)
+			if err != nil {
+				return err
+			}
+			key := keyTok.(string)
+			child, childPattern := node.Fields[key], key
+			if node.Kind == Map {
+				child, childPattern = node.Elem, "*"
+				if node.Key != nil {
+					if err := node.Key(key); err != nil {
+						return v.fail(append(path, key), append(pattern, childPattern), keyOffset, err.Error())
+					}
+				}
+			}
+			seen[key] = true
+			if err := v.value(child, append(path, key), append(pattern, childPattern)); err != nil {
+				return err
+			}
+		}
+		if _, _, err := v.token(path, pattern); err != nil {
+			return err
+		}
+		for _, field := range node.Required {
+			if !seen[field] {
+				return v.fail(path, pattern, offset, fmt.Sprintf("missing field %s", field))
+			}
+		}
+	case Array:
+		if tok != json.Delim('[') {
+			return mismatch()
+		}
+		for i := 0; v.dec.More(); i++ {
+			index := strconv.Itoa(i)
+			if err := v.value(node.Elem, append(path, index), append(pattern, index)); err != nil {
+				return err
+			}
+		}
+		if _, _, err := v.token(path, pattern); err != nil {
+			return err
+		}
+	case String:
+		s, ok := tok.(string)
+		if !ok {
+			return mismatch()
+		}
+		if node.Format != nil {
+			if err := node.Format(s); err != nil {
+				return v.fail(path, pattern, offset, err.Error())
+			}
+		}
+	case Number, Integer:
+		n, ok := tok.(json.Number)
+		if !ok {
+			return mismatch()
+		}
+		if _, err := n.Int64(); node.Kind == Integer && err != nil {
+			return mismatch()
+		}
+	case Bool:
+		if _, ok := tok.(bool); !ok {
+			return mismatch()
+		}
+	}
+	return nil
+}
+
+// skip reads past a value that has no schema.
+func (v *validator) skip(tok json.Token, path, pattern []string) error {
+	if tok != json.Delim('{') && tok != json.Delim('[') {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		tok, _, err := v.token(path, pattern)
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+func tokenKind(tok json.Token) string {
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return "an object"
+		}
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "null"
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = &Node{
+	Kind: Object,
+	Fields: map[string]*Node{
+		"vaults": {
+			Kind: Map,
+			Key: func(key string) error {
+				if strings.HasPrefix(key, "-") {
+					return errors.New("not a vault ID")
+				}
+				return nil
+			},
+			Elem: &Node{Kind: Object, Fields: map[string]*Node{
+				"iv": {Kind: String, Format: func(s string) error {
+					if strings.Trim(s, "0123456789abcdef") != "" {
+						return errors.New("not hex")
+					}
+					return nil
+				}},
+				"n":    {Kind: Integer},
+				"ok":   {Kind: Bool},
+				"list": {Kind: Array, Elem: &Node{Kind: Number}, Nullable: true},
+			}, Required: []string{"iv"}},
+		},
+	},
+	Required: []string{"vaults"},
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name, content, path, pattern, problem string
+		offset                                int64
+	}{
+		{"not hex", `{"vaults": {"abc123": {"iv": "0g"}}}`, "vaults.abc123.iv", "vaults.*.iv", "not hex", 29},
+		{"wrong kind", `{"vaults": {"abc123": {"iv": 12}}}`, "vaults.abc123.iv", "vaults.*.iv", "expected a string, got a number", 29},
+		{"not an integer", `{"vaults": {"a": {"iv": "", "n": 1.5}}}`, "vaults.a.n", "vaults.*.n", "expected an integer, got a number", 33},
+		{"array element", `{"vaults": {"a": {"iv": "", "list": [1, "2"]}}}`, "vaults.a.list.1", "vaults.*.list.1", "expected a number, got a string", 40},
+		{"null", `{"vaults": {"a": {"iv": "", "ok": null}}}`, "vaults.a.ok", "vaults.*.ok", "expected a boolean, got null", 34},
+		{"missing field", `{"vaults": {"a": {}}}`, "vaults.a", "vaults.*", "missing field iv", 17},
+		{"bad key", `{"vaults": {"-a": {"iv": ""}}}`, "vaults.-a", "vaults.*", "not a vault ID", 12},
+		{"missing root field", `{}`, "", "", "missing field vaults", 0},
+		{"root kind", `[]`, "", "", "expected an object, got an array", 0},
+		{"syntax", `{"vaults": {"a": {"iv": "", }}}`, "vaults.a", "vaults.*", "invalid character ',' looking for beginning of value", 27},
+		{"truncated", `{"vaults": {"a": {"iv": ""`, "vaults.a", "vaults.*", "unexpected end of the document", 26},
+		{"trailing data", `{"vaults": {}} {}`, "", "", "unexpected data after the document", 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schemaErr *Error
+			require.ErrorAs(t, Validate([]byte(tt.content), testSchema), &schemaErr)
+			assert.Equal(t, tt.path, schemaErr.Path)
+			assert.Equal(t, tt.pattern, schemaErr.Pattern)
+			assert.Equal(t, tt.problem, schemaErr.Problem)
+			assert.Equal(t, tt.offset, schemaErr.Offset)
+		})
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	// unknown fields, at any depth, are not validated
+	content := `{"version": 2, "vaults": {"a": {"iv": "00ff", "n": 3, "ok": true, "list": null, "extra": {"x": [1, {}]}}}}`
+	assert.NoError(t, Validate([]byte(content), testSchema))
+}
+
+func TestError(t *testing.T) {
+	err := &Error{Path: "vaults.abc123.0.cipherparams.iv", Pattern: "vaults.*.0.cipherparams.iv", Offset: 10234, Problem: "not hex"}
+	assert.Equal(t, "vaults.abc123.0.cipherparams.iv: not hex at offset 10234", err.Error())
+	assert.Equal(t, "vaults.*.0.cipherparams.iv: not hex at offset 10234", err.Anonymized())
+	assert.Equal(t, "document: missing field vaults at offset 0", (&Error{Problem: "missing field vaults"}).Error())
+}
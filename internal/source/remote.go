@@ -0,0 +1,78 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package source
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxRemoteSize is far above the size of any backup file, and only guards memory against a wrong URL
+const maxRemoteSize = 64 << 20
+
+// client fetches remote backups. It follows redirects to HTTPS only, and sends no credentials other than those in the URL.
+var client = &http.Client{
+	Timeout: 2 * time.Minute,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme != "https" {
+			return errors.New("redirect away from https")
+		}
+		if len(via) >= 5 {
+			return errors.New("too many redirects")
+		}
+		return nil
+	},
+}
+
+// IsRemote reports whether an input is a URL rather than a local path.
+func IsRemote(input string) bool {
+	lower := strings.ToLower(input)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+// DisplayName returns a URL without its query string and fragment, which hold the signature and credentials of
+// pre-signed S3, GCS and Azure URLs, so that it can be shown on screen.
+func DisplayName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid URL)"
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+}
+
+// FetchRemote downloads a backup object from a pre-signed HTTPS URL (S3 pre-signed URL, GCS signed URL or Azure SAS URL)
+// into memory. Nothing is written to disk. Errors never include the query string of the URL.
+func FetchRemote(rawURL string) ([]byte, error) {
+	name := DisplayName(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" {
+		return nil, fmt.Errorf("⚠ remote backup `%s` must be an https:// URL", name)
+	}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("⚠ unable to download remote backup `%s`: %s", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("⚠ unable to download remote backup `%s`: %s. Check that the signed URL has not expired", name, resp.Status)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to download remote backup `%s`: %s", name, err)
+	}
+	if len(content) > maxRemoteSize {
+		return nil, fmt.Errorf("⚠ remote backup `%s` is larger than %d MiB, is this a backup file?", name, maxRemoteSize>>20)
+	}
+	return content, nil
+}
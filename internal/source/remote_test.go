@@ -0,0 +1,55 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package source
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRemote(t *testing.T) {
+	assert.True(t, IsRemote("https://bucket.s3.amazonaws.com/backup.json"))
+	assert.True(t, IsRemote("HTTP://example.com/backup.json"))
+	assert.False(t, IsRemote("./backups/https-backup.json"))
+}
+
+func TestDisplayName(t *testing.T) {
+	assert.Equal(t, "https://bucket.s3.amazonaws.com/dr/backup.json",
+		DisplayName("https://bucket.s3.amazonaws.com/dr/backup.json?X-Amz-Signature=abc&X-Amz-Credential=def#frag"))
+}
+
+func TestFetchRemote(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("sig") != "secret":
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/redirect":
+			http.Redirect(w, r, "http://example.com/backup.json", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte(`{"vaults":{}}`))
+		}
+	}))
+	defer srv.Close()
+	transport := client.Transport
+	client.Transport = srv.Client().Transport
+	defer func() { client.Transport = transport }()
+
+	content, err := FetchRemote(srv.URL + "/backup.json?sig=secret")
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"vaults":{}}`, string(content))
+	}
+
+	for _, rawURL := range []string{srv.URL + "/backup.json?sig=wrong", srv.URL + "/redirect?sig=secret"} {
+		_, err = FetchRemote(rawURL)
+		if assert.Error(t, err) {
+			assert.NotContains(t, err.Error(), "sig=", "the signature must not leak into errors")
+		}
+	}
+	_, err = FetchRemote("http://example.com/backup.json")
+	assert.ErrorContains(t, err, "https://")
+}
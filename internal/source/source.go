@@ -0,0 +1,33 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package source checks the locations that backup files are read from, such as WebDAV or SMB shares mounted on the recovery machine.
+// The tool only ever opens input files for reading and never writes temporary files next to them.
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CheckReadOnly returns an error if the tool could write to an input file, or to the directory that holds it.
+// It is a preflight check for operators who require backup shares to be mounted read-only; nothing is written to find out.
+func CheckReadOnly(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("⚠ unable to see `%s` - does it exist?: %s", path, err)
+	}
+	dir := path
+	if !info.IsDir() {
+		if writable(path, false) {
+			return fmt.Errorf("⚠ input file `%s` is writable by this tool; mount the share read-only or drop -readonly-source", path)
+		}
+		dir = filepath.Dir(path)
+	}
+	if writable(dir, true) {
+		return fmt.Errorf("⚠ input location `%s` is writable by this tool; mount the share read-only or drop -readonly-source", dir)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSource_CheckReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "backup.json")
+	if !assert.NoError(t, os.WriteFile(file, []byte("{}"), 0600)) {
+		return
+	}
+	assert.ErrorContains(t, CheckReadOnly(file), "writable")
+	assert.ErrorContains(t, CheckReadOnly(filepath.Join(dir, "missing.json")), "does it exist")
+
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root outside of read-only mounts")
+	}
+	assert.NoError(t, os.Chmod(file, 0400))
+	assert.NoError(t, os.Chmod(dir, 0500))
+	defer os.Chmod(dir, 0700)
+	assert.NoError(t, CheckReadOnly(file))
+}
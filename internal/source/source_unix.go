@@ -0,0 +1,16 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build !windows
+
+package source
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// writable asks the kernel whether the path could be written, which also reports read-only mounts (EROFS) for root.
+func writable(path string, _ bool) bool {
+	return unix.Access(path, unix.W_OK) == nil
+}
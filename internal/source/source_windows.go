@@ -0,0 +1,25 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build windows
+
+package source
+
+import (
+	"os"
+)
+
+// writable opens a file for writing without creating or truncating it, then closes it again, leaving it untouched.
+// Windows does not report directory write permissions through file modes, so directories are judged by their files.
+func writable(path string, isDir bool) bool {
+	if isDir {
+		return false
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	return true
+}
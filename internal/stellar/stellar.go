@@ -0,0 +1,45 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package stellar encodes the recovered Ed25519 key of a vault as a Stellar account ID, the G... strkey (SEP-23).
+//
+// There is no encoder for the S... secret seed: a Stellar seed is an Ed25519 seed that wallets hash into the signing
+// scalar, while the vault key is the scalar itself. A seed strkey made from the vault key would open another account
+// than the vault's.
+package stellar
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+)
+
+// versionAccountID is the strkey version byte of account IDs, 6 << 3, which encodes to a leading G
+const versionAccountID = 6 << 3
+
+// AccountID returns the Stellar account ID of a 32 byte Ed25519 public key.
+func AccountID(edPK []byte) string {
+	return strkey(versionAccountID, edPK)
+}
+
+// strkey encodes the version byte and payload in base32, with the CRC16-XModem checksum of both in little-endian.
+func strkey(version byte, payload []byte) string {
+	data := append([]byte{version}, payload...)
+	data = binary.LittleEndian.AppendUint16(data, crc16XModem(data))
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(data)
+}
+
+func crc16XModem(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
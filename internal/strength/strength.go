@@ -0,0 +1,83 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package strength estimates how hard a password is to guess, to refuse weak passwords for exported files.
+package strength
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// MinBits is the minimum estimated entropy of passwords for exported files. Exported files can be attacked offline,
+// so they need more than a login password.
+const MinBits = 60
+
+// common are frequent passwords and words that guessing tools try first, also with digits or symbols appended.
+var common = []string{
+	"password", "passw0rd", "p@ssword", "p@ssw0rd", "qwerty", "qwertyuiop", "asdfgh", "zxcvbn", "letmein", "welcome",
+	"admin", "administrator", "iloveyou", "monkey", "dragon", "master", "secret", "changeme", "default", "login",
+	"abc123", "trustno1", "sunshine", "princess", "football", "baseball", "shadow", "superman", "bitcoin", "ethereum",
+	"crypto", "wallet", "metamask", "ledger", "recovery", "backup", "finnet", "iofinnet", "vault",
+}
+
+// EstimateBits estimates the entropy of a password in bits, by its length and character classes. Runs of a repeated
+// character and straight sequences such as "abcd" or "1234" count as a single character, and common passwords with
+// only digits or symbols added count as nothing.
+func EstimateBits(password string) float64 {
+	base := strings.ToLower(strings.TrimRightFunc(password, func(r rune) bool { return !unicode.IsLetter(r) }))
+	for _, word := range common {
+		if base == word || strings.ToLower(password) == word {
+			return 0
+		}
+	}
+
+	var lower, upper, digit, space, other bool
+	effective := 0
+	var prev rune
+	for i, r := range []rune(password) {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case r == ' ':
+			space = true
+		default:
+			other = true
+		}
+		if i > 0 && (r == prev || r == prev+1 || r == prev-1) {
+			prev = r
+			continue
+		}
+		prev = r
+		effective++
+	}
+	charset := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {space, 1}, {other, 33}} {
+		if class.present {
+			charset += class.size
+		}
+	}
+	if charset == 0 {
+		return 0
+	}
+	return float64(effective) * math.Log2(float64(charset))
+}
+
+// Check refuses passwords estimated below MinBits. The name of the password is used in the error, e.g. "-password".
+func Check(name, password string) error {
+	if bits := EstimateBits(password); bits < MinBits {
+		return fmt.Errorf("⚠ the %s is too weak (about %.0f bits, at least %d needed): use a longer passphrase of several unrelated words, "+
+			"or mix upper and lower case letters, digits and symbols", name, bits, MinBits)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package strength
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrength(t *testing.T) {
+	weak := []string{"", "password", "Password123!", "qwerty2024", "aaaaaaaaaaaaaaaaaaaa", "abcdefghijklmnopqrstuvwxyz", "12345678901234567890", "Tr0ub4dor"}
+	for _, password := range weak {
+		assert.Error(t, Check("-password", password), password)
+	}
+	strong := []string{"a strong password", "correct horse battery staple", "k8#Vq!2zR@w9Lp"}
+	for _, password := range strong {
+		assert.NoError(t, Check("-password", password), password)
+	}
+	assert.ErrorContains(t, Check("-bundle-password", "letmein"), "-bundle-password is too weak")
+}
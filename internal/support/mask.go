@@ -0,0 +1,36 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package support
+
+import (
+	"regexp"
+)
+
+// maskKeep is the number of characters kept at each end of a masked value.
+const maskKeep = 4
+
+// maskPattern matches the keys and addresses of terminal output, including right after the ANSI code that makes them
+// bold. The base58 alternative needs 26 characters, so that the 24 and 25 character vault IDs are kept.
+var maskPattern = regexp.MustCompile(`(^|[^0-9A-Za-z]|\x1b\[[0-9;]*m)(` +
+	`(?i:bc1|tb1)[0-9a-zA-Z]{20,}|` + // bech32 addresses
+	`[a-z]{2,20}1[02-9ac-hj-np-z]{38,}|` + // bech32 addresses of Cosmos SDK chains
+	`(?:0x)?[0-9a-fA-F]{32,}|` + // hex keys and ETH addresses
+	`G[A-Z2-7]{55}|` + // Stellar account IDs
+	`[1-9A-HJ-NP-Za-km-z]{26,}` + // base58: WIFs, Tron, Zcash, Horizen, Komodo, XRPL and SOL addresses
+	`)\b`)
+
+// Mask shortens every key and address in terminal output to its first and last 4 characters, so that a screen can be
+// shared without showing them. Phrases and QR codes are not masked.
+// The text is returned as a new slice, which can be cleared like the original.
+func Mask(text []byte) []byte {
+	return maskPattern.ReplaceAllFunc(text, func(match []byte) []byte {
+		sub := maskPattern.FindSubmatch(match)
+		value := sub[2]
+		masked := append([]byte{}, sub[1]...)
+		masked = append(masked, value[:maskKeep]...)
+		masked = append(masked, "…"...)
+		return append(masked, value[len(value)-maskKeep:]...)
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package support
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupport_Mask(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain line", "Inflated 4 V2 shares: 5.4 KB → 23.6 KB (4.4x).", "Inflated 4 V2 shares: 5.4 KB → 23.6 KB (4.4x)."},
+		{"eth address", "0x620Ac72121234f1b313BD4e8b78C81323502679A  (ECDSA master key)", "0x62…679A  (ECDSA master key)"},
+		{"bold hex key", "key: \x1b[1m4cc05b1d3216da8ef91729744159019b25ea1ed5932e387199f1de6ff6667ac2\x1b[0m\n", "key: \x1b[1m4cc0…7ac2\x1b[0m\n"},
+		{"wif", "WIF: L1CujRNEhNfZgTS9b6e3hytTDu7gpUv1kiLx4ETEEhEc8nJcx4QA", "WIF: L1Cu…x4QA"},
+		{"bech32", "Bitcoin mainnet (P2WPKH):  bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", "Bitcoin mainnet (P2WPKH):  bc1q…5mdq"},
+		{"cosmos", "Cosmos SDK (celestia): celestia1w508d6qejxtdg4y5r3zarvary0c5xw7kthx244", "Cosmos SDK (celestia): cele…x244"},
+		{"stellar", "Stellar: GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ", "Stellar: GA7Q…VSGZ"},
+		{"tron", "Tron: TJRyWwFs9wTFGZg3JbrVriFbNfCug5tDeC", "Tron: TJRy…tDeC"},
+		{"vault ids", "vault clujhtm9d0013wc3xso1b2m0k and phrot42ltzawmn7nrm7mqvl5", "vault clujhtm9d0013wc3xso1b2m0k and phrot42ltzawmn7nrm7mqvl5"},
+		{"xprv", "root: xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi", "root: xprv…MPHi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(Mask([]byte(tt.in))))
+		})
+	}
+}
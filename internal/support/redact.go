@@ -0,0 +1,98 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package support prepares data that is safe to attach to a support ticket.
+package support
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// secretPatterns match keys, seeds and addresses of every format the tool prints, erring on the side of redacting too much
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:0x)?[0-9a-f]{32,}\b`),                           // hex keys, hashes and ETH addresses
+		regexp.MustCompile(`\b[1-9A-HJ-NP-Za-km-z]{25,}\b`),                          // base58: WIFs, BTC, Tron, XRPL, SOL addresses
+		regexp.MustCompile(`(?i)\b(?:bc|tb|cosmos|osmo|addr|ur:)[0-9a-z:/-]{20,}\b`), // bech32 addresses and URs
+		regexp.MustCompile(`\b[a-z]{2,20}1[02-9ac-hj-np-z]{38,}\b`),                  // bech32 addresses of any other chain
+		regexp.MustCompile(`[A-Za-z0-9+/]{40,}={0,2}`),                               // base64 ciphertexts and shares
+	}
+
+	bip39Words = func() map[string]struct{} {
+		words := make(map[string]struct{}, len(wordlists.English))
+		for _, word := range wordlists.English {
+			words[word] = struct{}{}
+		}
+		return words
+	}()
+)
+
+// minMnemonicRun is the number of consecutive BIP39 words that is treated as (part of) a mnemonic phrase.
+const minMnemonicRun = 4
+
+// Redact removes anything that could be a secret, an address or a mnemonic phrase from text.
+func Redact(text string) string {
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, redacted)
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = redactMnemonicRuns(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// redactMnemonicRuns replaces runs of BIP39 words, which may be pasted phrases, even when numbered or comma separated.
+func redactMnemonicRuns(line string) string {
+	const (
+		other = iota
+		word
+		numbering
+	)
+	fields := strings.Fields(line)
+	kinds := make([]int, len(fields))
+	for i, field := range fields {
+		trimmed := strings.ToLower(strings.Trim(field, `.,;:"'()0123456789`))
+		if _, ok := bip39Words[trimmed]; ok {
+			kinds[i] = word
+		} else if trimmed == "" {
+			kinds[i] = numbering
+		}
+	}
+	changed := false
+	for start := 0; start < len(fields); start++ {
+		if kinds[start] != word {
+			continue
+		}
+		end, words := start, 0
+		for ; end < len(fields) && kinds[end] != other; end++ {
+			if kinds[end] == word {
+				words++
+			}
+		}
+		if words >= minMnemonicRun {
+			for i := start; i < end; i++ {
+				fields[i] = ""
+			}
+			fields[start] = redacted
+			changed = true
+		}
+		start = end
+	}
+	if !changed {
+		return line
+	}
+	kept := fields[:0]
+	for _, field := range fields {
+		if field != "" {
+			kept = append(kept, field)
+		}
+	}
+	return strings.Join(kept, " ")
+}
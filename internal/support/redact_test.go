@@ -0,0 +1,32 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package support
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupport_Redact(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain log line", "Processing V2 share.\t 1.2 KB → 3.4 KB", "Processing V2 share.\t 1.2 KB → 3.4 KB"},
+		{"eth address", "address 0x620Ac72121234f1b313BD4e8b78C81323502679A ok", "address [REDACTED] ok"},
+		{"hex key", "key: 4cc05b1d3216da8ef91729744159019b25ea1ed5932e387199f1de6ff6667ac2", "key: [REDACTED]"},
+		{"wif", "WIF: L1CujRNEhNfZgTS9b6e3hytTDu7gpUv1kiLx4ETEEhEc8nJcx4QA", "WIF: [REDACTED]"},
+		{"bech32", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", "[REDACTED]"},
+		{"cosmos", "celestia1w508d6qejxtdg4y5r3zarvary0c5xw7kthx244", "[REDACTED]"},
+		{"mnemonic", "got: season pole chronic surround fiber stumble remove", "got: [REDACTED]"},
+		{"numbered mnemonic", "1. season 2. pole 3. chronic 4. surround", "1. [REDACTED]"},
+		{"short word run", "the vault was not found", "the vault was not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Redact(tt.in))
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package sweep builds the unsigned transactions that move the full balance of a recovered key to a new key. The tool
+// is offline, so the chain state they spend (the balance, nonce and fees, or the unspent outputs) is looked up by the
+// operator on a block explorer and passed in. Nothing is signed here: the transactions are signed with the recovered
+// key in a wallet, where the operator sees what is signed.
+package sweep
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/address"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+type (
+	// Params is the chain state of the recovered addresses, as read from block explorers. A chain without params gets no
+	// sweep transaction.
+	Params struct {
+		Ethereum *EthereumParams `json:"ethereum,omitempty"`
+		Bitcoin  *BitcoinParams  `json:"bitcoin,omitempty"`
+	}
+
+	// EthereumParams are the account state of the recovered Ethereum address and the fees to pay. Amounts are decimal
+	// strings in wei, as they overflow JSON numbers.
+	EthereumParams struct {
+		ChainID                 int64  `json:"chainId"`
+		Nonce                   uint64 `json:"nonce"`
+		BalanceWei              string `json:"balanceWei"`
+		MaxFeePerGasWei         string `json:"maxFeePerGasWei"`
+		MaxPriorityFeePerGasWei string `json:"maxPriorityFeePerGasWei"`
+	}
+
+	// BitcoinParams are the unspent outputs of the recovered mainnet P2WPKH address and the fee rate to pay.
+	BitcoinParams struct {
+		FeeRateSatPerVByte int64  `json:"feeRateSatPerVByte"`
+		UTXOs              []UTXO `json:"utxos"`
+	}
+
+	// UTXO is an unspent output, by the txid shown by explorers.
+	UTXO struct {
+		TxID     string `json:"txid"`
+		Vout     uint32 `json:"vout"`
+		ValueSat int64  `json:"valueSat"`
+	}
+
+	// EthereumSweep is an unsigned EIP-1559 transaction of the whole balance less the most it can pay in fees.
+	EthereumSweep struct {
+		// Unsigned is 0x02 followed by the RLP of the transaction fields without the signature, as taken by signers
+		Unsigned []byte
+		// SigningHash is the Keccak-256 of Unsigned, which the key signs
+		SigningHash []byte
+		Value, Fee  *big.Int
+	}
+
+	// BitcoinSweep is an unsigned PSBT (BIP-174) spending all the given outputs to a single output.
+	BitcoinSweep struct {
+		PSBT        []byte
+		Amount, Fee int64
+	}
+)
+
+const (
+	ethTransferGas = 21000
+
+	// the virtual sizes of a P2WPKH transaction: its overhead rounded up, and each input and output
+	p2wpkhOverheadVBytes = 11
+	p2wpkhInputVBytes    = 68
+	p2wpkhOutputVBytes   = 31
+	// p2wpkhDust is the smallest P2WPKH output that Bitcoin Core relays
+	p2wpkhDust = 294
+	// rbfSequence opts the inputs in to replace-by-fee, so that a sweep stuck with a low fee can be bumped
+	rbfSequence = 0xfffffffd
+)
+
+// Ethereum builds the sweep of the native balance of the recovered address to the new one, with a plain transfer.
+// Tokens are not swept: their transfers are contract calls that need the token contracts and balances.
+func Ethereum(p EthereumParams, to common.Address) (*EthereumSweep, error) {
+	balance, okBalance := new(big.Int).SetString(p.BalanceWei, 10)
+	maxFee, okFee := new(big.Int).SetString(p.MaxFeePerGasWei, 10)
+	tip, okTip := new(big.Int).SetString(p.MaxPriorityFeePerGasWei, 10)
+	switch {
+	case p.ChainID < 1:
+		return nil, errors.New("⚠ the Ethereum sweep needs the chainId of the network, e.g. 1 for mainnet")
+	case !okBalance || !okFee || !okTip || balance.Sign() < 0 || maxFee.Sign() <= 0 || tip.Sign() < 0:
+		return nil, errors.New("⚠ the Ethereum sweep needs balanceWei, maxFeePerGasWei and maxPriorityFeePerGasWei as positive whole numbers of wei")
+	case tip.Cmp(maxFee) > 0:
+		return nil, errors.New("⚠ maxPriorityFeePerGasWei cannot be over maxFeePerGasWei")
+	}
+	fee := new(big.Int).Mul(maxFee, big.NewInt(ethTransferGas))
+	value := new(big.Int).Sub(balance, fee)
+	if value.Sign() <= 0 {
+		return nil, fmt.Errorf("⚠ the balance of %s wei does not cover the fee of up to %s wei, so there is nothing to sweep on Ethereum", balance, fee)
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(p.ChainID),
+		Nonce:     p.Nonce,
+		GasTipCap: tip,
+		GasFeeCap: maxFee,
+		Gas:       ethTransferGas,
+		To:        &to,
+		Value:     value,
+	})
+	payload, err := rlp.EncodeToBytes([]any{
+		tx.ChainId(), tx.Nonce(), tx.GasTipCap(), tx.GasFeeCap(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), tx.AccessList(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("⚠ could not encode the Ethereum sweep: %v", err)
+	}
+	unsigned := append([]byte{types.DynamicFeeTxType}, payload...)
+	return &EthereumSweep{
+		Unsigned:    unsigned,
+		SigningHash: crypto.Keccak256(unsigned),
+		Value:       value,
+		Fee:         fee,
+	}, nil
+}
+
+// Bitcoin builds the sweep of the unspent outputs of the recovered P2WPKH key to the P2WPKH address of the new key.
+// The keys are compressed secp256k1 public keys. The fee is paid at the given rate on the virtual size of the signed
+// transaction.
+func Bitcoin(p BitcoinParams, fromPK, toPK []byte) (*BitcoinSweep, error) {
+	if len(p.UTXOs) == 0 {
+		return nil, errors.New("⚠ the Bitcoin sweep needs the unspent outputs of the recovered address")
+	}
+	if p.FeeRateSatPerVByte < 1 {
+		return nil, errors.New("⚠ the Bitcoin sweep needs a feeRateSatPerVByte of at least 1")
+	}
+	fromScript, toScript := p2wpkhScript(fromPK), p2wpkhScript(toPK)
+
+	var total int64
+	tx := new(bytes.Buffer)
+	_ = binary.Write(tx, binary.LittleEndian, uint32(2))
+	writeCompactSize(tx, uint64(len(p.UTXOs)))
+	for i, utxo := range p.UTXOs {
+		txid, err := hex.DecodeString(utxo.TxID)
+		if err != nil || len(txid) != 32 {
+			return nil, fmt.Errorf("⚠ the txid of Bitcoin output %d is not 64 hex characters: %s", i+1, utxo.TxID)
+		}
+		if utxo.ValueSat <= 0 {
+			return nil, fmt.Errorf("⚠ the valueSat of Bitcoin output %d must be positive", i+1)
+		}
+		total += utxo.ValueSat
+		// txids are shown byte-reversed
+		for j := len(txid) - 1; j >= 0; j-- {
+			tx.WriteByte(txid[j])
+		}
+		_ = binary.Write(tx, binary.LittleEndian, utxo.Vout)
+		writeCompactSize(tx, 0)
+		_ = binary.Write(tx, binary.LittleEndian, uint32(rbfSequence))
+	}
+	fee := p.FeeRateSatPerVByte * int64(p2wpkhOverheadVBytes+p2wpkhInputVBytes*len(p.UTXOs)+p2wpkhOutputVBytes)
+	amount := total - fee
+	if amount < p2wpkhDust {
+		return nil, fmt.Errorf("⚠ the %d sat of the Bitcoin outputs do not cover the fee of %d sat, so there is nothing to sweep on Bitcoin", total, fee)
+	}
+	writeCompactSize(tx, 1)
+	_ = binary.Write(tx, binary.LittleEndian, amount)
+	writeCompactSize(tx, uint64(len(toScript)))
+	tx.Write(toScript)
+	_ = binary.Write(tx, binary.LittleEndian, uint32(0))
+
+	psbt := bytes.NewBufferString("psbt\xff")
+	// global map: the unsigned transaction
+	writePSBTPair(psbt, []byte{0x00}, tx.Bytes())
+	psbt.WriteByte(0x00)
+	// input maps: the spent output, which segwit signatures commit to
+	for _, utxo := range p.UTXOs {
+		witnessUTXO := new(bytes.Buffer)
+		_ = binary.Write(witnessUTXO, binary.LittleEndian, utxo.ValueSat)
+		writeCompactSize(witnessUTXO, uint64(len(fromScript)))
+		witnessUTXO.Write(fromScript)
+		writePSBTPair(psbt, []byte{0x01}, witnessUTXO.Bytes())
+		psbt.WriteByte(0x00)
+	}
+	// output map, empty
+	psbt.WriteByte(0x00)
+	return &BitcoinSweep{PSBT: psbt.Bytes(), Amount: amount, Fee: fee}, nil
+}
+
+// p2wpkhScript is the output script of a P2WPKH address: version 0 and the 20 byte key hash.
+func p2wpkhScript(compressedPK []byte) []byte {
+	return append([]byte{0x00, 0x14}, address.Hash160(compressedPK)...)
+}
+
+func writePSBTPair(b *bytes.Buffer, key, value []byte) {
+	writeCompactSize(b, uint64(len(key)))
+	b.Write(key)
+	writeCompactSize(b, uint64(len(value)))
+	b.Write(value)
+}
+
+// writeCompactSize writes a Bitcoin variable length integer.
+func writeCompactSize(b *bytes.Buffer, n uint64) {
+	switch {
+	case n < 0xfd:
+		b.WriteByte(byte(n))
+	case n <= 0xffff:
+		b.WriteByte(0xfd)
+		_ = binary.Write(b, binary.LittleEndian, uint16(n))
+	case n <= 0xffffffff:
+		b.WriteByte(0xfe)
+		_ = binary.Write(b, binary.LittleEndian, uint32(n))
+	default:
+		b.WriteByte(0xff)
+		_ = binary.Write(b, binary.LittleEndian, n)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package sweep
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthereum(t *testing.T) {
+	from, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	fromKey, err := crypto.ToECDSA(from.Serialize())
+	require.NoError(t, err)
+	to := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+
+	params := EthereumParams{ChainID: 1, Nonce: 7, BalanceWei: "1000000000000000000", MaxFeePerGasWei: "30000000000", MaxPriorityFeePerGasWei: "1000000000"}
+	sweep, err := Ethereum(params, to)
+	require.NoError(t, err)
+	assert.Equal(t, "630000000000000", sweep.Fee.String())
+	assert.Equal(t, "999370000000000000", sweep.Value.String())
+	assert.Equal(t, byte(types.DynamicFeeTxType), sweep.Unsigned[0])
+
+	// signing the hash with the recovered key gives a transaction from its address, of the swept value, to the new key
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 7, GasTipCap: big.NewInt(1000000000),
+		GasFeeCap: big.NewInt(30000000000), Gas: 21000, To: &to, Value: sweep.Value})
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	assert.Equal(t, signer.Hash(tx).Bytes(), sweep.SigningHash)
+	sig, err := crypto.Sign(sweep.SigningHash, fromKey)
+	require.NoError(t, err)
+	signed, err := tx.WithSignature(signer, sig)
+	require.NoError(t, err)
+	sender, err := types.Sender(signer, signed)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(fromKey.PublicKey), sender)
+
+	params.BalanceWei = "630000000000000"
+	_, err = Ethereum(params, to)
+	assert.ErrorContains(t, err, "nothing to sweep")
+	params.BalanceWei, params.ChainID = "1000000000000000000", 0
+	_, err = Ethereum(params, to)
+	assert.ErrorContains(t, err, "chainId")
+	params.ChainID, params.MaxFeePerGasWei = 1, "0.5"
+	_, err = Ethereum(params, to)
+	assert.Error(t, err)
+}
+
+func TestBitcoin(t *testing.T) {
+	fromPK := mustHex(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	toPK := mustHex(t, "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5")
+	txid := "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16"
+	params := BitcoinParams{FeeRateSatPerVByte: 10, UTXOs: []UTXO{{TxID: txid, Vout: 1, ValueSat: 100000}, {TxID: txid, Vout: 2, ValueSat: 50000}}}
+	sweep, err := Bitcoin(params, fromPK, toPK)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10*(11+2*68+31)), sweep.Fee)
+	assert.Equal(t, 150000-sweep.Fee, sweep.Amount)
+
+	psbt := sweep.PSBT
+	require.True(t, bytes.HasPrefix(psbt, []byte("psbt\xff\x01\x00")), "magic and the global unsigned transaction key")
+	tx := psbt[len("psbt\xff\x01\x00")+1:]
+	assert.Equal(t, uint32(2), binary.LittleEndian.Uint32(tx))
+	assert.Equal(t, byte(2), tx[4], "two inputs")
+	reversed := make([]byte, 32)
+	for i, b := range mustHex(t, txid) {
+		reversed[31-i] = b
+	}
+	assert.Equal(t, reversed, tx[5:37], "txids are byte-reversed")
+	assert.Equal(t, uint32(1), binary.LittleEndian.Uint32(tx[37:41]))
+	// the output pays the P2WPKH script of the new key, and each input carries its spent output
+	assert.Contains(t, hex.EncodeToString(psbt), "0014"+hex.EncodeToString(p2wpkhScript(toPK)[2:]))
+	assert.Equal(t, 2, strings.Count(hex.EncodeToString(psbt), "16"+hex.EncodeToString(p2wpkhScript(fromPK))))
+	assert.True(t, bytes.HasSuffix(psbt, []byte{0x00, 0x00}), "the last input map and the empty output map")
+
+	params.FeeRateSatPerVByte = 1000
+	_, err = Bitcoin(params, fromPK, toPK)
+	assert.ErrorContains(t, err, "nothing to sweep")
+	params.FeeRateSatPerVByte, params.UTXOs[0].TxID = 10, "abcd"
+	_, err = Bitcoin(params, fromPK, toPK)
+	assert.ErrorContains(t, err, "txid")
+	_, err = Bitcoin(BitcoinParams{FeeRateSatPerVByte: 10}, fromPK, toPK)
+	assert.Error(t, err)
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
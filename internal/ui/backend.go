@@ -0,0 +1,12 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build !goexperiment.boringcrypto
+
+package ui
+
+// CryptoBackend names the implementation of the standard library crypto (AES, SHA-2, TLS) used by this build.
+func CryptoBackend() string {
+	return "Go standard library"
+}
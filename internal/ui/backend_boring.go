@@ -0,0 +1,22 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build goexperiment.boringcrypto
+
+package ui
+
+import (
+	"crypto/boring"
+	// restricts TLS (used by -remote) to FIPS approved settings
+	_ "crypto/tls/fipsonly"
+)
+
+// CryptoBackend names the implementation of the standard library crypto (AES, SHA-2, TLS) used by this build. Only those
+// primitives come from BoringCrypto: the TSS, secp256k1 and Ed25519 math does not use it.
+func CryptoBackend() string {
+	if boring.Enabled() {
+		return "BoringCrypto (standard library primitives only)"
+	}
+	return "Go standard library (BoringCrypto build, but the module is unavailable on this platform)"
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+)
+
+// FileFingerprint identifies an input file, for checking against an asset inventory that the right, untampered files were used.
+type FileFingerprint struct {
+	File    string
+	SHA256  string
+	Size    int64
+	ModTime time.Time
+}
+
+// FingerprintFile hashes the file, or content if the backup was not read from the file directly, e.g. decoded from QR code
+// images or downloaded with -remote. Downloaded backups have no local file, and no modification time.
+func FingerprintFile(file string, content []byte) (FileFingerprint, error) {
+	fp := FileFingerprint{File: file}
+	info, err := os.Stat(file)
+	switch {
+	case err == nil:
+		fp.ModTime = info.ModTime()
+	case content == nil:
+		return FileFingerprint{}, errors2.Errorf("⚠ unable to see file `%s` - does it exist?: %s", file, err)
+	}
+	if content == nil {
+		if content, err = os.ReadFile(file); err != nil {
+			return FileFingerprint{}, errors2.Errorf("⚠ unable to read file `%s`: %s", file, err)
+		}
+	}
+	hash := sha256.Sum256(content)
+	fp.SHA256, fp.Size = hex.EncodeToString(hash[:]), int64(len(content))
+	return fp, nil
+}
+
+func (f FileFingerprint) String() string {
+	s := fmt.Sprintf("%s  sha256:%s  %d bytes", f.File, f.SHA256, f.Size)
+	if !f.ModTime.IsZero() {
+		s += fmt.Sprintf("  modified %s", f.ModTime.UTC().Format(time.RFC3339))
+	}
+	return s
+}
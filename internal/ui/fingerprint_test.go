@@ -0,0 +1,39 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint_File(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.json")
+	require.NoError(t, os.WriteFile(file, []byte("abc"), 0600))
+
+	fp, err := FingerprintFile(file, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", fp.SHA256)
+	assert.EqualValues(t, 3, fp.Size)
+	assert.Contains(t, fp.String(), "sha256:ba7816bf")
+
+	// decoded content is hashed instead of the file
+	fp, err = FingerprintFile(file, []byte(""))
+	require.NoError(t, err)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", fp.SHA256)
+
+	_, err = FingerprintFile(filepath.Join(t.TempDir(), "missing.json"), nil)
+	assert.Error(t, err)
+
+	// downloaded content has no local file
+	fp, err = FingerprintFile("https://bucket.s3.amazonaws.com/a.json", []byte("abc"))
+	require.NoError(t, err)
+	assert.True(t, fp.ModTime.IsZero())
+	assert.NotContains(t, fp.String(), "modified")
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var (
+	// global output controls, see ConfigureOutput
+	quietOutput bool
+	plainOutput bool
+
+	// plainReplacer swaps decorative symbols for ASCII when color is disabled, so log scrapers get clean text
+	plainReplacer = strings.NewReplacer("⚠ ", "WARNING: ", "⚠", "WARNING:", "✓", "*", "→", "->", "…", "...", "• ", "- ", "—", "-")
+)
+
+// ConfigureOutput sets the global output controls once at startup.
+// Quiet suppresses the banner, warnings and progress messages, leaving only results and errors. The files written
+// are results, so they are printed with fmt rather than Printf.
+// Color (and decorative symbols) can also be disabled with the NO_COLOR and CLICOLOR=0 environment variables.
+func ConfigureOutput(quiet, noColor bool) {
+	quietOutput = quiet
+	if noColor || NoColorFromEnv() {
+		plainOutput = true
+		for code := range AnsiCodes {
+			AnsiCodes[code] = ""
+		}
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// NoColorFromEnv reports whether the environment asks for output without color (https://no-color.org, CLICOLOR=0).
+func NoColorFromEnv() bool {
+	if noColor, ok := os.LookupEnv("NO_COLOR"); ok && noColor != "" {
+		return true
+	}
+	return os.Getenv("CLICOLOR") == "0"
+}
+
+// IsQuiet reports whether informational output is suppressed.
+func IsQuiet() bool {
+	return quietOutput
+}
+
+// Plain returns s without decorative symbols when color is disabled, otherwise s as is.
+func Plain(s string) string {
+	if !plainOutput {
+		return s
+	}
+	return plainReplacer.Replace(s)
+}
+
+// Printf prints an informational or warning message, unless the output is quiet.
+func Printf(format string, a ...any) {
+	Fprintf(os.Stdout, format, a...)
+}
+
+// Fprintf writes an informational or warning message to w, unless the output is quiet.
+func Fprintf(w io.Writer, format string, a ...any) {
+	if quietOutput {
+		return
+	}
+	_, _ = fmt.Fprint(w, Plain(fmt.Sprintf(format, a...)))
+}
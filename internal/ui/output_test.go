@@ -0,0 +1,39 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutput_NoColorFromEnv(t *testing.T) {
+	tests := []struct {
+		name, noColor, cliColor string
+		want                    bool
+	}{
+		{"unset", "", "", false},
+		{"NO_COLOR set", "1", "", true},
+		{"CLICOLOR=0", "", "0", true},
+		{"CLICOLOR=1", "", "1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("CLICOLOR", tt.cliColor)
+			assert.Equal(t, tt.want, NoColorFromEnv())
+		})
+	}
+}
+
+func TestOutput_Plain(t *testing.T) {
+	assert.Equal(t, "⚠ no shares", Plain("⚠ no shares"))
+
+	plainOutput = true
+	defer func() { plainOutput = false }()
+	assert.Equal(t, "WARNING: no shares", Plain("⚠ no shares"))
+	assert.Equal(t, "1.0 KB -> 2.0 KB", Plain("1.0 KB → 2.0 KB"))
+}
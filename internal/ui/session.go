@@ -0,0 +1,31 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+)
+
+// SessionID identifies this run of the tool. It is shown in the banner and errors and added to the names of exported
+// files, so that operators and support tickets can refer to a specific run. It is random and tells nothing about the vault.
+var SessionID = newSessionID()
+
+func newSessionID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// SessionFilename tags the name of an exported file with the session ID, before its extension: wallet.json becomes
+// wallet-<session id>.json.
+func SessionFilename(name string) string {
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + SessionID + ext
+}
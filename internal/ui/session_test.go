@@ -0,0 +1,25 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionID(t *testing.T) {
+	assert.Regexp(t, `^[0-9a-f]{8}$`, SessionID)
+	assert.NotEqual(t, SessionID, newSessionID())
+
+	assert.Equal(t, "wallet-"+SessionID+".json", SessionFilename("wallet.json"))
+	assert.Equal(t, "out/v1-party-2-"+SessionID+".json", SessionFilename("out/v1-party-2.json"))
+	assert.Equal(t, "keys-"+SessionID, SessionFilename("keys"))
+
+	assert.Contains(t, Banner(), SessionID)
+	assert.Contains(t, Banner(), "Crypto: "+CryptoBackend())
+	assert.Contains(t, ErrorBox(errors.New("boom")), SessionID)
+}
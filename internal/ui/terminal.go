@@ -0,0 +1,57 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"io"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+	errors2 "github.com/pkg/errors"
+)
+
+// Capabilities describe the terminal the tool runs in, e.g. a container, an SSH session without a TTY or a Windows console.
+type Capabilities struct {
+	// InputTTY is set when the phrases can be typed into interactive forms
+	InputTTY bool
+	// OutputTTY is set when the output goes to a terminal rather than a file or a pipe
+	OutputTTY bool
+	// Color is the color depth of the output; termenv.Ascii when colors can't be shown
+	Color termenv.Profile
+}
+
+// DetectCapabilities inspects the input and output of the tool.
+// It only reads the environment and file descriptors, and never fails: unknown terminals are treated as plain text.
+func DetectCapabilities(in io.Reader, out io.Writer) Capabilities {
+	caps := Capabilities{
+		InputTTY:  isTerminal(in),
+		OutputTTY: isTerminal(out),
+		Color:     termenv.Ascii,
+	}
+	if caps.OutputTTY && os.Getenv("TERM") != "dumb" {
+		caps.Color = termenv.NewOutput(out).EnvColorProfile()
+	}
+	return caps
+}
+
+// RequireInteractive returns an explicit error when the phrases can't be entered, rather than leaving the forms to fail.
+func (c Capabilities) RequireInteractive() error {
+	if !c.InputTTY {
+		return errors2.New("⚠ this tool needs an interactive terminal to enter the phrases, but its input is not a terminal. " +
+			"Over SSH, connect with `ssh -t`; in a container, run it with `docker run -it`")
+	}
+	return nil
+}
+
+// PlainText reports whether the output can only show plain text, e.g. when redirected to a file.
+func (c Capabilities) PlainText() bool {
+	return c.Color == termenv.Ascii
+}
+
+func isTerminal(f any) bool {
+	file, ok := f.(*os.File)
+	return ok && file != nil && term.IsTerminal(file.Fd())
+}
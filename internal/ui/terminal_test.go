@@ -0,0 +1,38 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminal_NotATerminal(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	// files, pipes and buffers are plain text and can't host the forms
+	for _, caps := range []Capabilities{
+		DetectCapabilities(file, file),
+		DetectCapabilities(new(bytes.Buffer), new(bytes.Buffer)),
+		DetectCapabilities(nil, nil),
+	} {
+		assert.False(t, caps.InputTTY)
+		assert.False(t, caps.OutputTTY)
+		assert.Equal(t, termenv.Ascii, caps.Color)
+		assert.ErrorContains(t, caps.RequireInteractive(), "not a terminal")
+	}
+}
+
+func TestTerminal_Interactive(t *testing.T) {
+	assert.NoError(t, Capabilities{InputTTY: true}.RequireInteractive())
+}
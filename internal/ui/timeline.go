@@ -0,0 +1,56 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimelineEntry is what a backup file tells about the history of a vault: when it was backed up, and the
+// reshare nonces it holds. The vault data has no creation or reshare dates, so the backup time is the only date known.
+type TimelineEntry struct {
+	File       string
+	BackedUpAt time.Time
+	Nonces     []int
+}
+
+// NewTimelineEntry parses the backup file's RFC 3339 timestamp. A missing or invalid timestamp leaves BackedUpAt zero.
+func NewTimelineEntry(file, timestamp string, nonces []int) TimelineEntry {
+	backedUpAt, _ := time.Parse(time.RFC3339Nano, timestamp)
+	return TimelineEntry{File: file, BackedUpAt: backedUpAt, Nonces: nonces}
+}
+
+// FormatTimeline renders the entries oldest backup first, one line per file.
+func FormatTimeline(entries []TimelineEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	sorted := make([]TimelineEntry, len(entries))
+	copy(sorted, entries)
+	// insertion sort keeps files with the same backup time in input order
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j].BackedUpAt.Before(sorted[j-1].BackedUpAt); j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("Backup timeline:\n")
+	for _, entry := range sorted {
+		backedUpAt := "unknown date"
+		if !entry.BackedUpAt.IsZero() {
+			backedUpAt = entry.BackedUpAt.UTC().Format("2006-01-02 15:04:05 UTC")
+		}
+		nonces := make([]string, len(entry.Nonces))
+		for i, nonce := range entry.Nonces {
+			nonces[i] = strconv.Itoa(nonce)
+		}
+		fmt.Fprintf(&b, "  %s  %s  reshare nonces %s\n", backedUpAt, entry.File, strings.Join(nonces, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,24 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeline_Format(t *testing.T) {
+	entries := []TimelineEntry{
+		NewTimelineEntry("b.json", "2024-09-30T11:08:02.256Z", []int{2, 3, 4}),
+		NewTimelineEntry("a.json", "2024-09-30T11:07:59.954Z", []int{1, 3, 4}),
+		NewTimelineEntry("c.json", "not a date", []int{4}),
+	}
+	assert.Equal(t, "Backup timeline:\n"+
+		"  unknown date  c.json  reshare nonces 4\n"+
+		"  2024-09-30 11:07:59 UTC  a.json  reshare nonces 1, 3, 4\n"+
+		"  2024-09-30 11:08:02 UTC  b.json  reshare nonces 2, 3, 4\n", FormatTimeline(entries))
+	assert.Empty(t, FormatTimeline(nil))
+}
@@ -6,6 +6,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 )
 
 const (
@@ -15,11 +16,12 @@ const (
 var (
 	// ANSI escape seqs for colours in the terminal
 	AnsiCodes = map[string]string{
-		"bold":        "\033[1m",
-		"invertOn":    "\033[7m",
-		"darkRedBG":   "\033[41m",
-		"darkGreenBG": "\033[42m",
-		"reset":       "\033[0m",
+		"bold":         "\033[1m",
+		"invertOn":     "\033[7m",
+		"darkRedBG":    "\033[41m",
+		"darkGreenBG":  "\033[42m",
+		"darkYellowBG": "\033[43m",
+		"reset":        "\033[0m",
 	}
 )
 
@@ -27,17 +29,41 @@ func Banner() string {
 	b := "\n"
 	b += fmt.Sprintf("%s%s                                     %s\n", AnsiCodes["invertOn"], AnsiCodes["bold"], AnsiCodes["reset"])
 	b += fmt.Sprintf("%s%s     io.finnet Key Recovery Tool     %s\n", AnsiCodes["invertOn"], AnsiCodes["bold"], AnsiCodes["reset"])
-	b += fmt.Sprintf("%s%s               v5.2.0                %s\n", AnsiCodes["invertOn"], AnsiCodes["bold"], AnsiCodes["reset"])
+	b += fmt.Sprintf("%s%s%s%s\n", AnsiCodes["invertOn"], AnsiCodes["bold"], centered("v"+Version, 37), AnsiCodes["reset"])
 	b += fmt.Sprintf("%s%s                                     %s\n", AnsiCodes["invertOn"], AnsiCodes["bold"], AnsiCodes["reset"])
+	b += fmt.Sprintf("\nSession: %s (quote it in support tickets)\n", SessionID)
+	b += fmt.Sprintf("Crypto: %s\n", CryptoBackend())
 	b += "\n"
 	return b
 }
 
+// centered pads s with spaces to width, e.g. for the version line of the banner, whose length varies between builds.
+func centered(s string, width int) string {
+	if len(s) >= width {
+		return " " + s + " "
+	}
+	left := (width - len(s)) / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", width-len(s)-left)
+}
+
 func ErrorBox(err error) string {
 	b := "\n"
 	b += fmt.Sprintf("%s%s         %s\n", AnsiCodes["darkRedBG"], AnsiCodes["bold"], AnsiCodes["reset"])
-	b += fmt.Sprintf("%s%s  Error  %s  %s.\n", AnsiCodes["darkRedBG"], AnsiCodes["bold"], AnsiCodes["reset"], err)
+	b += fmt.Sprintf("%s%s  Error  %s  %s. (session %s)\n", AnsiCodes["darkRedBG"], AnsiCodes["bold"], AnsiCodes["reset"], Plain(err.Error()), SessionID)
 	b += fmt.Sprintf("%s%s         %s\n", AnsiCodes["darkRedBG"], AnsiCodes["bold"], AnsiCodes["reset"])
 	b += "\n"
 	return b
 }
+
+// WarningBox highlights a warning that must be read before going on. Unlike other warnings, it is shown with -quiet too.
+func WarningBox(title string, lines []string) string {
+	b := "\n"
+	b += fmt.Sprintf("%s%s           %s\n", AnsiCodes["darkYellowBG"], AnsiCodes["bold"], AnsiCodes["reset"])
+	b += fmt.Sprintf("%s%s  Warning  %s  %s\n", AnsiCodes["darkYellowBG"], AnsiCodes["bold"], AnsiCodes["reset"], Plain(title))
+	for _, line := range lines {
+		b += strings.TrimRight(fmt.Sprintf("%s%s           %s  %s", AnsiCodes["darkYellowBG"], AnsiCodes["bold"], AnsiCodes["reset"], Plain(line)), " ") + "\n"
+	}
+	b += fmt.Sprintf("%s%s           %s\n", AnsiCodes["darkYellowBG"], AnsiCodes["bold"], AnsiCodes["reset"])
+	b += "\n"
+	return b
+}
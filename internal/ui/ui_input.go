@@ -6,11 +6,14 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/lipgloss/list"
+	"github.com/charmbracelet/lipgloss/table"
 	errors2 "github.com/pkg/errors"
 )
 
@@ -18,93 +21,517 @@ type (
 	VaultsDataFile struct {
 		File      string
 		Mnemonics string
+		// Content is the backup file JSON when it was not read from File directly, e.g. decoded from QR code images.
+		Content []byte
 	}
 
+	// MnemonicsChecker tries a phrase against its backup file and returns the number of vaults found in it.
+	MnemonicsChecker func(file VaultsDataFile) (vaults int, err error)
+	// MnemonicsMatcher quickly tells whether a phrase belongs to a backup file, e.g. by a mnemonic check ciphertext.
+	// It is false for files that cannot be checked quickly.
+	MnemonicsMatcher func(file VaultsDataFile) bool
+
 	/**
 	 * mnemmonicsFormModel is a struct that represents the model for the mnemonics entry.
 	 */
 	mnemonicsFormModel struct {
 		filenames []string
+		check     MnemonicsChecker
+		match     MnemonicsMatcher
+	}
+
+	mnemonicStatus int
+
+	// mnemonicEntry is a row of the summary table shown while phrases are entered
+	mnemonicEntry struct {
+		VaultsDataFile
+		status mnemonicStatus
+		vaults int
+		err    error
+		// notice is shown on the next prompt for this file
+		notice string
 	}
 )
 
+const (
+	statusPending mnemonicStatus = iota
+	statusValidated
+	statusFailed
+)
+
+func (s mnemonicStatus) String() string {
+	switch s {
+	case statusValidated:
+		return "validated"
+	case statusFailed:
+		return "failed"
+	default:
+		return "pending"
+	}
+}
+
 func NewMnemonicsForm(config config.AppConfig) mnemonicsFormModel {
 	return mnemonicsFormModel{
 		filenames: config.Filenames,
 	}
 }
 
-func (m mnemonicsFormModel) Run() (*[]VaultsDataFile, error) {
-	filesWithMnemonics := []VaultsDataFile{}
+// WithChecker sets a check that is run on each phrase as soon as it is entered, so that a wrong phrase is caught
+// before the phrases for the other files are entered.
+func (m mnemonicsFormModel) WithChecker(check MnemonicsChecker) mnemonicsFormModel {
+	m.check = check
+	return m
+}
 
-	for _, filename := range m.filenames {
-		input := huh.NewText().
-			Key("phrase").
-			Title(fmt.Sprintf("Mnemonics for %s", filename)).
-			Description(fmt.Sprintf("Enter the %d word phrase", WORDS)).
-			Validate(func(input string) error {
-				fileWithMnemonic := VaultsDataFile{File: filename, Mnemonics: input}
-				return fileWithMnemonic.ValidateMnemonics()
-			})
+// WithMatcher sets a quick check that pairs a phrase entered for the wrong file with the file it belongs to, so that
+// phrases can be entered in any order.
+func (m mnemonicsFormModel) WithMatcher(match MnemonicsMatcher) mnemonicsFormModel {
+	m.match = match
+	return m
+}
 
-		var form *huh.Form
+func (m mnemonicsFormModel) Run() (*[]VaultsDataFile, error) {
+	entries := make([]mnemonicEntry, len(m.filenames))
+	for i, filename := range m.filenames {
+		entries[i] = mnemonicEntry{VaultsDataFile: VaultsDataFile{File: filename}, vaults: -1}
+	}
 
-		// Show the list of files added if there are more than one
-		if len(filesWithMnemonics) > 0 {
-			form = huh.NewForm(
-				huh.NewGroup(
-					huh.NewNote().Description(m.fileList(filesWithMnemonics)),
-					input,
-				),
-			).WithTheme(huh.ThemeBase16())
-		} else {
-			form = huh.NewForm(huh.NewGroup(input)).WithTheme(huh.ThemeBase16())
+	for i := range entries {
+		// already paired with a phrase entered for another file
+		if entries[i].status == statusValidated {
+			continue
+		}
+		if err := m.enterPhrase(entries, i); err != nil {
+			return nil, err
 		}
+	}
+	// a single file has nothing to review side by side: a wrong phrase can still be re-entered before recovery
+	if len(entries) == 1 && entries[0].status == statusValidated {
+		return m.done(entries)
+	}
+	return m.reviewUntilDone(entries)
+}
+
+// Review returns to the review step with the phrases entered in a previous Run, so that some of them can be re-entered.
+func (m mnemonicsFormModel) Review(previous []VaultsDataFile) (*[]VaultsDataFile, error) {
+	entries := make([]mnemonicEntry, len(previous))
+	for i, file := range previous {
+		entries[i] = mnemonicEntry{VaultsDataFile: file, status: statusValidated, vaults: -1}
+		if m.check != nil {
+			if entries[i].vaults, entries[i].err = m.check(file); entries[i].err != nil {
+				entries[i].status, entries[i].vaults = statusFailed, -1
+			}
+		}
+	}
+	return m.reviewUntilDone(entries)
+}
 
-		err := form.Run()
+func (m mnemonicsFormModel) reviewUntilDone(entries []mnemonicEntry) (*[]VaultsDataFile, error) {
+	// Any phrase can be re-entered until the user continues
+	for {
+		index, err := m.review(entries)
 		if err != nil {
 			return nil, err
 		}
+		if index < 0 {
+			break
+		}
+		if err = m.enterPhrase(entries, index); err != nil {
+			return nil, err
+		}
+	}
+	return m.done(entries)
+}
+
+func (m mnemonicsFormModel) done(entries []mnemonicEntry) (*[]VaultsDataFile, error) {
+	Printf("%s\n", summaryTable(entries))
+	if len(entries) == 1 {
+		Printf("Mnemonics entered\n\n")
+	} else {
+		Printf("All mnemonics entered\n\n")
+	}
+
+	filesWithMnemonics := make([]VaultsDataFile, len(entries))
+	for i, entry := range entries {
+		filesWithMnemonics[i] = entry.VaultsDataFile
+	}
+	return &filesWithMnemonics, nil
+}
+
+func (m mnemonicsFormModel) enterPhrase(entries []mnemonicEntry, index int) error {
+	entry := &entries[index]
+	// a phrase being re-entered is pre-filled, so that a single wrong word can be fixed
+	phrase := entry.Mnemonics
+	description := fmt.Sprintf("Enter the %d word phrase", WORDS)
+	if entry.notice != "" {
+		description = entry.notice + "\n" + description
+		entry.notice = ""
+	}
+	title := fmt.Sprintf("Mnemonics for %s (file %d of %d)", entry.File, index+1, len(entries))
+	if len(entries) == 1 {
+		title = fmt.Sprintf("Mnemonics for %s", entry.File)
+	}
+	input := huh.NewText().
+		Title(title).
+		Description(description).
+		Value(&phrase).
+		Validate(func(input string) error {
+			fileWithMnemonic := VaultsDataFile{File: entry.File, Mnemonics: input}
+			return fileWithMnemonic.ValidateMnemonics()
+		})
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().Description(summaryTable(entries)),
+			input,
+		),
+	).WithTheme(huh.ThemeBase16())
+	if err := form.Run(); err != nil {
+		return err
+	}
+	if phrase = CleanMnemonicInput(phrase); phrase == "" {
+		return fmt.Errorf("phrase for %s is empty", entry.File)
+	}
+
+	entry.Mnemonics = phrase
+	entry.status, entry.vaults, entry.err = statusValidated, -1, nil
+	if m.check != nil {
+		if entry.vaults, entry.err = m.check(entry.VaultsDataFile); entry.err != nil {
+			entry.status, entry.vaults = statusFailed, -1
+		}
+	}
+	if entry.status == statusFailed {
+		if paired := m.pair(entries, index, phrase); paired >= 0 {
+			// the phrase was entered for the wrong file: keep it for the file it belongs to, and ask again for this one
+			entry.Mnemonics, entry.status, entry.err = "", statusPending, nil
+			entry.notice = fmt.Sprintf("The phrase entered belongs to %s and was paired with it.", entries[paired].File)
+			return m.enterPhrase(entries, index)
+		}
+	}
+	return nil
+}
 
-		mnemonics := form.GetString("phrase")
-		if mnemonics == "" {
-			return nil, fmt.Errorf("phrase for %s is empty", filename)
+// pair finds another file that the phrase belongs to, among those without a validated phrase, and sets the phrase on it.
+// It returns the index of that file, or -1.
+func (m mnemonicsFormModel) pair(entries []mnemonicEntry, index int, phrase string) int {
+	if m.match == nil {
+		return -1
+	}
+	for j := range entries {
+		if j == index || entries[j].status == statusValidated {
+			continue
+		}
+		candidate := entries[j].VaultsDataFile
+		candidate.Mnemonics = phrase
+		if !m.match(candidate) {
+			continue
+		}
+		entries[j].VaultsDataFile = candidate
+		entries[j].status, entries[j].vaults, entries[j].err = statusValidated, -1, nil
+		if m.check != nil {
+			if entries[j].vaults, entries[j].err = m.check(candidate); entries[j].err != nil {
+				entries[j].status, entries[j].vaults = statusFailed, -1
+			}
 		}
+		return j
+	}
+	return -1
+}
 
-		f := VaultsDataFile{File: filename, Mnemonics: mnemonics}
-		filesWithMnemonics = append(filesWithMnemonics, f)
+// review shows the summary table and returns the index of a phrase to re-enter, or -1 to continue.
+func (m mnemonicsFormModel) review(entries []mnemonicEntry) (int, error) {
+	choice := -1
+	options := []huh.Option[int]{huh.NewOption("Continue with these phrases", -1)}
+	for i, entry := range entries {
+		options = append(options, huh.NewOption(fmt.Sprintf("Re-enter the phrase for %s", entry.File), i))
+	}
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().Description(summaryTable(entries)),
+			huh.NewSelect[int]().
+				Title("Review the phrases before continuing").
+				Options(options...).
+				Value(&choice).
+				Validate(func(choice int) error {
+					if choice >= 0 {
+						return nil
+					}
+					for _, entry := range entries {
+						switch entry.status {
+						case statusFailed:
+							return fmt.Errorf("⚠ the phrase for %s failed, re-enter it first", entry.File)
+						case statusPending:
+							return fmt.Errorf("⚠ enter the phrase for %s first", entry.File)
+						}
+					}
+					return nil
+				}),
+		),
+	).WithTheme(huh.ThemeBase16())
+	if err := form.Run(); err != nil {
+		return 0, err
 	}
+	return choice, nil
+}
 
-	fmt.Println(m.fileList(filesWithMnemonics))
-	fmt.Print("All mnemonics entered\n\n")
+// summaryTable renders the status of the phrase of each file, with the reasons of any failures below it.
+func summaryTable(entries []mnemonicEntry) string {
+	validated := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"})
+	failed := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#D7263D", Dark: "#FF5F6D"})
 
-	return &filesWithMnemonics, nil
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("FILE", "STATUS", "VAULTS").
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if row == 0 || col != 1 {
+				return style
+			}
+			switch entries[row-1].status {
+			case statusValidated:
+				return style.Inherit(validated)
+			case statusFailed:
+				return style.Inherit(failed)
+			}
+			return style
+		})
+	var failures strings.Builder
+	for i, entry := range entries {
+		vaults := "-"
+		if entry.vaults >= 0 {
+			vaults = strconv.Itoa(entry.vaults)
+		}
+		t.Row(fmt.Sprintf("%d. %s", i+1, entry.File), entry.status.String(), vaults)
+		if entry.err != nil {
+			failures.WriteString(fmt.Sprintf("\n%s: %s", entry.File, Plain(entry.err.Error())))
+		}
+	}
+	return t.String() + failures.String()
 }
 
-func (m mnemonicsFormModel) fileList(filesWithMnemonics []VaultsDataFile) string {
-	if len(filesWithMnemonics) == 0 {
-		return ""
+// RunSessionPassphraseForm asks for the passphrase that unlocks the screen lock later in the session.
+func RunSessionPassphraseForm() ([]byte, error) {
+	var passphrase, confirmation string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Session passphrase").
+				Description("The screen locks when left idle with keys on it. Choose a passphrase to unlock it.").
+				EchoMode(huh.EchoModePassword).
+				Value(&passphrase).
+				Validate(func(input string) error {
+					if len(input) < 4 {
+						return errors2.New("⚠ the passphrase must be at least 4 characters")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Confirm the session passphrase").
+				EchoMode(huh.EchoModePassword).
+				Value(&confirmation).
+				Validate(func(input string) error {
+					if input != passphrase {
+						return errors2.New("⚠ the passphrases do not match")
+					}
+					return nil
+				}),
+		),
+	).WithTheme(huh.ThemeBase16())
+	if err := form.Run(); err != nil {
+		return nil, errors2.Wrapf(err, "unable to run form")
 	}
+	return []byte(passphrase), nil
+}
 
-	special := lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"}
-	checklistEnumStyle := func(items list.Items, index int) lipgloss.Style {
-		return lipgloss.NewStyle().
-			Foreground(special).
-			PaddingRight(1)
+// RunOperatorForm asks one of the two -four-eyes operators, numbered from 1, for their name and a passphrase.
+// The other operator must look away while it is typed.
+func RunOperatorForm(number int) (name string, passphrase []byte, err error) {
+	var secret, confirmation string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("Operator %d: your name", number)).
+				Description("Two operators must both confirm before any private key is shown or written.").
+				Value(&name).
+				Validate(func(input string) error {
+					if strings.TrimSpace(input) == "" {
+						return errors2.New("⚠ enter your name")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title(fmt.Sprintf("Operator %d: your confirmation passphrase", number)).
+				Description("Only you should know it. The other operator must look away.").
+				EchoMode(huh.EchoModePassword).
+				Value(&secret).
+				Validate(func(input string) error {
+					if len(input) < 8 {
+						return errors2.New("⚠ the passphrase must be at least 8 characters")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Confirm your passphrase").
+				EchoMode(huh.EchoModePassword).
+				Value(&confirmation).
+				Validate(func(input string) error {
+					if input != secret {
+						return errors2.New("⚠ the passphrases do not match")
+					}
+					return nil
+				}),
+		),
+	).WithTheme(huh.ThemeBase16())
+	if err = form.Run(); err != nil {
+		return "", nil, errors2.Wrapf(err, "unable to run form")
 	}
-	checklistEnum := func(items list.Items, index int) string {
-		return "✓"
+	return strings.TrimSpace(name), []byte(secret), nil
+}
+
+// RunOperatorConfirmForm asks an operator to enter their passphrase again, to confirm the recovery.
+func RunOperatorConfirmForm(name string) ([]byte, error) {
+	var secret string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("%s: enter your passphrase to confirm the recovery", name)).
+				Description("The private keys are only recovered once both operators have confirmed.").
+				EchoMode(huh.EchoModePassword).
+				Value(&secret),
+		),
+	).WithTheme(huh.ThemeBase16())
+	if err := form.Run(); err != nil {
+		return nil, errors2.Wrapf(err, "unable to run form")
 	}
+	return []byte(secret), nil
+}
 
-	l := list.New().
-		Enumerator(checklistEnum).
-		EnumeratorStyleFunc(checklistEnumStyle)
+// RunShowSecretsForm asks the operator to type the phrase before the private keys are printed and, if a word check is
+// given, the first word of the phrase of the named backup file. It returns false if the operator chose not to show them.
+func RunShowSecretsForm(phrase string, isPhrase func(string) bool, wordFile string, checkWord func(string) bool) (bool, error) {
+	var typed, word string
+	fields := []huh.Field{
+		huh.NewInput().
+			Title(fmt.Sprintf("Type \"%s\" to show the private keys", phrase)).
+			Description("Make sure nobody else can see the screen. Press Esc or Ctrl+C to not show them.").
+			Value(&typed).
+			Validate(func(input string) error {
+				if !isPhrase(input) {
+					return fmt.Errorf("⚠ type \"%s\" to continue", phrase)
+				}
+				return nil
+			}),
+	}
+	if checkWord != nil {
+		fields = append(fields, huh.NewInput().
+			Title(fmt.Sprintf("Enter the first word of the phrase of %s", wordFile)).
+			EchoMode(huh.EchoModePassword).
+			Value(&word).
+			Validate(func(input string) error {
+				if !checkWord(input) {
+					return errors2.New("⚠ that is not the first word of this phrase")
+				}
+				return nil
+			}))
+	}
+	form := huh.NewForm(huh.NewGroup(fields...)).WithTheme(huh.ThemeBase16())
+	if err := form.Run(); err != nil {
+		if errors2.Is(err, huh.ErrUserAborted) {
+			return false, nil
+		}
+		return false, errors2.Wrapf(err, "unable to run form")
+	}
+	return true, nil
+}
+
+// ConfirmChoice is the step chosen on the final confirmation screen before recovery.
+type ConfirmChoice int
+
+const (
+	ConfirmCancel ConfirmChoice = iota
+	ConfirmRecover
+	ConfirmPickVault
+	ConfirmReenterPhrases
+)
 
-	for i, f := range filesWithMnemonics {
-		l = l.Item(fmt.Sprintf("%s (file %d of %d)", f.File, i+1, len(m.filenames)))
+// RunConfirmRecoveryForm shows exactly what the recovery will show and write to disk, and lets the user go back a step.
+func RunConfirmRecoveryForm(vault VaultPickerItem, outputs []string) (ConfirmChoice, error) {
+	choice := ConfirmRecover
+	summary := fmt.Sprintf("Vault: %s (%s), %s\n", vault.Name, vault.VaultID, vault.SharesSummary())
+	if vault.Note != "" {
+		summary += fmt.Sprintf("Note: %s\n", vault.Note)
+	}
+	summary += fmt.Sprintf("Recovering at reshare nonce %d\n%s\n", vault.LastReShareNonce, FormatTimeline(vault.Timeline))
+	for _, output := range outputs {
+		summary += Plain("• ") + output + "\n"
+	}
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().Title("Ready to recover").Description(summary),
+			huh.NewSelect[ConfirmChoice]().
+				Options(
+					huh.NewOption("Recover this vault", ConfirmRecover),
+					huh.NewOption("Back: pick another vault", ConfirmPickVault),
+					huh.NewOption("Back: re-enter phrases", ConfirmReenterPhrases),
+					huh.NewOption("Cancel", ConfirmCancel),
+				).
+				Value(&choice),
+		),
+	).WithTheme(huh.ThemeBase16())
+	if err := form.Run(); err != nil {
+		return ConfirmCancel, errors2.Wrapf(err, "unable to run form")
 	}
+	return choice, nil
+}
 
-	return l.String()
+// RunConfirmBatchForm shows the vaults of a batch and what will be written, before they are all recovered. Picking
+// another vault is not offered: the batch is chosen on the command line.
+func RunConfirmBatchForm(vaults []VaultPickerItem, outputs []string) (ConfirmChoice, error) {
+	choice := ConfirmRecover
+	summary := fmt.Sprintf("%d vaults:\n", len(vaults))
+	for _, vault := range vaults {
+		summary += Plain("• ") + fmt.Sprintf("%s (%s), %s, at reshare nonce %d\n",
+			vault.Name, vault.VaultID, vault.SharesSummary(), vault.LastReShareNonce)
+	}
+	summary += "\n"
+	for _, output := range outputs {
+		summary += Plain("• ") + output + "\n"
+	}
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().Title("Ready to recover").Description(summary),
+			huh.NewSelect[ConfirmChoice]().
+				Options(
+					huh.NewOption("Recover these vaults", ConfirmRecover),
+					huh.NewOption("Back: re-enter phrases", ConfirmReenterPhrases),
+					huh.NewOption("Cancel", ConfirmCancel),
+				).
+				Value(&choice),
+		),
+	).WithTheme(huh.ThemeBase16())
+	if err := form.Run(); err != nil {
+		return ConfirmCancel, errors2.Wrapf(err, "unable to run form")
+	}
+	return choice, nil
+}
+
+// RunPolicyAcknowledgementForm shows the organization's key handling policy and returns whether it was accepted.
+func RunPolicyAcknowledgementForm(policy string) (bool, error) {
+	var accepted bool
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().Title("Key handling policy").Description(policy),
+			huh.NewConfirm().
+				Title("I have read and accept this policy").
+				Affirmative("Accept").
+				Negative("Decline").
+				Value(&accepted),
+		),
+	).WithTheme(huh.ThemeBase16())
+	if err := form.Run(); err != nil {
+		return false, errors2.Wrapf(err, "unable to run form")
+	}
+	return accepted, nil
 }
 
 /**
@@ -116,23 +543,73 @@ type VaultPickerItem struct {
 	Quorum           int
 	LastReShareNonce int
 	NumberOfShares   int
+	// HeldShares are the ECDSA shares found in the input files
+	HeldShares []HeldShare
+	// PartyShareIDs are the share IDs of all the parties of the vault, as recorded in the share data
+	PartyShareIDs []string
+	// Note is the operator's annotation of the vault, from the -notes file
+	Note string
+	// Timeline is the backup time and reshare nonces of each input file holding the vault
+	Timeline []TimelineEntry
+}
+
+// SingleSigner reports whether one backup file holds enough shares to recover the vault on its own, as for the vaults
+// of a single signer (threshold 1). No other file or party is then involved in the recovery.
+func (v VaultPickerItem) SingleSigner() bool {
+	if len(v.HeldShares) == 0 || v.Quorum < 1 {
+		return false
+	}
+	shareIDs := make(map[string]bool, len(v.HeldShares))
+	for _, held := range v.HeldShares {
+		if held.File != v.HeldShares[0].File {
+			return false
+		}
+		shareIDs[held.ShareID] = true
+	}
+	return len(shareIDs) >= v.Quorum
+}
+
+// SharesSummary describes the shares of the vault for the picker and the confirmation screens.
+func (v VaultPickerItem) SharesSummary() string {
+	if v.SingleSigner() {
+		return fmt.Sprintf("single signer, recovered from %s alone", filepath.Base(v.HeldShares[0].File))
+	}
+	return fmt.Sprintf("%d of %d shares", v.NumberOfShares, v.Quorum)
+}
+
+// HeldShare is a share of a vault and the input file it was found in.
+type HeldShare struct {
+	ShareID string
+	File    string
 }
 
+// vaultPickerPageSize is the number of vaults shown at once in the vault picker.
+const vaultPickerPageSize = 15
+
 func RunVaultPickerForm(vaultsData []VaultPickerItem) (string, error) {
 	var chosenVaultId string
 
 	vaultSelectOptions := make([]huh.Option[string], len(vaultsData))
 	for i, vault := range vaultsData {
-		vaultSelectOptions[i] = huh.NewOption(fmt.Sprintf("%s (%d/%d)", vault.Name, vault.NumberOfShares, vault.Quorum), vault.VaultID)
+		label := fmt.Sprintf("%s (%d/%d)", vault.Name, vault.NumberOfShares, vault.Quorum)
+		if vault.SingleSigner() {
+			label = fmt.Sprintf("%s (single signer)", vault.Name)
+		}
+		if vault.Note != "" {
+			label += Plain(" — ") + vault.Note
+		}
+		vaultSelectOptions[i] = huh.NewOption(label, vault.VaultID)
 	}
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Select a vault").
-				Options(vaultSelectOptions...).
-				Value(&chosenVaultId),
-		),
-	).WithTheme(huh.ThemeBase16())
+	picker := huh.NewSelect[string]().
+		Title("Select a vault").
+		Options(vaultSelectOptions...).
+		Value(&chosenVaultId)
+	// hundreds of vaults do not fit on a screen: show a page of them, and their count and how to filter them
+	if len(vaultsData) > vaultPickerPageSize {
+		picker.Title(fmt.Sprintf("Select a vault (%d vaults, type / to filter by name)", len(vaultsData))).
+			Height(vaultPickerPageSize + 2)
+	}
+	form := huh.NewForm(huh.NewGroup(picker)).WithTheme(huh.ThemeBase16())
 	err := form.Run()
 	if err != nil {
 		return "", errors2.Wrapf(err, "unable to run form")
@@ -0,0 +1,75 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInput_SummaryTable(t *testing.T) {
+	entries := []mnemonicEntry{
+		{VaultsDataFile: VaultsDataFile{File: "a.json"}, status: statusValidated, vaults: 3},
+		{VaultsDataFile: VaultsDataFile{File: "b.json"}, status: statusFailed, vaults: -1, err: errors.New("⚠ failed to decrypt vault")},
+		{VaultsDataFile: VaultsDataFile{File: "c.json"}, status: statusPending, vaults: -1},
+	}
+	out := summaryTable(entries)
+	assert.Contains(t, out, "1. a.json")
+	assert.Contains(t, out, "validated")
+	assert.Contains(t, out, "failed")
+	assert.Contains(t, out, "pending")
+	assert.Contains(t, out, "3")
+	assert.Contains(t, out, "b.json: ⚠ failed to decrypt vault")
+}
+
+func TestInput_PairPhrase(t *testing.T) {
+	m := NewMnemonicsForm(config.AppConfig{}).
+		WithChecker(func(file VaultsDataFile) (int, error) {
+			if file.Mnemonics != "phrase of "+file.File {
+				return 0, errors.New("⚠ the phrase does not belong to this file")
+			}
+			return 1, nil
+		}).
+		WithMatcher(func(file VaultsDataFile) bool {
+			return file.Mnemonics == "phrase of "+file.File
+		})
+	entries := []mnemonicEntry{
+		{VaultsDataFile: VaultsDataFile{File: "a.json", Mnemonics: "phrase of a.json"}, status: statusValidated, vaults: 1},
+		{VaultsDataFile: VaultsDataFile{File: "b.json"}, status: statusPending, vaults: -1},
+		{VaultsDataFile: VaultsDataFile{File: "c.json"}, status: statusPending, vaults: -1},
+	}
+
+	assert.Equal(t, 2, m.pair(entries, 1, "phrase of c.json"))
+	assert.Equal(t, statusValidated, entries[2].status)
+	assert.Equal(t, "phrase of c.json", entries[2].Mnemonics)
+	assert.Equal(t, 1, entries[2].vaults)
+
+	// validated files are not re-paired, and unknown phrases are not paired at all
+	assert.Equal(t, -1, m.pair(entries, 1, "phrase of a.json"))
+	assert.Equal(t, -1, m.pair(entries, 1, "unknown phrase"))
+	assert.Equal(t, -1, NewMnemonicsForm(config.AppConfig{}).pair(entries, 1, "phrase of c.json"))
+}
+
+func TestInput_SingleSigner(t *testing.T) {
+	single := VaultPickerItem{Quorum: 2, NumberOfShares: 2, HeldShares: []HeldShare{
+		{ShareID: "1", File: "dir/single.json"}, {ShareID: "2", File: "dir/single.json"},
+	}}
+	assert.True(t, single.SingleSigner())
+	assert.Equal(t, "single signer, recovered from single.json alone", single.SharesSummary())
+
+	// a full quorum across two files is not a single signer
+	split := VaultPickerItem{Quorum: 2, NumberOfShares: 2, HeldShares: []HeldShare{
+		{ShareID: "1", File: "a.json"}, {ShareID: "2", File: "b.json"},
+	}}
+	assert.False(t, split.SingleSigner())
+	assert.Equal(t, "2 of 2 shares", split.SharesSummary())
+
+	short := VaultPickerItem{Quorum: 2, NumberOfShares: 1, HeldShares: []HeldShare{{ShareID: "1", File: "a.json"}}}
+	assert.False(t, short.SingleSigner())
+	assert.False(t, VaultPickerItem{HeldShares: single.HeldShares}.SingleSigner(), "an unknown quorum is never single signer")
+}
@@ -5,16 +5,19 @@
 package ui
 
 import (
+	"crypto/sha256"
 	"os"
 	"strings"
+	"unicode"
 
 	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/source"
 	errors2 "github.com/pkg/errors"
+	"golang.org/x/text/unicode/norm"
 )
 
 func (v VaultsDataFile) ValidateMnemonics() error {
-	phrase := cleanMnemonicInput(v.Mnemonics)
-	words := strings.Split(phrase, " ")
+	words := strings.Fields(CleanMnemonicInput(v.Mnemonics))
 	if len(words) != WORDS {
 		return errors2.Errorf("⚠ wanted %d phrase words but got %d", WORDS, len(words))
 	}
@@ -36,14 +39,28 @@ func ValidateFiles(appConfig config.AppConfig) error {
 				return errors2.Errorf("⚠ duplicate file `%s`", file)
 			}
 			uniqueFiles[file] = struct{}{}
+			if appConfig.ReadOnlySource {
+				if err := source.CheckReadOnly(file); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
+	contents := make(map[string][]byte, len(files))
 	for _, file := range files {
 		// read file and basic validate
-		if _, err := os.Stat(file); err != nil {
+		info, err := os.Stat(file)
+		if err != nil {
 			return errors2.Errorf("unable to see file `%s` - does it exist?: %s", file, err)
 		}
+		// QR code image directories are validated when decoded
+		if appConfig.QRInput {
+			if !info.IsDir() {
+				return errors2.Errorf("⚠ `%s` is not a directory of QR code images", file)
+			}
+			continue
+		}
 		// fmt.Print("Reading file ", file, " ... ")
 
 		content, err := os.ReadFile(file)
@@ -53,13 +70,42 @@ func ValidateFiles(appConfig config.AppConfig) error {
 		if len(content) == 0 || content[0] != '{' {
 			return errors2.Errorf("⚠ invalid file format, expecting json. first char is %s", content[:1])
 		}
+		contents[file] = content
+	}
+	return CheckDuplicateContent(files, contents)
+}
+
+// CheckDuplicateContent ensures that no backup file was given twice under different paths, e.g. a copy in another
+// directory. A duplicate would otherwise count as a second share of the same party.
+func CheckDuplicateContent(files []string, contents map[string][]byte) error {
+	seen := make(map[[sha256.Size]byte]string, len(files))
+	for _, file := range files {
+		content, ok := contents[file]
+		if !ok {
+			continue
+		}
+		hash := sha256.Sum256(content)
+		if first, ok := seen[hash]; ok {
+			return errors2.Errorf("⚠ `%s` is the same backup file as `%s`", file, first)
+		}
+		seen[hash] = file
 	}
 	return nil
 }
 
-func cleanMnemonicInput(input string) string {
-	input = strings.Replace(input, "\n", "", -1)
-	input = strings.Replace(input, "\r", "", -1)
-	input = strings.TrimSpace(input)
-	return input
+// CleanMnemonicInput normalizes a phrase to lower case words separated by single spaces, so that a phrase pasted from a
+// password manager or a document is accepted as it is. Word numbers such as "1." or "(2)", punctuation, quotes, bullets
+// and line breaks are separators, invisible formatting characters such as zero-width spaces are dropped, and
+// compatibility forms such as full-width letters are folded to plain ones.
+func CleanMnemonicInput(input string) string {
+	input = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, norm.NFKC.String(input))
+	words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r)
+	})
+	return strings.Join(words, " ")
 }
@@ -0,0 +1,87 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_DuplicateContent(t *testing.T) {
+	dir := t.TempDir()
+	a, b, c := filepath.Join(dir, "a.json"), filepath.Join(dir, "copy-of-a.json"), filepath.Join(dir, "c.json")
+	require.NoError(t, os.WriteFile(a, []byte(`{"vaults":{}}`), 0600))
+	require.NoError(t, os.WriteFile(b, []byte(`{"vaults":{}}`), 0600))
+	require.NoError(t, os.WriteFile(c, []byte(`{"vaults":{"x":{}}}`), 0600))
+
+	assert.NoError(t, ValidateFiles(config.AppConfig{Filenames: []string{a, c}}))
+	assert.ErrorContains(t, ValidateFiles(config.AppConfig{Filenames: []string{a, c, b}}), "same backup file")
+	assert.ErrorContains(t, ValidateFiles(config.AppConfig{Filenames: []string{a, a}}), "duplicate file")
+}
+
+func TestCleanMnemonicInput(t *testing.T) {
+	const want = "season pole chronic surround fiber"
+	tests := []struct {
+		name, in string
+	}{
+		{"clean", "season pole chronic surround fiber"},
+		{"surrounding and repeated whitespace", "  season   pole\tchronic  surround  fiber \n"},
+		{"one word per line", "season\npole\r\nchronic\rsurround\nfiber\n"},
+		{"upper case", "Season POLE Chronic surround FIBER"},
+		{"numbered with dots", "1. season 2. pole 3. chronic 4. surround 5. fiber"},
+		{"numbered without spaces", "1.season 2.pole 3.chronic 4.surround 5.fiber"},
+		{"numbered with parentheses", "(1) season (2) pole (3) chronic (4) surround (5) fiber"},
+		{"numbered with brackets and colons", "[1]: season [2]: pole [3]: chronic [4]: surround [5]: fiber"},
+		{"numbered lines", "01 season\n02 pole\n03 chronic\n04 surround\n05 fiber"},
+		{"numbered with hashes", "#1 season #2 pole #3 chronic #4 surround #5 fiber"},
+		{"numbered with dashes", "1 - season, 2 - pole, 3 - chronic, 4 - surround, 5 - fiber"},
+		{"table columns", "1\tseason\t2\tpole\t3\tchronic\n4\tsurround\t5\tfiber"},
+		{"comma separated", "season,pole,chronic,surround,fiber"},
+		{"comma and space separated", "season, pole, chronic, surround, fiber"},
+		{"semicolons and pipes", "season;pole|chronic / surround ; fiber"},
+		{"json array", `["season", "pole", "chronic", "surround", "fiber"]`},
+		{"smart quotes", "“season pole chronic surround fiber”"},
+		{"single smart quotes", "‘season’ ‘pole’ ‘chronic’ ‘surround’ ‘fiber’"},
+		{"bullets", "• season\n• pole\n• chronic\n• surround\n• fiber"},
+		{"em dashes", "season—pole–chronic-surround-fiber"},
+		{"trailing full stop", "Season pole chronic surround fiber."},
+		{"zero-width spaces", "sea\u200bson\u200b pole chronic\ufeff surround fi\u00adber"},
+		{"ideographic space", "season\u3000pole\u3000chronic\u3000surround\u3000fiber"},
+		{"full-width letters", "ｓｅａｓｏｎ pole chronic surround fiber"},
+		{"full-width numbering", "１． season ２． pole 3. chronic 4. surround 5. fiber"},
+		{"ligature", "season pole chronic surround ﬁber"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, want, CleanMnemonicInput(tt.in))
+		})
+	}
+}
+
+func TestCleanMnemonicInput_KeepsWordsApart(t *testing.T) {
+	// letters that are not in the English wordlist are kept, so that the phrase check names the wrong word
+	assert.Equal(t, "café pole", CleanMnemonicInput("café, pole"))
+	assert.Equal(t, "café pole", CleanMnemonicInput("café pole"))
+	assert.Equal(t, "", CleanMnemonicInput(" 1. 2. , \n"))
+	assert.Equal(t, "", CleanMnemonicInput(""))
+}
+
+func TestValidateMnemonics_Formatting(t *testing.T) {
+	words := make([]string, WORDS)
+	for i := range words {
+		words[i] = fmt.Sprintf("%d. abandon", i+1)
+	}
+	assert.NoError(t, VaultsDataFile{Mnemonics: strings.Join(words, "\n")}.ValidateMnemonics())
+	assert.NoError(t, VaultsDataFile{Mnemonics: strings.Repeat("abandon,", WORDS)}.ValidateMnemonics())
+	assert.EqualError(t, VaultsDataFile{Mnemonics: "abandon abandon"}.ValidateMnemonics(), fmt.Sprintf("⚠ wanted %d phrase words but got 2", WORDS))
+	assert.EqualError(t, VaultsDataFile{Mnemonics: " "}.ValidateMnemonics(), fmt.Sprintf("⚠ wanted %d phrase words but got 0", WORDS))
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Version of the tool, shown in the banner and included in support bundles and summaries. Release builds set it from
+// the git tag with -ldflags "-X github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui.Version=<tag>", see the
+// Makefile. Other builds fall back to the version recorded by the go command.
+var Version string
+
+func init() {
+	if Version == "" {
+		info, _ := debug.ReadBuildInfo()
+		Version = buildVersion(info)
+	}
+	Version = strings.TrimPrefix(Version, "v")
+}
+
+// buildVersion is the module version of a `go install`ed build, or else dev with the VCS revision of a source build.
+func buildVersion(info *debug.BuildInfo) string {
+	if info == nil {
+		return "dev"
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	var revision, dirty string
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && len(setting.Value) >= 12:
+			revision = "+" + setting.Value[:12]
+		case setting.Key == "vcs.modified" && setting.Value == "true":
+			dirty = "-dirty"
+		}
+	}
+	return "dev" + revision + dirty
+}
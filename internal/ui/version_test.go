@@ -0,0 +1,29 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ui
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersion(t *testing.T) {
+	assert.NotEmpty(t, Version)
+	assert.NotEqual(t, 'v', rune(Version[0]), "the banner adds the v")
+	assert.Contains(t, Banner(), "v"+Version)
+
+	assert.Equal(t, "dev", buildVersion(nil))
+	assert.Equal(t, "v5.3.0", buildVersion(&debug.BuildInfo{Main: debug.Module{Version: "v5.3.0"}}))
+	assert.Equal(t, "dev+0123456789ab-dirty", buildVersion(&debug.BuildInfo{Main: debug.Module{Version: "(devel)"}, Settings: []debug.BuildSetting{
+		{Key: "vcs.revision", Value: "0123456789abcdef0123456789abcdef01234567"}, {Key: "vcs.modified", Value: "true"},
+	}}))
+}
+
+func TestCentered(t *testing.T) {
+	assert.Equal(t, "  v5.3.0   ", centered("v5.3.0", 11))
+	assert.Len(t, centered("v5.3.0", 37), 37)
+}
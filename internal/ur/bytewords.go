@@ -0,0 +1,71 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ur
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+)
+
+// bytewords is the BCR-2020-012 word list. The "minimal" encoding used in URs keeps the first and last letter of each word.
+const bytewords = "able acid also apex aqua arch atom aunt away axis back bald barn belt beta bias blue body brag brew bulb buzz " +
+	"calm cash cats chef city claw code cola cook cost crux curl cusp cyan dark data days deli dice diet door down draw drop " +
+	"drum dull duty each easy echo edge epic even exam exit eyes fact fair fern figs film fish fizz flap flew flux foxy free " +
+	"frog fuel fund gala game gear gems gift girl glow good gray grim guru gush gyro half hang hard hawk heat help high hill " +
+	"holy hope horn huts iced idea idle inch inky into iris iron item jade jazz join jolt jowl judo jugs jump junk jury keep " +
+	"keno kept keys kick kiln king kite kiwi knob lamb lava lazy leaf legs liar limp lion list logo loud love luau luck lung " +
+	"main many math maze memo menu meow mild mint miss monk nail navy need news next noon note numb obey oboe omit onyx open " +
+	"oval owls paid part peck play plus poem pool pose puff puma purr quad quiz race ramp real redo rich road rock roof ruby " +
+	"ruin runs rust safe saga scar sets silk skew slot soap solo song stub surf swan taco task taxi tent tied time tiny toil " +
+	"tomb toys trip tuna twin ugly undo unit urge user vast very veto vial vibe view visa void vows wall wand warm wasp wave " +
+	"waxy webs what when whiz wolf work yank yawn yell yoga yurt zaps zero zest zinc zone zoom"
+
+var (
+	ErrBytewords = errors.New("invalid bytewords")
+
+	minimalWords [256]string
+	minimalIndex = make(map[string]byte, 256)
+)
+
+func init() {
+	for i, word := range strings.Fields(bytewords) {
+		minimalWords[i] = word[:1] + word[3:]
+		minimalIndex[minimalWords[i]] = byte(i)
+	}
+}
+
+// encodeMinimal encodes data, followed by its CRC32 checksum, in minimal bytewords.
+func encodeMinimal(data []byte) string {
+	withChecksum := binary.BigEndian.AppendUint32(append([]byte{}, data...), crc32.ChecksumIEEE(data))
+	var sb strings.Builder
+	sb.Grow(len(withChecksum) * 2)
+	for _, b := range withChecksum {
+		sb.WriteString(minimalWords[b])
+	}
+	return sb.String()
+}
+
+// decodeMinimal decodes minimal bytewords and verifies the trailing CRC32 checksum.
+func decodeMinimal(s string) ([]byte, error) {
+	s = strings.ToLower(s)
+	if len(s)%2 != 0 || len(s) < 10 {
+		return nil, ErrBytewords
+	}
+	withChecksum := make([]byte, len(s)/2)
+	for i := range withChecksum {
+		b, ok := minimalIndex[s[i*2:i*2+2]]
+		if !ok {
+			return nil, ErrBytewords
+		}
+		withChecksum[i] = b
+	}
+	data, checksum := withChecksum[:len(withChecksum)-4], withChecksum[len(withChecksum)-4:]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(checksum) {
+		return nil, errors.New("invalid bytewords checksum")
+	}
+	return data, nil
+}
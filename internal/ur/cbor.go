@@ -0,0 +1,77 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ur
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Just enough CBOR (RFC 8949) for URs: unsigned ints, byte strings, arrays, maps and booleans.
+const (
+	cborUint  = 0
+	cborBytes = 2
+	cborArray = 4
+	cborMap   = 5
+
+	cborTrue = 0xf5
+)
+
+var errCBOR = errors.New("invalid cbor")
+
+func cborAppendHead(bz []byte, major byte, val uint64) []byte {
+	switch {
+	case val < 24:
+		return append(bz, major<<5|byte(val))
+	case val <= 0xff:
+		return append(bz, major<<5|24, byte(val))
+	case val <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(bz, major<<5|25), uint16(val))
+	case val <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(bz, major<<5|26), uint32(val))
+	default:
+		return binary.BigEndian.AppendUint64(append(bz, major<<5|27), val)
+	}
+}
+
+func cborAppendBytes(bz, data []byte) []byte {
+	return append(cborAppendHead(bz, cborBytes, uint64(len(data))), data...)
+}
+
+func cborReadHead(bz []byte) (major byte, val uint64, rest []byte, err error) {
+	if len(bz) == 0 {
+		return 0, 0, nil, errCBOR
+	}
+	major, info, bz := bz[0]>>5, bz[0]&0x1f, bz[1:]
+	switch {
+	case info < 24:
+		return major, uint64(info), bz, nil
+	case info == 24 && len(bz) >= 1:
+		return major, uint64(bz[0]), bz[1:], nil
+	case info == 25 && len(bz) >= 2:
+		return major, uint64(binary.BigEndian.Uint16(bz)), bz[2:], nil
+	case info == 26 && len(bz) >= 4:
+		return major, uint64(binary.BigEndian.Uint32(bz)), bz[4:], nil
+	case info == 27 && len(bz) >= 8:
+		return major, binary.BigEndian.Uint64(bz), bz[8:], nil
+	}
+	return 0, 0, nil, errCBOR
+}
+
+func cborReadUint(bz []byte) (uint64, []byte, error) {
+	major, val, rest, err := cborReadHead(bz)
+	if err != nil || major != cborUint {
+		return 0, nil, errCBOR
+	}
+	return val, rest, nil
+}
+
+func cborReadBytes(bz []byte) ([]byte, []byte, error) {
+	major, n, rest, err := cborReadHead(bz)
+	if err != nil || major != cborBytes || uint64(len(rest)) < n {
+		return nil, nil, errCBOR
+	}
+	return rest[:n], rest[n:], nil
+}
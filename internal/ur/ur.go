@@ -0,0 +1,173 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package ur encodes and decodes Uniform Resources (BCR-2020-005), in a single part or split across multiple parts,
+// as used to move data in and out of air-gapped devices through a series of QR codes.
+package ur
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+const (
+	scheme   = "ur:"
+	typeName = "bytes"
+
+	// BCR-2020-008
+	ecKeyTypeName   = "crypto-eckey"
+	ecKeyIsPrivate  = 2
+	ecKeyData       = 3
+	ecKeyPrivateLen = 32
+)
+
+// EncodeECKey encodes a secp256k1 private key as a single-part "crypto-eckey" UR, as imported by QR based wallets.
+func EncodeECKey(privKey []byte) (string, error) {
+	if len(privKey) != ecKeyPrivateLen {
+		return "", fmt.Errorf("private key must be %d bytes, got %d", ecKeyPrivateLen, len(privKey))
+	}
+	// the curve key is omitted, which means secp256k1
+	message := cborAppendHead(nil, cborMap, 2)
+	message = append(cborAppendHead(message, cborUint, ecKeyIsPrivate), cborTrue)
+	message = cborAppendBytes(cborAppendHead(message, cborUint, ecKeyData), privKey)
+	return strings.ToUpper(scheme + ecKeyTypeName + "/" + encodeMinimal(message)), nil
+}
+
+// Encode encodes data as a "bytes" UR, split into as few parts as needed to keep each fragment within maxFragmentLen bytes.
+// The parts are uppercase, which QR codes store more compactly in alphanumeric mode.
+func Encode(data []byte, maxFragmentLen int) []string {
+	message := cborAppendBytes(nil, data)
+	if len(message) <= maxFragmentLen {
+		return []string{strings.ToUpper(scheme + typeName + "/" + encodeMinimal(message))}
+	}
+
+	seqLen := (len(message) + maxFragmentLen - 1) / maxFragmentLen
+	fragmentLen := (len(message) + seqLen - 1) / seqLen
+	padded := make([]byte, seqLen*fragmentLen)
+	copy(padded, message)
+	checksum := crc32.ChecksumIEEE(message)
+
+	parts := make([]string, seqLen)
+	for i := range parts {
+		part := cborAppendHead(nil, cborArray, 5)
+		part = cborAppendHead(part, cborUint, uint64(i+1))
+		part = cborAppendHead(part, cborUint, uint64(seqLen))
+		part = cborAppendHead(part, cborUint, uint64(len(message)))
+		part = cborAppendHead(part, cborUint, uint64(checksum))
+		part = cborAppendBytes(part, padded[i*fragmentLen:(i+1)*fragmentLen])
+		parts[i] = strings.ToUpper(fmt.Sprintf("%s%s/%d-%d/%s", scheme, typeName, i+1, seqLen, encodeMinimal(part)))
+	}
+	return parts
+}
+
+// Decode reassembles and decodes the parts of a "bytes" UR, which may be given in any order.
+// Only the "pure" parts of a multi-part UR are used; fountain-coded parts beyond the sequence length are skipped.
+func Decode(parts []string) ([]byte, error) {
+	if len(parts) == 0 {
+		return nil, errors.New("no UR parts")
+	}
+	var (
+		fragments                [][]byte
+		seqLen, msgLen, checksum uint64
+	)
+	for _, part := range parts {
+		seq, body, err := parsePart(part)
+		if err != nil {
+			return nil, err
+		}
+		bz, err := decodeMinimal(body)
+		if err != nil {
+			return nil, err
+		}
+
+		// single part
+		if seq == "" {
+			if len(parts) != 1 {
+				return nil, errors.New("single-part UR mixed with other parts")
+			}
+			data, _, err := cborReadBytes(bz)
+			return data, err
+		}
+
+		// multi part
+		major, n, bz, err := cborReadHead(bz)
+		if err != nil || major != cborArray || n != 5 {
+			return nil, fmt.Errorf("invalid UR part %s", seq)
+		}
+		var partSeqNum, partSeqLen, partMsgLen, partChecksum uint64
+		for _, field := range []*uint64{&partSeqNum, &partSeqLen, &partMsgLen, &partChecksum} {
+			if *field, bz, err = cborReadUint(bz); err != nil {
+				return nil, fmt.Errorf("invalid UR part %s", seq)
+			}
+		}
+		fragment, _, err := cborReadBytes(bz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UR part %s", seq)
+		}
+		if fragments == nil {
+			if partSeqLen == 0 || partSeqLen > uint64(len(parts)) {
+				return nil, fmt.Errorf("incomplete UR: have %d parts, need %d", len(parts), partSeqLen)
+			}
+			seqLen, msgLen, checksum = partSeqLen, partMsgLen, partChecksum
+			fragments = make([][]byte, seqLen)
+		}
+		if partSeqLen != seqLen || partMsgLen != msgLen || partChecksum != checksum {
+			return nil, fmt.Errorf("UR part %s belongs to a different message", seq)
+		}
+		if partSeqNum < 1 || partSeqNum > seqLen {
+			continue // fountain-coded
+		}
+		fragments[partSeqNum-1] = fragment
+	}
+
+	message := make([]byte, 0, msgLen)
+	for i, fragment := range fragments {
+		if fragment == nil {
+			return nil, fmt.Errorf("incomplete UR: missing part %d of %d", i+1, seqLen)
+		}
+		message = append(message, fragment...)
+	}
+	if uint64(len(message)) < msgLen {
+		return nil, errors.New("incomplete UR: message too short")
+	}
+	message = message[:msgLen]
+	if crc32.ChecksumIEEE(message) != uint32(checksum) {
+		return nil, errors.New("invalid UR message checksum")
+	}
+	data, _, err := cborReadBytes(message)
+	return data, err
+}
+
+// IsUR reports whether s looks like a UR part.
+func IsUR(s string) bool {
+	return strings.HasPrefix(strings.ToLower(s), scheme)
+}
+
+func parsePart(part string) (seq, body string, err error) {
+	part = strings.ToLower(strings.TrimSpace(part))
+	if !IsUR(part) {
+		return "", "", errors.New("not a UR")
+	}
+	components := strings.Split(strings.TrimPrefix(part, scheme), "/")
+	if components[0] != typeName {
+		return "", "", fmt.Errorf("unsupported UR type `%s`", components[0])
+	}
+	switch len(components) {
+	case 2:
+		return "", components[1], nil
+	case 3:
+		seqNum, seqLen, found := strings.Cut(components[1], "-")
+		if _, err1 := strconv.Atoi(seqNum); !found || err1 != nil {
+			return "", "", fmt.Errorf("invalid UR sequence `%s`", components[1])
+		}
+		if _, err2 := strconv.Atoi(seqLen); err2 != nil {
+			return "", "", fmt.Errorf("invalid UR sequence `%s`", components[1])
+		}
+		return components[1], components[2], nil
+	}
+	return "", "", errors.New("invalid UR")
+}
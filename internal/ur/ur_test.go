@@ -0,0 +1,76 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package ur
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytewords_Minimal(t *testing.T) {
+	// test vector from BCR-2020-012
+	encoded := encodeMinimal([]byte{0, 1, 2, 128, 255})
+	if !assert.Equal(t, "aeadaolazmjendeoti", encoded) {
+		return
+	}
+	decoded, err := decodeMinimal("AEADAOLAZMJENDEOTI")
+	if !assert.NoError(t, err) || !assert.Equal(t, []byte{0, 1, 2, 128, 255}, decoded) {
+		return
+	}
+	_, err = decodeMinimal("aeadaolazmjendeota")
+	assert.Error(t, err)
+}
+
+func TestUR_RoundTrip(t *testing.T) {
+	data := make([]byte, 1000)
+	_, _ = rand.Read(data)
+
+	single := Encode(data[:100], 200)
+	if !assert.Len(t, single, 1) {
+		return
+	}
+	decoded, err := Decode(single)
+	if !assert.NoError(t, err) || !assert.Equal(t, data[:100], decoded) {
+		return
+	}
+
+	parts := Encode(data, 150)
+	if !assert.Len(t, parts, 7) {
+		return
+	}
+	// order must not matter
+	reversed := make([]string, len(parts))
+	for i, part := range parts {
+		reversed[len(parts)-1-i] = part
+	}
+	decoded, err = Decode(reversed)
+	if !assert.NoError(t, err) || !assert.True(t, bytes.Equal(data, decoded)) {
+		return
+	}
+
+	// a missing part is reported
+	withDuplicate := append(append([]string{}, parts[:6]...), parts[0])
+	_, err = Decode(withDuplicate)
+	assert.ErrorContains(t, err, "missing part 7 of 7")
+}
+
+func TestUR_EncodeECKey(t *testing.T) {
+	privKey := bytes.Repeat([]byte{0x01}, 32)
+	encoded, err := EncodeECKey(privKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// {2: true, 3: h'0101…01'}
+	expMessage := append([]byte{0xa2, 0x02, 0xf5, 0x03, 0x58, 0x20}, privKey...)
+	if !assert.Equal(t, "UR:CRYPTO-ECKEY/"+strings.ToUpper(encodeMinimal(expMessage)), encoded) {
+		return
+	}
+	_, err = EncodeECKey(privKey[:31])
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build entropytest
+
+package walletv3
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/entropy"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestWalletV3_Golden(t *testing.T) {
+	t.Cleanup(entropy.Use(entropy.NewDeterministic("walletv3")))
+	privKey := append(bytes.Repeat([]byte{0}, 31), 1)
+	keyfile, err := Encrypt(privKey, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", "password", Presets["light"])
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "keystore.golden.json")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, keyfile, 0644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(keyfile))
+
+	key, err := keystore.DecryptKey(keyfile, "password")
+	require.NoError(t, err)
+	assert.Equal(t, privKey, crypto.FromECDSA(key.PrivateKey))
+}
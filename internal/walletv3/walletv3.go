@@ -0,0 +1,188 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+// Package walletv3 writes Ethereum wallet v3 (keystore) JSON files with configurable scrypt parameters.
+package walletv3
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/entropy"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	version = 3
+	dkLen   = 32
+
+	// maxMemory caps the scrypt memory use (128 * N * r bytes), so that the file can be unlocked on a regular laptop
+	maxMemory = 1 << 30
+
+	// benchmarkN is the scrypt cost used to estimate the unlock time on this machine
+	benchmarkN = 1 << 12
+)
+
+// KDFParams are the scrypt parameters of a wallet v3 file.
+type KDFParams struct {
+	N, R, P int
+}
+
+// Presets are the named -ks-kdf options. Light and standard match the go-ethereum keystore presets.
+var Presets = map[string]KDFParams{
+	"light":    {N: 1 << 12, R: 8, P: 6},
+	"standard": {N: 1 << 18, R: 8, P: 1},
+	"paranoid": {N: 1 << 20, R: 8, P: 1},
+}
+
+// ParseKDF parses a preset name, or explicit parameters in the form "n=262144,r=8,p=1".
+func ParseKDF(s string) (KDFParams, error) {
+	if params, ok := Presets[strings.ToLower(s)]; ok {
+		return params, nil
+	}
+	params := KDFParams{R: 8, P: 1}
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return KDFParams{}, fmt.Errorf("⚠ invalid KDF option `%s`: use light, standard, paranoid or n=N,r=R,p=P", s)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return KDFParams{}, fmt.Errorf("⚠ invalid KDF parameter `%s`: %v", part, err)
+		}
+		switch strings.ToLower(name) {
+		case "n":
+			params.N = n
+		case "r":
+			params.R = n
+		case "p":
+			params.P = n
+		default:
+			return KDFParams{}, fmt.Errorf("⚠ unknown KDF parameter `%s`", name)
+		}
+	}
+	return params, params.Validate()
+}
+
+// Validate ensures that scrypt accepts the parameters and that unlocking the file does not need excessive memory.
+func (k KDFParams) Validate() error {
+	if k.N <= 1 || k.N&(k.N-1) != 0 {
+		return fmt.Errorf("⚠ the scrypt N parameter must be a power of 2 greater than 1, got %d", k.N)
+	}
+	if k.R < 1 || k.P < 1 || uint64(k.R)*uint64(k.P) >= 1<<30 {
+		return fmt.Errorf("⚠ invalid scrypt parameters r=%d p=%d", k.R, k.P)
+	}
+	if uint64(128)*uint64(k.N)*uint64(k.R) > maxMemory {
+		return fmt.Errorf("⚠ the scrypt parameters n=%d r=%d need more than %d MiB of memory to unlock", k.N, k.R, maxMemory>>20)
+	}
+	return nil
+}
+
+func (k KDFParams) String() string {
+	return fmt.Sprintf("scrypt n=%d r=%d p=%d", k.N, k.R, k.P)
+}
+
+// EstimateUnlockTime measures a small scrypt run on this machine and scales it to the parameters.
+// Unlocking in a wallet takes about as long as encrypting, so this is shown to the user before the file is written.
+func (k KDFParams) EstimateUnlockTime() (time.Duration, error) {
+	start := time.Now()
+	if _, err := scrypt.Key([]byte("benchmark"), make([]byte, 32), benchmarkN, k.R, 1, dkLen); err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+	return time.Duration(float64(elapsed) * float64(k.N) / benchmarkN * float64(k.P)), nil
+}
+
+type (
+	walletJSON struct {
+		Address string     `json:"address"`
+		Crypto  cryptoJSON `json:"crypto"`
+		ID      string     `json:"id"`
+		Version int        `json:"version"`
+	}
+	cryptoJSON struct {
+		Cipher       string           `json:"cipher"`
+		CipherText   string           `json:"ciphertext"`
+		CipherParams cipherParamsJSON `json:"cipherparams"`
+		KDF          string           `json:"kdf"`
+		KDFParams    kdfParamsJSON    `json:"kdfparams"`
+		MAC          string           `json:"mac"`
+	}
+	cipherParamsJSON struct {
+		IV string `json:"iv"`
+	}
+	kdfParamsJSON struct {
+		DKLen int    `json:"dklen"`
+		N     int    `json:"n"`
+		P     int    `json:"p"`
+		R     int    `json:"r"`
+		Salt  string `json:"salt"`
+	}
+)
+
+// Encrypt creates the wallet v3 JSON of a 32 byte private key, in the same format as the go-ethereum keystore.
+// The address is the hex Ethereum address of the key, with or without the 0x prefix. Its ID, salt and IV are read
+// from the entropy package.
+func Encrypt(privKey []byte, address, password string, kdf KDFParams) ([]byte, error) {
+	if len(privKey) != 32 {
+		return nil, fmt.Errorf("⚠ expected a 32 byte private key, got %d bytes", len(privKey))
+	}
+	if err := kdf.Validate(); err != nil {
+		return nil, err
+	}
+	id, err := uuid.NewRandomFromReader(entropy.Reader())
+	if err != nil {
+		return nil, fmt.Errorf("⚠ could not create random uuid: %v", err)
+	}
+	salt, iv := make([]byte, 32), make([]byte, aes.BlockSize)
+	if err = entropy.Read(salt); err != nil {
+		return nil, err
+	}
+	if err = entropy.Read(iv); err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(password), salt, kdf.N, kdf.R, kdf.P, dkLen)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(derivedKey)
+
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	cipherText := make([]byte, len(privKey))
+	cipher.NewCTR(block, iv).XORKeyStream(cipherText, privKey)
+	mac := sha3.NewLegacyKeccak256()
+	mac.Write(derivedKey[16:32])
+	mac.Write(cipherText)
+
+	return json.Marshal(walletJSON{
+		Address: strings.ToLower(strings.TrimPrefix(address, "0x")),
+		Crypto: cryptoJSON{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: kdfParamsJSON{
+				DKLen: dkLen,
+				N:     kdf.N,
+				P:     kdf.P,
+				R:     kdf.R,
+				Salt:  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac.Sum(nil)),
+		},
+		ID:      id.String(),
+		Version: version,
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package walletv3
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletV3_ParseKDF(t *testing.T) {
+	params, err := ParseKDF("standard")
+	require.NoError(t, err)
+	assert.Equal(t, KDFParams{N: 1 << 18, R: 8, P: 1}, params)
+
+	params, err = ParseKDF("n=16384,r=4,p=2")
+	require.NoError(t, err)
+	assert.Equal(t, KDFParams{N: 16384, R: 4, P: 2}, params)
+
+	for _, bad := range []string{"fast", "n=1000", "n=16384,x=1", "n=16384,r=0", "n=1073741824,r=8"} {
+		_, err = ParseKDF(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestWalletV3_EncryptDecryptsWithGoEthereum(t *testing.T) {
+	// go-ethereum's crypto.GenerateKey goes through crypto/ecdsa, which BoringCrypto refuses for secp256k1
+	sk, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	privKey, err := crypto.ToECDSA(sk.Serialize())
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(privKey.PublicKey)
+
+	for _, kdf := range []KDFParams{Presets["light"], {N: 1 << 10, R: 4, P: 2}} {
+		keyfile, err := Encrypt(crypto.FromECDSA(privKey), address.Hex(), "password", kdf)
+		require.NoError(t, err)
+
+		key, err := keystore.DecryptKey(keyfile, "password")
+		require.NoError(t, err, kdf.String())
+		assert.Equal(t, privKey.D, key.PrivateKey.D)
+		assert.Equal(t, address, key.Address)
+
+		_, err = keystore.DecryptKey(keyfile, "wrong password")
+		assert.Error(t, err)
+	}
+}
+
+func TestWalletV3_EstimateUnlockTime(t *testing.T) {
+	light, err := Presets["light"].EstimateUnlockTime()
+	require.NoError(t, err)
+	paranoid, err := Presets["paranoid"].EstimateUnlockTime()
+	require.NoError(t, err)
+	assert.Positive(t, light)
+	assert.Greater(t, paranoid, light)
+}
@@ -39,10 +39,11 @@ func b58encode(b []byte) (s string) {
 /* Base-58 Check Encode/Decode */
 /******************************************************************************/
 
-// b58checkencode encodes version ver and byte slice b into a base-58 check encoded string.
-func b58checkencode(ver uint8, b []byte) (s string) {
+// b58checkencode encodes the version bytes ver and byte slice b into a base-58 check encoded string.
+// Bitcoin uses a single version byte; some derivatives such as Zcash use two.
+func b58checkencode(ver []byte, b []byte) (s string) {
 	/* Prepend version */
-	bcpy := append([]byte{ver}, b...)
+	bcpy := append(append([]byte{}, ver...), b...)
 
 	/* Create a new SHA256 context */
 	sha256_h := sha256.New()
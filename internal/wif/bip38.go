@@ -0,0 +1,62 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package wif
+
+import (
+	"crypto/aes"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/address"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// The scrypt parameters of BIP38, which are fixed.
+const (
+	bip38ScryptN = 16384
+	bip38ScryptR = 8
+	bip38ScryptP = 8
+)
+
+var (
+	// bip38Prefix marks a key encrypted without EC multiplication, and bip38FlagCompressed a compressed public key
+	bip38Prefix         = []byte{0x01, 0x42}
+	bip38FlagCompressed = byte(0xe0)
+
+	bitcoin = address.UTXOChain{Name: "Bitcoin", P2PKHVersion: []byte{0x00}}
+)
+
+// ToBIP38 encrypts a private key with a passphrase as a BIP38 key (starting with 6P), without EC multiplication, for
+// the Bitcoin mainnet address of its compressed public key. Wallets decrypt it into the compressed mainnet WIF.
+func ToBIP38(privKey []byte, passphrase string) (string, error) {
+	if len(privKey) != 32 {
+		return "", errors.New("⚠ BIP38 encrypts 32 byte private keys")
+	}
+	pubKey := secp256k1.PrivKeyFromBytes(privKey).PubKey().SerializeCompressed()
+	hash1 := sha256.Sum256([]byte(bitcoin.P2PKH(pubKey)))
+	hash2 := sha256.Sum256(hash1[:])
+	addressHash := hash2[:4]
+
+	derived, err := scrypt.Key([]byte(norm.NFC.String(passphrase)), addressHash, bip38ScryptN, bip38ScryptR, bip38ScryptP, 64)
+	if err != nil {
+		return "", err
+	}
+	defer clear(derived)
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return "", err
+	}
+	encrypted := make([]byte, 32)
+	for i := range encrypted {
+		encrypted[i] = privKey[i] ^ derived[i]
+	}
+	block.Encrypt(encrypted[:16], encrypted[:16])
+	block.Encrypt(encrypted[16:], encrypted[16:])
+
+	payload := append(append([]byte{bip38FlagCompressed}, addressHash...), encrypted...)
+	return b58checkencode(bip38Prefix, payload), nil
+}
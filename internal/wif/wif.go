@@ -11,9 +11,17 @@ func ToBitcoinWIF(privKey []byte, testNet, compressed bool) string {
 		privKey = append(privKey, []byte{0x01}...)
 	}
 	// Convert bytes to base-58 check encoded string with version 0x80 (mainnet) or 0xef (testnet)
-	ver := uint8(0x80)
+	ver := []byte{0x80}
 	if testNet {
-		ver = 0xef
+		ver = []byte{0xef}
 	}
 	return b58checkencode(ver, privKey)
 }
+
+// ToWIF converts a private key to the Wallet Import Format of a Bitcoin derivative, by its version bytes.
+func ToWIF(privKey, version []byte, compressed bool) string {
+	if compressed {
+		privKey = append(privKey[:len(privKey):len(privKey)], 0x01)
+	}
+	return b58checkencode(version, privKey)
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package wif
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWIF(t *testing.T) {
+	privKey, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	assert.Equal(t, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", ToBitcoinWIF(privKey, false, true))
+	assert.Equal(t, "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf", ToBitcoinWIF(privKey, false, false))
+	assert.Equal(t, ToBitcoinWIF(privKey, false, true), ToWIF(privKey, []byte{0x80}, true))
+	// Komodo
+	assert.Equal(t, "Up1YVLk7uuErCHVQyFCtfinZngmdwfyfc47WCQ8oJxgowjVzNeqs", ToWIF(privKey, []byte{0xbc}, true))
+	// the key itself is not altered
+	assert.Len(t, privKey, 32)
+}
+
+func TestBIP38(t *testing.T) {
+	// the test vectors of BIP38 for compressed keys without EC multiplication
+	tests := []struct {
+		privKey, passphrase, want string
+	}{
+		{"cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5", "TestingOneTwoThree", "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo"},
+		{"09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae", "Satoshi", "6PYLtMnXvfG3oJde97zRyLYFZCYizPU5T3LwgdYJz1fRhh16bU7u6PPmY7"},
+	}
+	for _, tt := range tests {
+		privKey, err := hex.DecodeString(tt.privKey)
+		assert.NoError(t, err)
+		encrypted, err := ToBIP38(privKey, tt.passphrase)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, encrypted)
+	}
+
+	_, err := ToBIP38(make([]byte, 31), "pass")
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/address"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/wif"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+type (
+	// RecoveryResult is the -output json document of a recovery, printed alone on stdout for downstream tooling. The
+	// private keys are only in it once they were allowed to be shown.
+	RecoveryResult struct {
+		VaultID   string `json:"vaultId"`
+		VaultName string `json:"vaultName"`
+		Address   string `json:"address"`
+		// Chains are the chain IDs selected with -chains, or empty for every chain
+		Chains       []string         `json:"chains,omitempty"`
+		Addresses    []ResultAddress  `json:"addresses"`
+		SecretsShown bool             `json:"secretsShown"`
+		ECDSA        *ResultECDSAKey  `json:"ecdsa,omitempty"`
+		EdDSA        *ResultEdDSAKey  `json:"eddsa,omitempty"`
+		PublicKeys   *ResultPublicKey `json:"publicKeys,omitempty"`
+		BIP85        *ResultBIP85     `json:"bip85,omitempty"`
+		WrittenFiles []string         `json:"writtenFiles"`
+	}
+
+	ResultAddress struct {
+		Chain   string `json:"chain"`
+		Address string `json:"address"`
+		Key     string `json:"key"`
+	}
+
+	ResultECDSAKey struct {
+		PrivateKey string      `json:"privateKey,omitempty"`
+		WIFs       []ResultWIF `json:"wifs,omitempty"`
+		// BIP38 is the Bitcoin mainnet key encrypted with the -bip38-password passphrase
+		BIP38 string `json:"bip38,omitempty"`
+	}
+
+	ResultWIF struct {
+		Chain string `json:"chain"`
+		WIF   string `json:"wif"`
+	}
+
+	ResultEdDSAKey struct {
+		PrivateKey string `json:"privateKey"`
+		PublicKey  string `json:"publicKey"`
+	}
+
+	// ResultBIP85 records the -bip85 child seeds that were issued, so that no index is issued twice. The phrases and the
+	// root xprv are only set when the secrets are shown.
+	ResultBIP85 struct {
+		// RootFingerprint is the BIP32 fingerprint of the BIP85 root, which tells vaults apart without revealing the root
+		RootFingerprint string             `json:"rootFingerprint"`
+		RootXPRV        string             `json:"rootXprv,omitempty"`
+		Words           int                `json:"words"`
+		Seeds           []ResultBIP85Child `json:"seeds"`
+	}
+
+	ResultBIP85Child struct {
+		Index    uint32 `json:"index"`
+		Path     string `json:"path"`
+		Mnemonic string `json:"mnemonic,omitempty"`
+	}
+
+	// ResultPublicKey is the -show-pubkeys view of the ECDSA public key.
+	ResultPublicKey struct {
+		Compressed   string `json:"compressed"`
+		Uncompressed string `json:"uncompressed"`
+		X            string `json:"x"`
+		Y            string `json:"y"`
+	}
+)
+
+// parseOutputFormat checks the -output option.
+func parseOutputFormat(option string) (string, error) {
+	switch option {
+	case outputText, outputJSON:
+		return option, nil
+	}
+	return "", fmt.Errorf("⚠ unknown -output `%s`, expected text or json", option)
+}
+
+// recoveryResult collects the result of a recovery for -output json, with the same chains as the text output. The
+// private keys are left out unless showSecrets. eddsaSK and eddsaPK are nil for older vaults without an EdDSA key.
+func recoveryResult(vaultID, vaultName, ethAddress string, ecdsaSK, eddsaSK, eddsaPK []byte, chains ChainSelection,
+	showSecrets, showPubKeys bool, written []string) RecoveryResult {
+	result := RecoveryResult{
+		VaultID:      vaultID,
+		VaultName:    vaultName,
+		Address:      ethAddress,
+		Chains:       chains.IDs(),
+		Addresses:    make([]ResultAddress, 0),
+		SecretsShown: showSecrets,
+		WrittenFiles: append(make([]string, 0, len(written)), written...),
+	}
+	for _, a := range chains.Addresses(vaultAddresses(ecdsaSK, eddsaPK)) {
+		result.Addresses = append(result.Addresses, ResultAddress{a.Chain, a.Address, a.Key})
+	}
+	if showPubKeys {
+		details := ecdsaPublicKeyDetails(ecdsaSK)
+		result.PublicKeys = &ResultPublicKey{details.Compressed, details.Uncompressed, details.X, details.Y}
+	}
+	if !showSecrets {
+		return result
+	}
+
+	ecdsaKey := new(ResultECDSAKey)
+	if chains.Shows("ethereum", "tron", "cosmos") {
+		ecdsaKey.PrivateKey = hex.EncodeToString(ecdsaSK)
+	}
+	if chains.Shows("bitcoin") {
+		ecdsaKey.WIFs = append(ecdsaKey.WIFs,
+			ResultWIF{"Bitcoin mainnet", wif.ToBitcoinWIF(ecdsaSK, false, true)},
+			ResultWIF{"Bitcoin testnet", wif.ToBitcoinWIF(ecdsaSK, true, true)})
+	}
+	for _, chain := range address.UTXOChains {
+		if chains.ShowsChain(chain.Name) {
+			ecdsaKey.WIFs = append(ecdsaKey.WIFs, ResultWIF{chain.Name, wif.ToWIF(ecdsaSK, chain.WIFVersion, true)})
+		}
+	}
+	if ecdsaKey.PrivateKey != "" || len(ecdsaKey.WIFs) > 0 {
+		result.ECDSA = ecdsaKey
+	}
+	if eddsaSK != nil && chains.Shows("solana", "xrpl", "cardano", "stellar", "eddsa") {
+		result.EdDSA = &ResultEdDSAKey{PrivateKey: hex.EncodeToString(eddsaSK), PublicKey: hex.EncodeToString(eddsaPK)}
+	}
+	return result
+}
+
+// writeRecoveryResult prints the result as one indented JSON document.
+func writeRecoveryResult(out io.Writer, result RecoveryResult) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return fmt.Errorf("⚠ failed to write the JSON result: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	format, err := parseOutputFormat("json")
+	assert.NoError(t, err)
+	assert.Equal(t, outputJSON, format)
+	_, err = parseOutputFormat("xml")
+	assert.ErrorContains(t, err, "unknown -output `xml`")
+}
+
+func TestRecoveryResult(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	eddsaSK, eddsaPK := make([]byte, 32), make([]byte, 32)
+	eddsaPK[0] = 1
+
+	result := recoveryResult("vault-1", "Treasury", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", ecdsaSK, eddsaSK, eddsaPK, nil, true, false, []string{"wallet.json"})
+	assert.Equal(t, "vault-1", result.VaultID)
+	assert.Empty(t, result.Chains)
+	assert.Len(t, result.Addresses, len(vaultAddresses(ecdsaSK, eddsaPK)))
+	require.NotNil(t, result.ECDSA)
+	assert.Equal(t, hex.EncodeToString(ecdsaSK), result.ECDSA.PrivateKey)
+	assert.Equal(t, ResultWIF{"Bitcoin mainnet", "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"}, result.ECDSA.WIFs[0])
+	assert.Len(t, result.ECDSA.WIFs, 5)
+	require.NotNil(t, result.EdDSA)
+	assert.Equal(t, hex.EncodeToString(eddsaPK), result.EdDSA.PublicKey)
+	assert.Nil(t, result.PublicKeys)
+	assert.Equal(t, []string{"wallet.json"}, result.WrittenFiles)
+
+	// only the selected chains, like the text output
+	selection, err := parseChains("bitcoin,xrpl")
+	require.NoError(t, err)
+	result = recoveryResult("vault-1", "Treasury", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", ecdsaSK, eddsaSK, eddsaPK, selection, true, true, nil)
+	assert.Equal(t, []string{"bitcoin", "xrpl"}, result.Chains)
+	assert.Len(t, result.Addresses, 5)
+	assert.Empty(t, result.ECDSA.PrivateKey)
+	assert.Len(t, result.ECDSA.WIFs, 2)
+	assert.NotNil(t, result.EdDSA)
+	require.NotNil(t, result.PublicKeys)
+	assert.Equal(t, ecdsaPublicKeyDetails(ecdsaSK).Compressed, result.PublicKeys.Compressed)
+
+	// no keys until they are allowed to be shown
+	result = recoveryResult("vault-1", "Treasury", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", ecdsaSK, eddsaSK, eddsaPK, nil, false, false, nil)
+	assert.False(t, result.SecretsShown)
+	assert.Nil(t, result.ECDSA)
+	assert.Nil(t, result.EdDSA)
+	assert.NotEmpty(t, result.Addresses)
+}
+
+func TestWriteRecoveryResult(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	out := new(bytes.Buffer)
+	require.NoError(t, writeRecoveryResult(out, recoveryResult("vault-1", "Treasury", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", ecdsaSK, nil, nil, nil, false, false, nil)))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &document))
+	assert.Equal(t, "vault-1", document["vaultId"])
+	assert.Equal(t, false, document["secretsShown"])
+	assert.Equal(t, []any{}, document["writtenFiles"])
+	assert.NotContains(t, document, "ecdsa")
+	assert.NotContains(t, out.String(), "\x1b")
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/keychain"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+const keychainCmd = "keychain"
+
+// runKeychain stores or deletes the phrases of backup files in the OS keychain, for unattended drills with -drill-keychain.
+func runKeychain(args []string) error {
+	fs := flag.NewFlagSet(keychainCmd, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: recovery-tool %s store|delete file1.json file2.json …\n\n"+
+			"store asks for the phrase of each file, checks it, and saves it in the OS keychain under the file's SHA-256.\n"+
+			"delete removes them again. Only use this on dedicated rehearsal machines, never with production phrases you do not rotate.\n", keychainCmd)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return errors.New("⚠ expected an action and at least one backup file")
+	}
+	action, files := fs.Arg(0), fs.Args()[1:]
+	appConfig := config.AppConfig{Filenames: files}
+	if err := ui.ValidateFiles(appConfig); err != nil {
+		return err
+	}
+
+	switch action {
+	case "store":
+		vaultsDataFiles, err := ui.NewMnemonicsForm(appConfig).WithChecker(checkMnemonics).WithMatcher(matchMnemonicCheck).Run()
+		if err != nil {
+			return err
+		}
+		for _, file := range *vaultsDataFiles {
+			fp, err := ui.FingerprintFile(file.File, nil)
+			if err != nil {
+				return err
+			}
+			if err = keychain.Store(fp.SHA256, file.Mnemonics); err != nil {
+				return err
+			}
+			fmt.Printf("Stored the phrase for %s (sha256:%s)\n", file.File, fp.SHA256)
+		}
+	case "delete":
+		for _, file := range files {
+			fp, err := ui.FingerprintFile(file, nil)
+			if err != nil {
+				return err
+			}
+			if err = keychain.Delete(fp.SHA256); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted the phrase for %s (sha256:%s)\n", file, fp.SHA256)
+		}
+	default:
+		fs.Usage()
+		return fmt.Errorf("⚠ unknown action `%s`, expected store or delete", action)
+	}
+	return nil
+}
+
+// runKeychainDrill recovers the vault with phrases read from the OS keychain, without any prompt, and only reports whether
+// it succeeded. No private key is shown and nothing is exported, so it can run unattended on a schedule.
+func runKeychainDrill(files []string, contents map[string][]byte, vaultID string, nonceOverride, quorumOverride *int) error {
+	if vaultID == "" {
+		return errors.New("⚠ -drill-keychain runs unattended, so the vault must be given with -vault-id")
+	}
+	vaultsDataFiles, err := keychainPhrases(files, contents)
+	if err != nil {
+		return err
+	}
+
+	address, ecSK, edSK, _, err := runTool(vaultsDataFiles, ToolOptions{VaultID: vaultID, NonceOverride: nonceOverride, QuorumOverride: quorumOverride})
+	clear(ecSK)
+	clear(edSK)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Drill passed: vault %s was recovered from %d backup files. Ethereum address: %s\n", vaultID, len(files), address)
+	return nil
+}
+
+// keychainPhrases are the backup files with their phrases read from the OS keychain.
+func keychainPhrases(files []string, contents map[string][]byte) ([]ui.VaultsDataFile, error) {
+	vaultsDataFiles := make([]ui.VaultsDataFile, 0, len(files))
+	for _, file := range files {
+		fp, err := ui.FingerprintFile(file, contents[file])
+		if err != nil {
+			return nil, err
+		}
+		mnemonics, err := keychain.Load(fp.SHA256)
+		if errors.Is(err, keychain.ErrNotFound) {
+			return nil, fmt.Errorf("⚠ no phrase for `%s` in the keychain, store it with `recovery-tool %s store`", file, keychainCmd)
+		}
+		if err != nil {
+			return nil, err
+		}
+		vaultsDataFiles = append(vaultsDataFiles, ui.VaultsDataFile{File: file, Mnemonics: ui.CleanMnemonicInput(mnemonics), Content: contents[file]})
+	}
+	return vaultsDataFiles, nil
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/keychain"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestKeychainDrill(t *testing.T) {
+	keyring.MockInit()
+	manifest, err := generateFixtures(fixtureOptions{OutDir: t.TempDir(), Vaults: 1, Parties: 3, Threshold: 2, V2: true})
+	require.NoError(t, err)
+	files := []string{manifest.Files[0].File, manifest.Files[1].File}
+	contents := map[string][]byte{}
+	vaultID := manifest.Vaults[0].VaultID
+
+	// phrases must be stored first
+	assert.ErrorContains(t, runKeychainDrill(files, contents, vaultID, nil, nil), "no phrase for")
+
+	for i, file := range files {
+		fp, err := ui.FingerprintFile(file, nil)
+		require.NoError(t, err)
+		require.NoError(t, keychain.Store(fp.SHA256, manifest.Files[i].Mnemonics))
+	}
+	assert.NoError(t, runKeychainDrill(files, contents, vaultID, nil, nil))
+	assert.ErrorContains(t, runKeychainDrill(files, contents, "", nil, nil), "-vault-id")
+}
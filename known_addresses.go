@@ -0,0 +1,144 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// KnownAddressCheck is a row of the -known-addresses verification matrix.
+type KnownAddressCheck struct {
+	Entry AddressBookEntry
+	// Matched is set if the known address is one of the recovered addresses, on any chain
+	Matched bool
+}
+
+// loadKnownAddresses reads the known vault addresses from the platform, as CSV with a header row or as a JSON array.
+// It takes the format of -export-addresses: only the vault and address columns are required.
+func loadKnownAddresses(file string) ([]AddressBookEntry, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to read the known addresses file `%s`: %s", file, err)
+	}
+	var entries []AddressBookEntry
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		if err = json.Unmarshal(content, &entries); err != nil {
+			return nil, fmt.Errorf("⚠ the known addresses file `%s` is not a JSON array of {\"vault\", \"address\"} objects: %s", file, err)
+		}
+	case ".csv":
+		if entries, err = parseKnownAddressesCSV(content); err != nil {
+			return nil, fmt.Errorf("⚠ invalid known addresses file `%s`: %s", file, err)
+		}
+	default:
+		return nil, fmt.Errorf("⚠ the known addresses file must be a .csv or .json file: %s", file)
+	}
+	for i, entry := range entries {
+		if strings.TrimSpace(entry.Vault) == "" || strings.TrimSpace(entry.Address) == "" {
+			return nil, fmt.Errorf("⚠ entry %d of the known addresses file `%s` needs a vault and an address", i+1, file)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("⚠ the known addresses file `%s` is empty", file)
+	}
+	return entries, nil
+}
+
+func parseKnownAddressesCSV(content []byte) ([]AddressBookEntry, error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["vault"]; !ok {
+		return nil, fmt.Errorf("the header row has no vault column")
+	}
+	if _, ok := columns["address"]; !ok {
+		return nil, fmt.Errorf("the header row has no address column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	var entries []AddressBookEntry
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, AddressBookEntry{Chain: field(record, "chain"), Address: field(record, "address"), Vault: field(record, "vault")})
+	}
+}
+
+// verifyKnownAddresses checks the known addresses of the recovered vault against its recovered addresses, in any of
+// their equivalent forms. It also returns the other vaults whose known addresses were recovered, which means that the
+// wrong vault was picked or the file mixes vaults up.
+func verifyKnownAddresses(vaultID string, known []AddressBookEntry, addresses []ChainAddress) (rows []KnownAddressCheck, otherVaults []string) {
+	others := make(map[string]bool)
+	for _, entry := range known {
+		_, matched := checkPastedAddress(entry.Address, addresses)
+		if entry.Vault == vaultID {
+			rows = append(rows, KnownAddressCheck{Entry: entry, Matched: matched})
+		} else if matched {
+			others[entry.Vault] = true
+		}
+	}
+	for vID := range others {
+		otherVaults = append(otherVaults, vID)
+	}
+	sort.Strings(otherVaults)
+	return rows, otherVaults
+}
+
+// printKnownAddresses prints the -known-addresses verification matrix and its verdict.
+func printKnownAddresses(out io.Writer, vaultID string, rows []KnownAddressCheck, otherVaults []string) {
+	fmt.Fprintf(out, "\n%s%s KNOWN ADDRESSES %s\n\n", ui.AnsiCodes["invertOn"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	mismatched := 0
+	for _, row := range rows {
+		chain := row.Entry.Chain
+		if chain == "" {
+			chain = "(chain not given)"
+		}
+		status := "✓ recovered"
+		if !row.Matched {
+			status = "✗ NOT RECOVERED"
+			mismatched++
+		}
+		fmt.Fprintf(out, "%-34s %s  %s\n", chain+":", row.Entry.Address, ui.Plain(status))
+	}
+	switch {
+	case len(rows) == 0:
+		fmt.Fprintf(out, "%s\n", ui.Plain(fmt.Sprintf("⚠ The known addresses file has no addresses of vault %s, so it could not be verified.", vaultID)))
+	case mismatched > 0:
+		fmt.Fprintf(out, "\n%s\n", ui.Plain(fmt.Sprintf("⚠ %d of %d known addresses of vault %s are not among the recovered addresses. The wrong vault may have been picked, "+
+			"or the file is out of date. Do not use these keys before this is explained.", mismatched, len(rows), vaultID)))
+	default:
+		fmt.Fprintf(out, "\n%s\n", ui.Plain(fmt.Sprintf("✓ All %d known addresses of vault %s match the recovered keys.", len(rows), vaultID)))
+	}
+	if len(otherVaults) > 0 {
+		fmt.Fprintf(out, "%s\n", ui.Plain(fmt.Sprintf("⚠ The recovered addresses are listed for other vaults in the file: %s. The wrong vault may have been picked.",
+			strings.Join(otherVaults, ", "))))
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKnownAddresses(t *testing.T) {
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "known.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("Address,Vault,Chain\n"+
+		"0x7e5f4552091a69125d5dfcb7b8c2659029395bdf,vault-1,Ethereum\n"+
+		"TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC,vault-1,Tron\n"+
+		"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq,vault-1,Bitcoin\n"+
+		"t1UYsZVJkLPeMjxEtACvSxfWuNmddpWfxzs,vault-2,\n"), 0600))
+	known, err := loadKnownAddresses(csvFile)
+	require.NoError(t, err)
+	require.Len(t, known, 4)
+	assert.Equal(t, AddressBookEntry{Chain: "Tron", Address: "TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC", Vault: "vault-1"}, known[1])
+
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	addresses := vaultAddresses(ecdsaSK, nil)
+	rows, otherVaults := verifyKnownAddresses("vault-1", known, addresses)
+	require.Len(t, rows, 3)
+	// the lower case Ethereum address is the recovered one without its checksum
+	assert.True(t, rows[0].Matched)
+	assert.True(t, rows[1].Matched)
+	assert.False(t, rows[2].Matched)
+	assert.Equal(t, []string{"vault-2"}, otherVaults)
+
+	out := new(bytes.Buffer)
+	printKnownAddresses(out, "vault-1", rows, otherVaults)
+	assert.Contains(t, out.String(), "1 of 3 known addresses of vault vault-1 are not among the recovered addresses")
+	assert.Contains(t, out.String(), "listed for other vaults in the file: vault-2")
+
+	out.Reset()
+	printKnownAddresses(out, "vault-1", rows[:2], nil)
+	assert.Contains(t, out.String(), "All 2 known addresses of vault vault-1 match")
+
+	// the address book of -export-addresses can be used as is
+	jsonFile, err := writeAddressBook(filepath.Join(dir, "book.json"), addressBook("vault-1", addresses, ecdsaSK, nil))
+	require.NoError(t, err)
+	known, err = loadKnownAddresses(jsonFile)
+	require.NoError(t, err)
+	rows, _ = verifyKnownAddresses("vault-1", known, addresses)
+	assert.Len(t, rows, len(addresses))
+	for _, row := range rows {
+		assert.True(t, row.Matched, row.Entry.Chain)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.csv"), []byte("chain,address\nTron,TMVQGm1qAQYVdetCeGRRkTWYYrLXuHK2HC\n"), 0600))
+	_, err = loadKnownAddresses(filepath.Join(dir, "bad.csv"))
+	assert.ErrorContains(t, err, "no vault column")
+	_, err = loadKnownAddresses(filepath.Join(dir, "known.txt"))
+	assert.Error(t, err)
+}
@@ -5,13 +5,30 @@
 package main
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
+	"math/rand/v2"
 	"os"
+	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/harden"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/instance"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/lock"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/observe"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/qr"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/source"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/strength"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/support"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/sweep"
 	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ur"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
 	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/wif"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
@@ -22,13 +39,102 @@ const (
 )
 
 func main() {
-	vaultID := flag.String("vault-id", "", "(Optional) The vault id to export the keys for.")
+	// harden the process before anything is read or written
+	hardeningWarnings := harden.Apply()
+
+	if len(os.Args) > 1 {
+		if runCmd, ok := subcommands[os.Args[1]]; ok {
+			ui.ConfigureOutput(false, false)
+			printWarnings(hardeningWarnings)
+			if err := runCmd(os.Args[2:]); err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	var vaultIDs VaultIDs
+	flag.Var(&vaultIDs, "vault-id", "(Optional, repeatable) The vault id to export the keys for. Repeat it to recover several vaults in a batch, written to -batch-out.")
+	allVaults := flag.Bool("all-vaults", false, "(Optional) Recover every vault of the backup files in a batch, with the phrases entered once. The keys of each vault are written to their own file in -batch-out, and not shown.")
+	batchOut := flag.String("batch-out", "", "(Optional) Directory to write the result file of each vault of a batch to, as with -output json. The files hold the private keys, unencrypted.")
 	nonceOverride := flag.Int("nonce", -1, "(Optional) Reshare Nonce override. Try it if the tool advises you to do so.")
 	quorumOverride := flag.Int("threshold", 0, "(Optional) Vault Quorum (Threshold) override. Try it if the tool advises you to do so.")
+	vaultOverrides := make(VaultOverrides)
+	flag.Var(vaultOverrides, "override", "(Optional, repeatable) Reshare nonce and threshold override of a single vault, as vaultID=nonce:threshold. Unlike -nonce and -threshold, it only applies when that vault is recovered.")
 	passwordForKS := flag.String("password", "", "(Optional) Encryption password for the Ethereum wallet v3 file; use with -export")
-	exportKSFile := flag.String("export", "wallet.json", "(Optional) Filename to export a Ethereum wallet v3 JSON to; use with -password.")
+	exportKSFile := flag.String("export", "wallet.json", "(Optional) Filename to export a Ethereum wallet v3 JSON to, tagged with the session ID; use with -password.")
+	strictWritesOption := flag.Bool("strict-writes", false, "(Optional) Refuse to write any file that is not named on the command line: -export must be given to export a wallet v3 file, and -bundle, which writes under a generated name, is refused.")
+	allowSyncedPath := flag.Bool("allow-synced-path", false, "(Optional) Allow writing outputs into a cloud synced folder (Dropbox, OneDrive, iCloud Drive or Google Drive), which is refused by default as they are uploaded as soon as they are written.")
+	confirmWord := flag.Bool("confirm-word", false, "(Optional) Before the private keys are shown, also ask for the first word of the phrase of a backup file picked at random, besides typing \"show secrets\".")
+	fourEyes := flag.Bool("four-eyes", false, "(Optional) Dual-operator mode: two operators each set a passphrase at the start, and both must enter it again before any private key is shown or written.")
+	exportPEM := flag.String("export-pem", "", "(Optional) After recovery, also write the ECDSA private key as an unencrypted PKCS#8 PEM file with this name, and as a SEC1 (OpenSSL EC key) PEM file next to it, for OpenSSL based tooling and HSM import utilities.")
+	exportCardanoKey := flag.String("export-cardano-key", "", "(Optional) After recovery, also write the EdDSA private key as an unencrypted cardano-cli signing key file with this name, e.g. payment.skey, to sign transactions of the vault's Cardano address.")
+	policyFile := flag.String("policy", "", "(Optional) A text file with the organization's key handling policy. It is shown and must be accepted before any private key is shown.")
+	notesFile := flag.String("notes", "", "(Optional) A JSON file of operator notes for vaults, e.g. {\"<vault id>\": \"Treasury hot vault\"}, shown in the vault picker.")
+	plan := flag.Bool("plan", false, "(Optional) Only show which shares of the vault you hold and how many more backup files are needed to recover it.")
+	simulate := flag.Bool("simulate", false, "(Optional) With -plan, also show with made-up numbers how the shares you hold combine into the vault key, with its real quorum and parties, and why too few shares or mixed reshares fail.")
+	showPubKeys := flag.Bool("show-pubkeys", false, "(Optional) Also show the recovered public keys in compressed, uncompressed and X/Y form, with the steps to the Ethereum address.")
+	showUR := flag.Bool("show-ur", false, "(Optional) Show the recovered ECDSA private key as a crypto-eckey UR QR code, for import into QR based wallets.")
+	addressesOnly := flag.Bool("addresses-only", false, "(Optional) Only show the addresses of the recovered vault on each supported chain. No private keys are shown and nothing is written to disk.")
+	checkAddress := flag.String("check-address", "", "(Optional) An address from a wallet or explorer to check against the recovered keys, e.g. one that looks different in another wallet. Explains checksum, case and Tron encoding differences.")
+	exportAddresses := flag.String("export-addresses", "", "(Optional) After recovery, write an address book of the vault's addresses on each supported chain, with their public keys and no secrets, to this .csv or .json file, for monitoring systems and watch lists.")
+	chainsOption := flag.String("chains", "", "(Optional) Only show the addresses and keys of these chains, e.g. ethereum,bitcoin, or all: "+strings.Join(chainIDs(), ", ")+". Defaults to the chains saved with -save-chains, or all.")
+	saveChains := flag.Bool("save-chains", false, "(Optional) Remember the -chains selection as the default of future runs, in a settings file of the user config directory. -chains all forgets it.")
+	exportWatchOnly := flag.String("export-watch-only", "", "(Optional) After recovery, write Electrum watch-only wallet files of the vault's Bitcoin mainnet and testnet addresses, and its wpkh output descriptor for descriptor wallets, named after this file. They hold no secrets.")
+	exportSummary := flag.String("export-summary", "", "(Optional) After recovery, write a summary of what was recovered, when, by whom and the next steps, without any secrets, to this .md or .html file, to carry off the machine for the incident records.")
+	postHook := flag.String("post-hook", "", "(Optional) A local command to run after a successful recovery, e.g. to encrypt and archive the written files. It gets the session ID, vault ID and written file paths in RECOVERY_* environment variables, and never secrets unless -pass-secrets-fd is set.")
+	passSecretsFD := flag.Bool("pass-secrets-fd", false, "(Optional) Also hand the recovered private keys to the -post-hook command, as JSON on file descriptor 3 (RECOVERY_SECRETS_FD). Not supported on Windows.")
+	knownAddressesFile := flag.String("known-addresses", "", "(Optional) A CSV or JSON file of the known addresses of vaults, e.g. from the platform or -export-addresses, with vault and address columns. The recovered addresses are verified against it, to catch a wrong vault.")
+	bip85Option := flag.String("bip85", "", "(Optional) After recovery, also show the BIP85 child seeds at these indexes, e.g. 0,1,5, derived from the vault ECDSA key, to provision new wallets. They can be derived again from the vault only with this tool, as its BIP85 root is not standard.")
+	bip85Words := flag.Int("bip85-words", 24, "(Optional) Number of words of the -bip85 child seeds: 12, 18 or 24.")
+	rotate := flag.Bool("rotate", false, "(Optional) After recovery, generate a brand-new key and show the addresses to sweep funds from and to.")
+	sweepParamsFile := flag.String("sweep-params", "", "(Optional) A JSON file of the balance, nonce and fees of the recovered Ethereum address and the unspent outputs of its Bitcoin address, to build unsigned sweep transactions to the new key; use with -rotate.")
+	ksKDFOption := flag.String("ks-kdf", "standard", "(Optional) Key derivation strength of the Ethereum wallet v3 file: light, standard, paranoid, or explicit scrypt parameters like n=262144,r=8,p=1; use with -export.")
+	exportLabel := flag.String("label", "", "(Optional) A label identifying this recovery session, e.g. a ticket number, to embed in the metadata of exported files for leak tracing. Key data is never altered.")
+	commitmentsFile := flag.String("commitments", "", "(Optional) A JSON file of the public VSS share commitments of vaults, exported at keygen. Each share is checked against them before recovery, to pinpoint corrupted or swapped shares.")
+	force := flag.Bool("force", false, "(Optional) Show the recovered keys even if they do not match the public keys recorded in the vault metadata. Only use this if advised to.")
+	bundleOutputs := flag.Bool("bundle", false, "(Optional) After recovery, move the files written in this session (wallet v3 file, TSS share bundles) into a single ZIP named with the vault and session ID, and print its SHA-256.")
+	bip38Password := flag.String("bip38-password", "", "(Optional) Also show the Bitcoin mainnet key encrypted with this passphrase as a BIP38 key (6P…), which cannot be spent without the passphrase, e.g. for a printed backup.")
+	bundlePassword := flag.String("bundle-password", "", "(Optional) Encrypt the -bundle ZIP with this password. Decrypt it with \"recovery-tool unbundle\".")
+	exportTSSShareDir := flag.String("export-tss-share", "", "(Optional) Directory to export per-party TSS share bundles (Paillier, NTilde, H1/H2) to, for re-import into a signing cluster.")
+
+	qrInput := flag.Bool("qr", false, "(Optional) The input files are directories of QR code images (PNG/JPEG) of the backup files, one directory per file.")
+	remote := flag.Bool("remote", false, "(Optional) Online mode: also accept pre-signed https:// URLs of backup objects in S3, GCS or Azure as inputs. They are downloaded into memory only; outputs are always written to local disk.")
+	readOnlySource := flag.Bool("readonly-source", false, "(Optional) Refuse to run unless the input files and their directories are read-only to this tool, e.g. on a read-only mounted network share.")
+	maxMemory := flag.Int("max-memory", 0, "(Optional) Memory ceiling in MiB, for small recovery hardware. The Go runtime collects garbage harder near it, and if the recovery is estimated to need more, vaults are listed in a slower low memory mode.")
+	verbose := flag.Bool("verbose", false, "(Optional) Also print the compressed and inflated size of every V2 share, instead of one summary per vault.")
+	quiet := flag.Bool("quiet", false, "(Optional) Only print results and errors: no banner, warnings or progress messages.")
+	lockAfter := flag.Int("lock-after", 0, "(Optional) Keep the recovered keys on screen until Enter is pressed, and lock the screen after this many idle minutes. A session passphrase is set at the start to unlock it.")
+	drillKeychain := flag.Bool("drill-keychain", false, "(Optional) Unattended drill: read the phrases from the OS keychain (stored with \"recovery-tool keychain store\"), recover the -vault-id vault and only report whether it succeeded. No keys are shown or exported.")
+	yes := flag.Bool("yes", false, "(Optional) Run unattended and answer yes to every confirmation: recover the vault, accept the -policy and show the keys. The vault comes from -vault-id and the phrases from the -answers file; a prompt without an answer fails the run.")
+	answersFile := flag.String("answers", "", "(Optional) A YAML file pre-answering the prompts, to run unattended in rehearsal pipelines: vault-id, phrases (or phrases-from-keychain), confirm-recovery, accept-policy and show-secrets. A prompt without an answer fails the run.")
+	var mnemonicFiles MnemonicFiles
+	flag.Var(&mnemonicFiles, "mnemonic-file", "(Optional, repeatable) Read the phrase of a backup file from a text file, as backup=phrasefile, to run unattended with -yes or -answers. A single value without = is a YAML manifest mapping each backup file to its phrase file.")
+	cosmosHRPOption := flag.String("cosmos-hrp", defaultCosmosHRP, "(Optional) The bech32 prefixes of the Cosmos SDK chains to show the vault's addresses on, e.g. cosmos,osmo,celestia. The ECDSA private key imports into Keplr on each of them.")
+	redactScreen := flag.Bool("redact", false, "(Optional) Mask all but the first and last 4 characters of every key and address shown on screen, e.g. to guide a recovery over a screen share. Written files still hold them in full.")
+	output := flag.String("output", outputText, "(Optional) Output format of the result: text, or json to print the addresses, keys and written files as a single JSON document on stdout. Prompts, progress and errors then go to stderr, without colors.")
+	noColor := flag.Bool("no-color", false, "(Optional) Disable colors and decorative symbols in the output. Also set by the NO_COLOR or CLICOLOR=0 environment variables.")
 
 	flag.Parse()
+	outputFormat, err := parseOutputFormat(*output)
+	if err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	// the JSON result is alone on stdout, so everything else, the prompts included, goes to stderr
+	resultOut := os.Stdout
+	if outputFormat == outputJSON {
+		os.Stdout = os.Stderr
+	}
+	vaultID := new(string)
+	if len(vaultIDs) == 1 {
+		*vaultID = vaultIDs[0]
+	}
+	batch := *allVaults || len(vaultIDs) > 1
+	caps := ui.DetectCapabilities(os.Stdin, os.Stdout)
+	verboseOutput = *verbose
+	ui.ConfigureOutput(*quiet, *noColor || caps.PlainText() || outputFormat == outputJSON)
 	files := flag.Args()
 	if len(files) < 1 {
 		fmt.Println("Please supply some input files on the command line. \nExample: recovery-tool.exe [-flags] file1.json file2.json … \n\nOptional flags:")
@@ -36,71 +142,610 @@ func main() {
 		return
 	}
 
-	fmt.Print(ui.Banner())
+	ui.Printf("%s", ui.Banner())
+	printWarnings(hardeningWarnings)
 
+	// exported files are tagged with the session ID, to trace them back to this run
+	if *exportKSFile != "" {
+		*exportKSFile = ui.SessionFilename(*exportKSFile)
+	}
 	appConfig := config.AppConfig{
-		Filenames:      files,
-		NonceOverride:  *nonceOverride,
-		QuorumOverride: *quorumOverride,
-		ExportKSFile:   *exportKSFile,
-		PasswordForKS:  *passwordForKS,
+		Filenames:         files,
+		NonceOverride:     *nonceOverride,
+		QuorumOverride:    *quorumOverride,
+		ExportKSFile:      *exportKSFile,
+		PasswordForKS:     *passwordForKS,
+		ExportTSSShareDir: *exportTSSShareDir,
+		ExportPEMFile:     *exportPEM,
+		ExportCardanoKey:  *exportCardanoKey,
+		QRInput:           *qrInput,
+		ReadOnlySource:    *readOnlySource,
+		Bundle:            *bundleOutputs,
+		BundlePassword:    *bundlePassword,
+		BIP38Password:     *bip38Password,
+
+		ExportAddressesFile: *exportAddresses,
+		ExportWatchOnlyFile: *exportWatchOnly,
+		ExportSummaryFile:   *exportSummary,
+		PostHook:            *postHook,
+		PassSecretsFD:       *passSecretsFD,
+	}
+
+	if err := validateExportLabel(*exportLabel); err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	hrps, err := parseCosmosHRPs(*cosmosHRPOption)
+	if err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	cosmosHRPs = hrps
+	if *exportAddresses != "" {
+		if err := validateAddressBookFile(*exportAddresses); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+	if *exportSummary != "" {
+		if err := validateSummaryFile(*exportSummary); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+	if len(vaultOverrides) > 0 && (*nonceOverride > -1 || *quorumOverride > 0) {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -override cannot be combined with -nonce or -threshold: give the overrides of every vault with -override")))
+		os.Exit(1)
+	}
+	if *simulate && !*plan {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -simulate explains the -plan of a vault, so use it with -plan")))
+		os.Exit(1)
+	}
+	if *maxMemory < 0 {
+		fmt.Print(ui.ErrorBox(fmt.Errorf("⚠ -max-memory is a number of MiB, not %d", *maxMemory)))
+		os.Exit(1)
+	}
+	if *fourEyes && *drillKeychain {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -four-eyes needs two operators at the terminal, so it cannot be combined with the unattended -drill-keychain")))
+		os.Exit(1)
+	}
+	if len(mnemonicFiles) > 0 && *drillKeychain {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -drill-keychain reads the phrases from the OS keychain, so it cannot be combined with -mnemonic-file")))
+		os.Exit(1)
+	}
+	if *passSecretsFD && (*postHook == "" || *addressesOnly || !postHookSecretsSupported) {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -pass-secrets-fd needs a -post-hook, cannot be combined with -addresses-only, and is not supported on Windows")))
+		os.Exit(1)
+	}
+	// the outputs named on the command line, checked before any phrase is entered
+	var outputs, outputDirs []string
+	if *passwordForKS != "" {
+		outputs = append(outputs, *exportKSFile)
+	}
+	if *exportPEM != "" {
+		pkcs8File, sec1File := pemFilenames(*exportPEM)
+		outputs = append(outputs, pkcs8File, sec1File)
+	}
+	if *exportCardanoKey != "" {
+		outputs = append(outputs, ui.SessionFilename(*exportCardanoKey))
+	}
+	if *exportAddresses != "" {
+		outputs = append(outputs, ui.SessionFilename(*exportAddresses))
+	}
+	if *exportWatchOnly != "" {
+		mainnetFile, testnetFile, descriptorFile := watchOnlyFilenames(*exportWatchOnly)
+		outputs = append(outputs, mainnetFile, testnetFile, descriptorFile)
+	}
+	if *exportSummary != "" {
+		outputs = append(outputs, ui.SessionFilename(*exportSummary))
+	}
+	if *exportTSSShareDir != "" {
+		outputDirs = append(outputDirs, *exportTSSShareDir)
+	}
+	if *batchOut != "" {
+		outputDirs = append(outputDirs, *batchOut)
+	}
+	if *strictWritesOption {
+		named := make(map[string]bool)
+		flag.Visit(func(f *flag.Flag) { named[f.Name] = true })
+		if *passwordForKS != "" && !named["export"] {
+			fmt.Print(ui.ErrorBox(errors.New("⚠ -strict-writes: name the wallet v3 file with -export, instead of the default wallet.json")))
+			os.Exit(1)
+		}
+		if *bundleOutputs {
+			fmt.Print(ui.ErrorBox(errors.New("⚠ -strict-writes: -bundle writes to the current directory under a generated name, so it cannot be used")))
+			os.Exit(1)
+		}
+		strictWrites = newWritePolicy(outputs, outputDirs)
+		ui.Printf("Strict writes: only these outputs may be written: %s.\n\n", strictOutputsList(outputs, outputDirs))
+	}
+	if *saveChains && *strictWritesOption {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -strict-writes: -save-chains writes the settings file, which is not an output, so it cannot be used")))
+		os.Exit(1)
+	}
+	settingsFile, err := settingsPath()
+	if err != nil {
+		settingsFile = ""
+	}
+	chains, chainsSource, err := chainSelection(*chainsOption, *saveChains, settingsFile)
+	if err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	if *saveChains {
+		fmt.Printf("Saved the chain selection in %s.\n\n", settingsFile)
+	}
+	bip85Indexes, err := parseBIP85Indexes(*bip85Option)
+	if err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	if len(bip85Indexes) > 0 && *bip85Words != 12 && *bip85Words != 18 && *bip85Words != 24 {
+		fmt.Print(ui.ErrorBox(fmt.Errorf("⚠ -bip85-words must be 12, 18 or 24, not %d", *bip85Words)))
+		os.Exit(1)
+	}
+	if outputFormat == outputJSON && (*showUR || *rotate || *lockAfter > 0 || *checkAddress != "" || *knownAddressesFile != "") {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -output json holds the addresses, keys, BIP85 seeds and written files, so it cannot be combined with -show-ur, -rotate, -lock-after, -check-address or -known-addresses")))
+		os.Exit(1)
+	}
+	if *redactScreen && (*showUR || len(bip85Indexes) > 0 || outputFormat == outputJSON) {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -redact masks the keys and addresses on screen, so it cannot be combined with -show-ur or -bip85, whose QR codes and phrases cannot be masked, or with -output json")))
+		os.Exit(1)
+	}
+	if batch {
+		named := make(map[string]bool)
+		flag.Visit(func(f *flag.Flag) { named[f.Name] = true })
+		switch conflicts := batchConflicts(named); {
+		case *allVaults && len(vaultIDs) > 0:
+			err = errors.New("⚠ -all-vaults recovers every vault, so leave out -vault-id, or repeat -vault-id without -all-vaults")
+		case *batchOut == "":
+			err = errors.New("⚠ a batch writes the keys of each vault to its own file, so give the directory to write them to with -batch-out")
+		case len(conflicts) > 0:
+			err = fmt.Errorf("⚠ a batch writes the keys of each vault to its own file, so it cannot be combined with %s", strings.Join(conflicts, ", "))
+		}
+		if err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	} else if *batchOut != "" {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -batch-out is only used in a batch: repeat -vault-id, or use -all-vaults")))
+		os.Exit(1)
+	}
+	if *addressesOnly && (*passwordForKS != "" || *exportTSSShareDir != "" || *exportPEM != "" || *exportCardanoKey != "" || *bundleOutputs || *showUR || *rotate || len(bip85Indexes) > 0 || *bip38Password != "") {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -addresses-only shows no private keys and writes nothing, so it cannot be combined with -password, -export-tss-share, -export-pem, -export-cardano-key, -bundle, -show-ur, -rotate, -bip85 or -bip38-password")))
+		os.Exit(1)
+	}
+	if *bip38Password != "" && !chains.Shows("bitcoin") {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -bip38-password encrypts the Bitcoin key, so add bitcoin to -chains")))
+		os.Exit(1)
+	}
+	if *exportCardanoKey != "" && !chains.Shows("cardano") {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -export-cardano-key writes the Cardano key, so add cardano to -chains")))
+		os.Exit(1)
+	}
+	// the exported files can be attacked offline, so weak passwords are refused before any phrase is entered
+	// in flag order, so that the same weak password is reported on every run
+	for _, flagPassword := range []struct{ name, password string }{
+		{"-password", *passwordForKS}, {"-bundle-password", *bundlePassword}, {"-bip38-password", *bip38Password},
+	} {
+		if flagPassword.password == "" {
+			continue
+		}
+		if err := strength.Check(flagPassword.name, flagPassword.password); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+	if *bundlePassword != "" && !*bundleOutputs {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -bundle-password is only used with -bundle")))
+		os.Exit(1)
+	}
+	if *bundleOutputs && *passwordForKS == "" && *exportTSSShareDir == "" && *exportPEM == "" && *exportCardanoKey == "" {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ -bundle needs files to bundle: use it with -password, -export-tss-share, -export-pem or -export-cardano-key")))
+		os.Exit(1)
+	}
+	if source.IsRemote(*exportKSFile) || source.IsRemote(*exportTSSShareDir) || source.IsRemote(*batchOut) {
+		fmt.Print(ui.ErrorBox(errors.New("⚠ outputs are only written to local disk, not to URLs")))
+		os.Exit(1)
+	}
+	syncedOutputs := append(append([]string{}, outputs...), outputDirs...)
+	if *bundleOutputs {
+		syncedOutputs = append(syncedOutputs, ".")
+	}
+	syncedWarnings, err := checkSyncedOutputs(syncedOutputs, *allowSyncedPath)
+	if err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	printWarnings(syncedWarnings)
+	// runs writing to the same outputs at the same time corrupt them, so each output is locked until the run exits
+	var lockedOutputs []string
+	if *passwordForKS != "" {
+		lockedOutputs = append(lockedOutputs, *exportKSFile)
+	}
+	for _, output := range []string{*exportPEM, *exportCardanoKey, *exportAddresses, *exportWatchOnly, *exportSummary, *exportTSSShareDir, *batchOut} {
+		if output != "" {
+			lockedOutputs = append(lockedOutputs, output)
+		}
+	}
+	outputLocks, err := instance.AcquireAll(instance.Dir(settingsDirName), lockedOutputs)
+	if err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	defer instance.ReleaseAll(outputLocks)
+	ksKDF, err := walletv3.ParseKDF(*ksKDFOption)
+	if err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+
+	var commitments VaultCommitments
+	if *commitmentsFile != "" {
+		if commitments, err = loadCommitments(*commitmentsFile); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+
+	var policy *HandlingPolicy
+	if *policyFile != "" {
+		if policy, err = loadHandlingPolicy(*policyFile); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+
+	var knownAddresses []AddressBookEntry
+	if *knownAddressesFile != "" {
+		if knownAddresses, err = loadKnownAddresses(*knownAddressesFile); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+
+	var vaultNotes map[string]string
+	if *notesFile != "" {
+		if vaultNotes, err = loadVaultNotes(*notesFile); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+
+	// Backups that were not read from a local file directly: downloaded with -remote, or decoded from QR code images
+	contents := make(map[string][]byte, len(appConfig.Filenames))
+	localConfig := appConfig
+	if appConfig.Filenames, localConfig.Filenames, err = fetchRemoteInputs(files, *remote, *qrInput, contents); err != nil {
+		fmt.Print(ui.ErrorBox(err))
+		os.Exit(1)
 	}
 
 	// First validate that files exist and are readable
-	if err := ui.ValidateFiles(appConfig); err != nil {
+	if err := ui.ValidateFiles(localConfig); err != nil {
 		fmt.Print(ui.ErrorBox(err))
 		os.Exit(1)
 	}
+	if len(contents) > 0 {
+		if err := checkDuplicateInputs(appConfig.Filenames, contents); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
 
-	/**
-	 * Run the steps to get the menmonics
-	 */
-	// var vaultsDataFiles []VaultsDataFile = make([]VaultsDataFile, 0, len(appConfig.Filenames))
-	f := ui.NewMnemonicsForm(appConfig)
-	vaultsDataFiles, err := f.Run()
+	// Decode QR code backups up front, so that unreadable images are caught before any phrase is entered
+	if appConfig.QRInput {
+		for _, dir := range appConfig.Filenames {
+			content, err := qr.ReadBackup(dir)
+			if err != nil {
+				fmt.Print(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+			contents[dir] = content
+		}
+		if err := ui.CheckDuplicateContent(appConfig.Filenames, contents); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+
+	// Show the fingerprint of each backup, to check against the asset inventory before any phrase is entered
+	ui.Printf("Input files:\n")
+	for _, file := range appConfig.Filenames {
+		fp, err := ui.FingerprintFile(file, contents[file])
+		if err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		ui.Printf("  %s\n", fp)
+	}
+	ui.Printf("\n")
+
+	// Estimate the work before it starts, so that a long recovery is not mistaken for a hang and stopped midway
+	var exportKDF *walletv3.KDFParams
+	if *passwordForKS != "" {
+		exportKDF = &ksKDF
+	}
+	preflight, err := estimatePreflight(appConfig.Filenames, contents, exportKDF)
 	if err != nil {
-		// if err := f.Run(&vaultsDataFiles); err != nil {
-		fmt.Println(ui.ErrorBox(err))
+		fmt.Print(ui.ErrorBox(err))
 		os.Exit(1)
 	}
-	if vaultsDataFiles == nil {
-		fmt.Println("No vaults data files were selected.")
-		os.Exit(0)
+	ui.Printf("%s\n\n", preflight)
+	printWarnings(preflight.Warnings(availableMemory()))
+	if *maxMemory > 0 {
+		ceiling := uint64(*maxMemory) << 20
+		debug.SetMemoryLimit(int64(ceiling))
+		var notes []string
+		if lowMemory, notes, err = preflight.MemoryPlan(ceiling); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		printWarnings(notes)
 	}
 
-	/**
-	 * Retrieve vaults information and select a vault
-	 */
-	_, _, _, vaultsFormInfo, err := runTool(*vaultsDataFiles, nil, nonceOverride, quorumOverride, exportKSFile, passwordForKS)
-	if err != nil {
-		fmt.Printf("Failed to run tool to retrieve vault information: %s\n", err)
+	if *drillKeychain {
+		drillNonce, drillQuorum := vaultOverrides.For(*vaultID, nonceOverride, quorumOverride)
+		if err := runKeychainDrill(appConfig.Filenames, contents, *vaultID, drillNonce, drillQuorum); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	var answers *Answers
+	if *answersFile != "" {
+		if answers, err = loadAnswers(*answersFile); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	} else if *yes || len(mnemonicFiles) > 0 {
+		answers = new(Answers)
+	}
+	if len(mnemonicFiles) > 0 {
+		if len(answers.Phrases) > 0 || answers.PhrasesFromKeychain {
+			fmt.Print(ui.ErrorBox(errors.New("⚠ the -answers file already sets the phrases, so leave them out of it or drop -mnemonic-file")))
+			os.Exit(1)
+		}
+		if answers.Phrases, err = mnemonicFiles.Phrases(appConfig.Filenames); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+	if answers != nil {
+		if *yes {
+			answers.withYes()
+		}
+		if err = answers.Check(UnattendedRun{VaultID: *vaultID, LockAfter: *lockAfter > 0, FourEyes: *fourEyes, ConfirmWord: *confirmWord,
+			Policy: policy != nil, Plan: *plan, AddressesOnly: *addressesOnly, Batch: batch}); err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		if *vaultID == "" {
+			*vaultID = answers.VaultID
+		}
+	} else if err := caps.RequireInteractive(); err != nil {
+		fmt.Print(ui.ErrorBox(err))
 		os.Exit(1)
 	}
 
-	var selectedVaultId string
-	// If the vault ID is not provided, run the vault picker form
-	if *vaultID == "" {
-		selectedVaultId, err = ui.RunVaultPickerForm(vaultsFormInfo)
+	// Set the session passphrase before any secret is entered or shown
+	var sessionPassphrase []byte
+	if *lockAfter > 0 {
+		var err error
+		if sessionPassphrase, err = ui.RunSessionPassphraseForm(); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		defer clear(sessionPassphrase)
+	}
+	var operators []Operator
+	if *fourEyes {
+		var first, second Operator
+		var err error
+		if first.Name, first.Passphrase, err = ui.RunOperatorForm(1); err == nil {
+			second.Name, second.Passphrase, err = ui.RunOperatorForm(2)
+		}
+		if err == nil {
+			operators, err = newOperators(first, second)
+		}
+		defer clear(first.Passphrase)
+		defer clear(second.Passphrase)
 		if err != nil {
-			fmt.Printf("Failed to run form: %s\n", err)
+			fmt.Println(ui.ErrorBox(err))
 			os.Exit(1)
 		}
-	} else {
-		// Use the vault ID provided by CLI argument
-		selectedVaultId = *vaultID
 	}
 
-	var selectedVault ui.VaultPickerItem
-	// Get the selected vault from the vaults form data
-	for _, vault := range vaultsFormInfo {
-		if vault.VaultID == selectedVaultId {
-			selectedVault = vault
-			break
+	var sweepParams *sweep.Params
+	if *sweepParamsFile != "" {
+		if !*rotate {
+			fmt.Print(ui.ErrorBox(errors.New("⚠ -sweep-params builds the sweep transactions to the new key of -rotate, so it needs -rotate")))
+			os.Exit(1)
 		}
+		params, err := loadSweepParams(*sweepParamsFile)
+		if err != nil {
+			fmt.Print(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		sweepParams = params
 	}
-	if selectedVault.VaultID == "" {
-		fmt.Println(ui.ErrorBox(fmt.Errorf("vault with ID %s not found", selectedVaultId)))
-		os.Exit(1)
+
+	/**
+	 * Run the steps to get the menmonics and select a vault.
+	 * The confirmation screen can go back to the vault picker or to the phrases.
+	 */
+	f := ui.NewMnemonicsForm(appConfig).WithChecker(func(file ui.VaultsDataFile) (int, error) {
+		file.Content = contents[file.File]
+		return checkMnemonics(file)
+	}).WithMatcher(func(file ui.VaultsDataFile) bool {
+		file.Content = contents[file.File]
+		return matchMnemonicCheck(file)
+	})
+	var (
+		vaultsDataFiles *[]ui.VaultsDataFile
+		selectedVault   ui.VaultPickerItem
+		batchList       []ui.VaultPickerItem
+	)
+	selectedVaultId := *vaultID
+wizard:
+	for {
+		if answers != nil {
+			var answered []ui.VaultsDataFile
+			answered, err = answers.VaultsDataFiles(appConfig.Filenames, contents)
+			vaultsDataFiles = &answered
+		} else if vaultsDataFiles == nil {
+			vaultsDataFiles, err = f.Run()
+		} else {
+			vaultsDataFiles, err = f.Review(*vaultsDataFiles)
+		}
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		if vaultsDataFiles == nil {
+			fmt.Println("No vaults data files were selected.")
+			os.Exit(0)
+		}
+		for i, file := range *vaultsDataFiles {
+			(*vaultsDataFiles)[i].Content = contents[file.File]
+		}
+
+		/**
+		 * Retrieve vaults information and select a vault
+		 */
+		_, _, _, vaultsFormInfo, err := runTool(*vaultsDataFiles, ToolOptions{NonceOverride: nonceOverride, QuorumOverride: quorumOverride})
+		if err != nil {
+			fmt.Printf("Failed to run tool to retrieve vault information: %s\n", ui.Plain(err.Error()))
+			os.Exit(1)
+		}
+		annotateVaults(vaultsFormInfo, vaultNotes)
+		if err = vaultOverrides.Check(vaultsFormInfo); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+
+		if batch {
+			if batchList, err = batchVaults(vaultsFormInfo, vaultIDs, *allVaults); err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+			var choice ui.ConfirmChoice
+			if answers != nil {
+				choice = answers.ConfirmChoice()
+			} else if choice, err = ui.RunConfirmBatchForm(batchList, batchPlannedOutputs(*batchOut, *addressesOnly, *showPubKeys, chains)); err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+			switch choice {
+			case ui.ConfirmRecover:
+				break wizard
+			case ui.ConfirmReenterPhrases:
+				continue wizard
+			default:
+				fmt.Println("Recovery cancelled. Nothing was written.")
+				return
+			}
+		}
+
+		for {
+			// If the vault ID is not provided, run the vault picker form
+			if selectedVaultId == "" {
+				selectedVaultId, err = ui.RunVaultPickerForm(vaultsFormInfo)
+				if err != nil {
+					fmt.Printf("Failed to run form: %s\n", err)
+					os.Exit(1)
+				}
+			}
+
+			selectedVault = ui.VaultPickerItem{}
+			// Get the selected vault from the vaults form data
+			for _, vault := range vaultsFormInfo {
+				if vault.VaultID == selectedVaultId {
+					selectedVault = vault
+					break
+				}
+			}
+			if selectedVault.VaultID == "" {
+				fmt.Println(ui.ErrorBox(fmt.Errorf("vault with ID %s not found", selectedVaultId)))
+				os.Exit(1)
+			}
+
+			if *plan {
+				recoveryPlan := planVaultRecovery(selectedVault)
+				printRecoveryPlan(recoveryPlan)
+				if *simulate {
+					printQuorumSimulation(os.Stdout, simulateQuorum(recoveryPlan, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))))
+				}
+				return
+			}
+
+			var choice ui.ConfirmChoice
+			if answers != nil {
+				choice = answers.ConfirmChoice()
+			} else if choice, err = ui.RunConfirmRecoveryForm(selectedVault, plannedOutputs(appConfig, ksKDF, *addressesOnly, *showUR, *showPubKeys, *rotate, bip85Indexes, chains)); err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+			switch choice {
+			case ui.ConfirmRecover:
+				break wizard
+			case ui.ConfirmPickVault:
+				selectedVaultId = ""
+			case ui.ConfirmReenterPhrases:
+				selectedVaultId = ""
+				continue wizard
+			default:
+				fmt.Println("Recovery cancelled. Nothing was written.")
+				return
+			}
+		}
+	}
+
+	// no private key is shown with -addresses-only, so the policy does not apply
+	if policy != nil && !*addressesOnly {
+		var accepted bool
+		if answers != nil {
+			accepted = *answers.AcceptPolicy
+		} else if accepted, err = ui.RunPolicyAcknowledgementForm(policy.Text); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		if !accepted {
+			fmt.Println("Recovery cancelled: the key handling policy was not accepted. Nothing was written.")
+			return
+		}
+		fmt.Println(policyAcknowledgement(policy, time.Now()))
+	}
+
+	// both operators confirm right before the keys are recovered, so that neither can see them alone
+	if operators != nil && !*addressesOnly {
+		entered := make([][]byte, 0, len(operators))
+		for _, operator := range operators {
+			passphrase, err := ui.RunOperatorConfirmForm(operator.Name)
+			if err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+			entered = append(entered, passphrase)
+		}
+		err := verifyOperators(operators, entered)
+		for _, passphrase := range entered {
+			clear(passphrase)
+		}
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		fmt.Println(fourEyesAcknowledgement(operators, time.Now()))
+	}
+
+	if batch {
+		outcomes := recoverBatch(*vaultsDataFiles, batchList, *batchOut, vaultOverrides, nonceOverride, quorumOverride, *force, commitments,
+			chains, *addressesOnly, *showPubKeys)
+		if printBatchOutcomes(os.Stdout, outcomes) > 0 {
+			os.Exit(1)
+		}
+		return
 	}
 
 	/**
@@ -110,7 +755,19 @@ func main() {
 		lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("RECOVERING VAULT \"%s\" WITH ID %s\n", selectedVault.Name, selectedVault.VaultID)),
 	)
 
-	address, ecSK, edSK, _, err := runTool(*vaultsDataFiles, &selectedVault.VaultID, nonceOverride, quorumOverride, exportKSFile, passwordForKS)
+	vaultNonce, vaultQuorum := vaultOverrides.For(selectedVault.VaultID, nonceOverride, quorumOverride)
+	address, ecSK, edSK, _, err := runTool(*vaultsDataFiles, ToolOptions{
+		VaultID:           selectedVault.VaultID,
+		NonceOverride:     vaultNonce,
+		QuorumOverride:    vaultQuorum,
+		ExportKSFile:      *exportKSFile,
+		PasswordForKS:     *passwordForKS,
+		ExportTSSShareDir: *exportTSSShareDir,
+		ExportLabel:       *exportLabel,
+		KSKDF:             ksKDF,
+		Force:             *force,
+		Commitments:       commitments,
+	})
 	if err != nil {
 		fmt.Println(ui.ErrorBox(err))
 		os.Exit(1)
@@ -126,38 +783,406 @@ func main() {
 		return
 	}
 
-	fmt.Printf("%s%s                %s\n", ui.AnsiCodes["darkGreenBG"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
-	fmt.Printf("%s%s    Success!    %s\n", ui.AnsiCodes["darkGreenBG"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
-	fmt.Printf("%s%s                %s\n", ui.AnsiCodes["darkGreenBG"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
-
-	fmt.Printf("\nYour vault has been recovered. Make sure this address matches your vault's Ethereum address.\n")
-	fmt.Printf("%s%s%s\n", ui.AnsiCodes["bold"], address, ui.AnsiCodes["reset"])
-
-	fmt.Printf("\nHere is your private key for Ethereum and Tron assets. Keep safe and do not share.\n")
-	fmt.Printf("Recovered ECDSA private key (for ETH/MetaMask, Tron/TronLink): %s%s%s\n",
-		ui.AnsiCodes["bold"], hex.EncodeToString(ecSK), ui.AnsiCodes["reset"])
-
-	fmt.Printf("\nHere are your private keys for Bitcoin assets. Keep safe and do not share.\n")
-	fmt.Printf("Recovered testnet WIF (for BTC/Electrum Wallet): %s%s%s\n", ui.AnsiCodes["bold"],
-		wif.ToBitcoinWIF(ecSK, true, true), ui.AnsiCodes["reset"])
-	fmt.Printf("Recovered mainnet WIF (for BTC/Electrum Wallet): %s%s%s\n", ui.AnsiCodes["bold"],
-		wif.ToBitcoinWIF(ecSK, false, true), ui.AnsiCodes["reset"])
+	if appConfig.ExportPEMFile != "" {
+		pemFiles, err := writePEMKeys(appConfig.ExportPEMFile, ecSK)
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote the ECDSA private key as PEM, unencrypted: %s (PKCS#8) and %s (SEC1). Keep safe and do not share.\n\n", pemFiles[0], pemFiles[1])
+	}
 
+	var edPKBytes []byte
 	if edSK != nil {
-		fmt.Printf("\nHere is your private key for EDDSA based assets. Keep safe and do not share.\n")
-		fmt.Printf("Recovered EdDSA/Ed25519 private key (for XRPL, SOL, TAO, etc): %s%s%s\n",
-			ui.AnsiCodes["bold"], hex.EncodeToString(edSK), ui.AnsiCodes["reset"])
-
 		// load the eddsa private key in edSK and output the public key
 		_, edPK, err2 := edwards.PrivKeyFromScalar(edSK)
 		if err2 != nil {
 			panic("ed25519: internal error: setting scalar failed")
 		}
-		fmt.Printf("Recovered EdDSA/Ed25519 public key (for XRPL tool): %s%s%s\n",
-			ui.AnsiCodes["bold"], hex.EncodeToString(edPK.SerializeCompressed()), ui.AnsiCodes["reset"])
+		edPKBytes = edPK.SerializeCompressed()
+	}
+
+	if appConfig.ExportCardanoKey != "" {
+		if edSK == nil {
+			fmt.Println(ui.ErrorBox(errors.New("⚠ this older vault has no EdDSA key, so there is no Cardano key to write")))
+			os.Exit(1)
+		}
+		cardanoFile, err := writeCardanoKey(appConfig.ExportCardanoKey, edSK, edPKBytes)
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote the EdDSA private key as a cardano-cli signing key, unencrypted: %s. Keep safe and do not share.\n\n", cardanoFile)
+	}
+
+	artifacts, err := collectArtifacts(appConfig)
+	if err != nil {
+		fmt.Println(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	written := make([]string, 0, len(artifacts)+1)
+	for _, file := range artifacts {
+		written = append(written, file)
+	}
+	if appConfig.Bundle {
+		filename, hash, err := bundleArtifacts(".", selectedVault.VaultID, artifacts, appConfig.BundlePassword)
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		fmt.Printf("Moved %d written files into the bundle: %s\nSHA-256: %s\n\n", len(artifacts), filename, hash)
+		written = []string{filename}
+	}
+
+	var addressBookFile string
+	if *exportAddresses != "" {
+		if addressBookFile, err = writeAddressBook(*exportAddresses, addressBook(selectedVault.VaultID, chains.Addresses(vaultAddresses(ecSK, edPKBytes)), ecSK, edPKBytes)); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		written = append(written, addressBookFile)
+	}
+	var watchOnlyFiles []string
+	if *exportWatchOnly != "" {
+		if watchOnlyFiles, err = writeWatchOnlyWallets(*exportWatchOnly, selectedVault.VaultID, ecSK); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		written = append(written, watchOnlyFiles...)
+	}
+	var summaryFile string
+	if *exportSummary != "" {
+		result := recoveryResult(selectedVault.VaultID, selectedVault.Name, address, ecSK, nil, edPKBytes, chains, false, false, written)
+		if len(bip85Indexes) > 0 {
+			if result.BIP85, err = bip85Result(ecSK, *bip85Words, bip85Indexes, false); err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+		}
+		backupFiles := make([]string, 0, len(*vaultsDataFiles))
+		for _, file := range *vaultsDataFiles {
+			backupFiles = append(backupFiles, file.File)
+		}
+		if summaryFile, err = writeSummary(*exportSummary, recoverySummary(result, *exportLabel, backupFiles, time.Now())); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		written = append(written, summaryFile)
+	}
+
+	if *postHook != "" {
+		var secrets []byte
+		if *passSecretsFD {
+			secrets = hookSecrets(ecSK, edSK)
+		}
+		err := runPostHook(*postHook, postHookEnv(selectedVault.VaultID, address, written, *passSecretsFD), secrets)
+		clear(secrets)
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+
+	if *addressesOnly && outputFormat == outputJSON {
+		result := recoveryResult(selectedVault.VaultID, selectedVault.Name, address, ecSK, nil, edPKBytes, chains, false, *showPubKeys, written)
+		if err := writeRecoveryResult(resultOut, result); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		return
+	}
+	if *addressesOnly {
+		addresses := vaultAddresses(ecSK, edPKBytes)
+		var publicFiles []string
+		if addressBookFile != "" {
+			publicFiles = append(publicFiles, addressBookFile)
+		}
+		publicFiles = append(publicFiles, watchOnlyFiles...)
+		if summaryFile != "" {
+			publicFiles = append(publicFiles, summaryFile)
+		}
+		out := new(bytes.Buffer)
+		printVaultAddresses(out, chains.Addresses(addresses), publicFiles)
+		fmt.Fprint(out, chainsNotice(chains, chainsSource))
+		if *checkAddress != "" {
+			printAddressCheck(out, *checkAddress, addresses)
+		}
+		if knownAddresses != nil {
+			rows, otherVaults := verifyKnownAddresses(selectedVault.VaultID, knownAddresses, addresses)
+			printKnownAddresses(out, selectedVault.VaultID, rows, otherVaults)
+		}
+		os.Stdout.Write(onScreen(out.Bytes(), *redactScreen))
+		return
+	}
+
+	// the keys are collected so that the screen lock can show them again
+	out := new(bytes.Buffer)
+	defer func() { clear(out.Bytes()) }()
+	fmt.Fprintf(out, "%s%s                %s\n", ui.AnsiCodes["darkGreenBG"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "%s%s    Success!    %s\n", ui.AnsiCodes["darkGreenBG"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "%s%s                %s\n", ui.AnsiCodes["darkGreenBG"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+
+	fmt.Fprintf(out, "\nYour vault has been recovered. Make sure this address matches your vault's Ethereum address.\n")
+	fmt.Fprintf(out, "%s%s%s  (%s)\n", ui.AnsiCodes["bold"], address, ui.AnsiCodes["reset"], ecdsaMasterKey)
+
+	if chains.Shows("ethereum", "tron", "cosmos") {
+		fmt.Fprintf(out, "\nHere is your private key for Ethereum, Tron and Cosmos SDK assets. Keep safe and do not share.\n")
+		fmt.Fprintf(out, "Recovered ECDSA private key (for ETH/MetaMask, Tron/TronLink, Cosmos/Keplr): %s%s%s\n",
+			ui.AnsiCodes["bold"], hex.EncodeToString(ecSK), ui.AnsiCodes["reset"])
+		if chains.Shows("tron") {
+			printTronAddress(out, ecSK)
+		}
+		if chains.Shows("cosmos") {
+			printCosmosAddresses(out, ecSK)
+		}
+	}
+
+	if chains.Shows("bitcoin") {
+		fmt.Fprintf(out, "\nHere are your private keys for Bitcoin assets. Keep safe and do not share.\n")
+		fmt.Fprintf(out, "Recovered testnet WIF (for BTC/Electrum Wallet): %s%s%s\n", ui.AnsiCodes["bold"],
+			wif.ToBitcoinWIF(ecSK, true, true), ui.AnsiCodes["reset"])
+		fmt.Fprintf(out, "Recovered mainnet WIF (for BTC/Electrum Wallet): %s%s%s\n", ui.AnsiCodes["bold"],
+			wif.ToBitcoinWIF(ecSK, false, true), ui.AnsiCodes["reset"])
+		if appConfig.BIP38Password != "" {
+			encrypted, err := wif.ToBIP38(ecSK, appConfig.BIP38Password)
+			if err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+			fmt.Fprintf(out, "Recovered mainnet BIP38 encrypted key (decrypt with the -bip38-password passphrase): %s%s%s\n", ui.AnsiCodes["bold"],
+				encrypted, ui.AnsiCodes["reset"])
+		}
+		printBitcoinAddresses(out, ecSK)
+	}
+	printUTXOChainWIFs(out, ecSK, chains)
+
+	switch {
+	case !chains.Shows("solana", "xrpl", "cardano", "stellar", "eddsa"):
+		// the EdDSA key is for none of the selected chains
+	case edSK != nil:
+		fmt.Fprintf(out, "\nHere is your private key for EDDSA based assets. Keep safe and do not share.\n")
+		fmt.Fprintf(out, "Recovered EdDSA/Ed25519 private key (for XRPL, SOL, ADA, XLM, TAO, etc): %s%s%s\n",
+			ui.AnsiCodes["bold"], hex.EncodeToString(edSK), ui.AnsiCodes["reset"])
+		fmt.Fprintf(out, "Recovered EdDSA/Ed25519 public key (for XRPL tool): %s%s%s\n",
+			ui.AnsiCodes["bold"], hex.EncodeToString(edPKBytes), ui.AnsiCodes["reset"])
+		if chains.Shows("cardano") {
+			printCardanoAddress(out, edPKBytes)
+		}
+		if chains.Shows("stellar") {
+			printStellarAddress(out, edPKBytes)
+		}
+	default:
+		fmt.Fprintln(out, "\nNo EdDSA/Ed25519 private key found for this older vault.")
+	}
+	fmt.Fprint(out, chainsNotice(chains, chainsSource))
+	ui.Fprintf(out, "\nNote: Some wallet apps may require you to prefix hex strings with 0x to load the key.\n")
+	ui.Fprintf(out, "Step by step import instructions for each chain are available offline: run \"recovery-tool %s\" for the topics, e.g. \"recovery-tool %s bitcoin\".\n", helpGuideCmd, helpGuideCmd)
+
+	if *showPubKeys {
+		printPublicKeyDetails(out, ecSK, edPKBytes)
+	}
+
+	if *checkAddress != "" {
+		printAddressCheck(out, *checkAddress, vaultAddresses(ecSK, edPKBytes))
+	}
+
+	if knownAddresses != nil {
+		rows, otherVaults := verifyKnownAddresses(selectedVault.VaultID, knownAddresses, vaultAddresses(ecSK, edPKBytes))
+		printKnownAddresses(out, selectedVault.VaultID, rows, otherVaults)
+	}
 
-	} else {
-		fmt.Println("\nNo EdDSA/Ed25519 private key found for this older vault.")
+	if addressBookFile != "" {
+		fmt.Fprintf(out, "\nAddress book of the vault written to %s. It holds no secrets.\n", addressBookFile)
+	}
+	if len(watchOnlyFiles) > 0 {
+		fmt.Fprintf(out, "\nWatch-only wallets of the vault written to %s. They hold no secrets.\n", strings.Join(watchOnlyFiles, ", "))
+	}
+	if summaryFile != "" {
+		fmt.Fprintf(out, "\nRecovery summary written to %s. It holds no secrets; fill in its incident record and file it.\n", summaryFile)
+	}
+
+	if *showUR {
+		ecKeyUR, err := ur.EncodeECKey(ecSK)
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		ecKeyQR, err := qr.Terminal(ecKeyUR)
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		fmt.Fprintf(out, "\nScan this QR code with your QR based wallet (e.g. Keystone, Sparrow) to import the ECDSA private key. Keep safe and do not share.\n\n")
+		fmt.Fprint(out, ecKeyQR)
+		fmt.Fprintf(out, "%s\n", ecKeyUR)
+	}
+
+	if len(bip85Indexes) > 0 {
+		if err := printBIP85Seeds(out, ecSK, *bip85Words, bip85Indexes); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+
+	if *rotate {
+		if err := printRotationPlan(out, ecSK, edPKBytes, chains, sweepParams); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+
+	var checkWord func(string) bool
+	var wordFile string
+	if *confirmWord {
+		challenge, err := newSecretsChallenge(*vaultsDataFiles)
+		if err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		checkWord, wordFile = challenge.Check, challenge.File
+	}
+	if observers := observe.Detect(); len(observers) > 0 {
+		fmt.Print(observerWarning(observers))
+	}
+	var show bool
+	if answers != nil {
+		show = *answers.ShowSecrets
+	} else if show, err = ui.RunShowSecretsForm(showSecretsPhrase, isShowSecretsPhrase, wordFile, checkWord); err != nil {
+		fmt.Println(ui.ErrorBox(err))
+		os.Exit(1)
+	}
+	if outputFormat == outputJSON {
+		result := recoveryResult(selectedVault.VaultID, selectedVault.Name, address, ecSK, edSK, edPKBytes, chains, show, *showPubKeys, written)
+		if len(bip85Indexes) > 0 {
+			if result.BIP85, err = bip85Result(ecSK, *bip85Words, bip85Indexes, show); err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+		}
+		if show && appConfig.BIP38Password != "" {
+			if result.ECDSA.BIP38, err = wif.ToBIP38(ecSK, appConfig.BIP38Password); err != nil {
+				fmt.Println(ui.ErrorBox(err))
+				os.Exit(1)
+			}
+		}
+		if err := writeRecoveryResult(resultOut, result); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+		return
+	}
+	if !show {
+		os.Stdout.Write(onScreen([]byte(fmt.Sprintf("The private keys were not shown. The vault address is %s.\n", address)), *redactScreen))
+		return
+	}
+	shown := onScreen(out.Bytes(), *redactScreen)
+	defer clear(shown)
+	os.Stdout.Write(shown)
+	if *lockAfter > 0 {
+		if err := lock.Hold(os.Stdin, os.Stdout, shown, sessionPassphrase, time.Duration(*lockAfter)*time.Minute); err != nil {
+			fmt.Println(ui.ErrorBox(err))
+			os.Exit(1)
+		}
+	}
+}
+
+// onScreen is the text to show on screen. With -redact, its keys and addresses are masked.
+func onScreen(text []byte, redact bool) []byte {
+	if !redact {
+		return text
+	}
+	return support.Mask(text)
+}
+
+// plannedOutputs lists what the recovery will show on screen and write to disk, for the confirmation screen.
+func plannedOutputs(appConfig config.AppConfig, ksKDF walletv3.KDFParams, addressesOnly, showUR, showPubKeys, rotate bool, bip85Indexes []uint32, chains ChainSelection) []string {
+	var onlyChains []string
+	if chains != nil {
+		onlyChains = []string{fmt.Sprintf("Only for the chains: %s", strings.Join(chains.IDs(), ", "))}
+	}
+	if addressesOnly {
+		outputs := append([]string{"Shown on screen: the addresses of the vault on each supported chain, and no private keys"}, onlyChains...)
+		if appConfig.ExportAddressesFile != "" {
+			outputs = append(outputs, fmt.Sprintf("Written to disk: address book %s, without secrets", appConfig.ExportAddressesFile))
+		}
+		if appConfig.ExportWatchOnlyFile != "" {
+			outputs = append(outputs, fmt.Sprintf("Written to disk: watch-only wallets %s, without secrets", appConfig.ExportWatchOnlyFile))
+		}
+		if appConfig.ExportSummaryFile != "" {
+			outputs = append(outputs, fmt.Sprintf("Written to disk: recovery summary %s, without secrets", appConfig.ExportSummaryFile))
+		}
+		if appConfig.ExportAddressesFile == "" && appConfig.ExportWatchOnlyFile == "" && appConfig.ExportSummaryFile == "" {
+			outputs = append(outputs, "Nothing is written to disk")
+		}
+		if appConfig.PostHook != "" {
+			outputs = append(outputs, fmt.Sprintf("Then the post hook runs, without secrets: %s", appConfig.PostHook))
+		}
+		return outputs
+	}
+	outputs := append([]string{"Shown on screen: the Ethereum address, the ECDSA private key and its Bitcoin WIFs, and the EdDSA keys if the vault has them"}, onlyChains...)
+	if appConfig.BIP38Password != "" {
+		outputs = append(outputs, "Shown on screen: the Bitcoin mainnet key encrypted with the -bip38-password passphrase (BIP38)")
+	}
+	if showPubKeys {
+		outputs = append(outputs, "Shown on screen: the public keys in all formats")
+	}
+	if showUR {
+		outputs = append(outputs, "Shown on screen: the ECDSA private key as a QR code")
+	}
+	if rotate {
+		outputs = append(outputs, "Shown on screen: brand-new keys and the addresses to sweep funds to")
+	}
+	if len(bip85Indexes) > 0 {
+		outputs = append(outputs, fmt.Sprintf("Shown on screen: %d BIP85 child seeds derived from the vault ECDSA key, which only this tool derives again", len(bip85Indexes)))
+	}
+	written := false
+	if len(appConfig.ExportKSFile) > 0 && len(appConfig.PasswordForKS) > 0 {
+		outputs = append(outputs, fmt.Sprintf("Written to disk: wallet v3 file %s (%s)", appConfig.ExportKSFile, ksKDF))
+		written = true
+	}
+	if len(appConfig.ExportTSSShareDir) > 0 {
+		outputs = append(outputs, fmt.Sprintf("Written to disk: one TSS share bundle per party in %s", appConfig.ExportTSSShareDir))
+		written = true
+	}
+	if appConfig.ExportPEMFile != "" {
+		pkcs8File, sec1File := pemFilenames(appConfig.ExportPEMFile)
+		outputs = append(outputs, fmt.Sprintf("Written to disk: the ECDSA private key, unencrypted, as PEM files %s and %s", pkcs8File, sec1File))
+		written = true
+	}
+	if appConfig.ExportCardanoKey != "" {
+		outputs = append(outputs, fmt.Sprintf("Written to disk: the EdDSA private key, unencrypted, as cardano-cli signing key %s", ui.SessionFilename(appConfig.ExportCardanoKey)))
+		written = true
+	}
+	if appConfig.ExportAddressesFile != "" {
+		outputs = append(outputs, fmt.Sprintf("Written to disk: address book %s, without secrets", appConfig.ExportAddressesFile))
+	}
+	if appConfig.ExportWatchOnlyFile != "" {
+		outputs = append(outputs, fmt.Sprintf("Written to disk: watch-only wallets %s, without secrets", appConfig.ExportWatchOnlyFile))
+	}
+	if appConfig.ExportSummaryFile != "" {
+		outputs = append(outputs, fmt.Sprintf("Written to disk: recovery summary %s, without secrets", appConfig.ExportSummaryFile))
+	}
+	switch {
+	case !written && appConfig.ExportAddressesFile == "" && appConfig.ExportWatchOnlyFile == "" && appConfig.ExportSummaryFile == "":
+		outputs = append(outputs, "Nothing is written to disk")
+	case written && appConfig.Bundle && appConfig.BundlePassword != "":
+		outputs = append(outputs, "Then the written key files are moved into a single password encrypted ZIP")
+	case written && appConfig.Bundle:
+		outputs = append(outputs, "Then the written key files are moved into a single ZIP")
+	}
+	if appConfig.PostHook != "" && appConfig.PassSecretsFD {
+		outputs = append(outputs, fmt.Sprintf("Then the post hook runs, and is handed the private keys: %s", appConfig.PostHook))
+	} else if appConfig.PostHook != "" {
+		outputs = append(outputs, fmt.Sprintf("Then the post hook runs, without secrets: %s", appConfig.PostHook))
+	}
+	return outputs
+}
+
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		ui.Printf("%s\n", warning)
+	}
+	if len(warnings) > 0 {
+		ui.Printf("\n")
 	}
-	fmt.Printf("\nNote: Some wallet apps may require you to prefix hex strings with 0x to load the key.\n")
 }
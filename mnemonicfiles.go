@@ -0,0 +1,113 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// MnemonicFiles are the repeated -mnemonic-file flags: each is backup=phrasefile, mapping a backup file (by path or
+// name) to the text file holding its phrase. A single value without `=` is a YAML manifest of such mappings instead.
+type MnemonicFiles []string
+
+func (m *MnemonicFiles) String() string {
+	return strings.Join(*m, ",")
+}
+
+// Set adds one -mnemonic-file flag.
+func (m *MnemonicFiles) Set(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return fmt.Errorf("⚠ -mnemonic-file needs backup=phrasefile, e.g. -mnemonic-file file1.json=file1-phrase.txt, or a manifest file")
+	}
+	*m = append(*m, s)
+	return nil
+}
+
+// mappings are the phrase files by backup file, from the flags or the manifest. Manifest paths are relative to it.
+func (m MnemonicFiles) mappings() (map[string]string, error) {
+	mappings := make(map[string]string, len(m))
+	if len(m) == 1 && !strings.Contains(m[0], "=") {
+		manifest := m[0]
+		content, err := os.ReadFile(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("⚠ unable to read the -mnemonic-file manifest `%s`: %s", manifest, err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		if err = decoder.Decode(&mappings); err != nil {
+			return nil, fmt.Errorf("⚠ the -mnemonic-file manifest `%s` is not valid, it maps each backup file to its phrase file: %s", manifest, err)
+		}
+		for backup, phraseFile := range mappings {
+			if !filepath.IsAbs(phraseFile) {
+				mappings[backup] = filepath.Join(filepath.Dir(manifest), phraseFile)
+			}
+		}
+		return mappings, nil
+	}
+	for _, mapping := range m {
+		backup, phraseFile, ok := strings.Cut(mapping, "=")
+		backup, phraseFile = strings.TrimSpace(backup), strings.TrimSpace(phraseFile)
+		if !ok || backup == "" || phraseFile == "" {
+			return nil, fmt.Errorf("⚠ invalid -mnemonic-file `%s`: use backup=phrasefile, e.g. -mnemonic-file file1.json=file1-phrase.txt. "+
+				"A manifest file can only be given alone", mapping)
+		}
+		if _, dup := mappings[backup]; dup {
+			return nil, fmt.Errorf("⚠ backup file `%s` is given more than one -mnemonic-file", backup)
+		}
+		mappings[backup] = phraseFile
+	}
+	return mappings, nil
+}
+
+// Phrases reads the phrase of each backup file from its phrase file, by backup file. Every backup file needs one, and
+// each phrase must have the right number of words. Whether it opens its backup file is checked later, like a typed one.
+func (m MnemonicFiles) Phrases(files []string) (map[string]string, error) {
+	mappings, err := m.mappings()
+	if err != nil {
+		return nil, err
+	}
+	phrases := make(map[string]string, len(files))
+	used := make(map[string]bool, len(mappings))
+	for _, file := range files {
+		key := file
+		if _, ok := mappings[key]; !ok {
+			key = filepath.Base(file)
+		}
+		phraseFile, ok := mappings[key]
+		if !ok {
+			return nil, fmt.Errorf("⚠ no -mnemonic-file for the backup file `%s`: map it by its path or name, e.g. -mnemonic-file %s=phrase.txt",
+				file, filepath.Base(file))
+		}
+		used[key] = true
+		content, err := os.ReadFile(phraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("⚠ unable to read the phrase file `%s` of `%s`: %s", phraseFile, file, err)
+		}
+		phrase := ui.CleanMnemonicInput(string(content))
+		clear(content)
+		if err = (ui.VaultsDataFile{File: file, Mnemonics: phrase}).ValidateMnemonics(); err != nil {
+			return nil, fmt.Errorf("⚠ the phrase file `%s` of `%s` is wrong: %s", phraseFile, file, strings.TrimPrefix(err.Error(), "⚠ "))
+		}
+		phrases[file] = phrase
+	}
+	unknown := make([]string, 0)
+	for backup := range mappings {
+		if !used[backup] {
+			unknown = append(unknown, backup)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("⚠ -mnemonic-file maps %s, which is not one of the backup files", strings.Join(unknown, ", "))
+	}
+	return phrases, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMnemonicFiles_Phrases(t *testing.T) {
+	dir := t.TempDir()
+	bvnPhrase, x2qPhrase := filepath.Join(dir, "bvn.txt"), filepath.Join(dir, "x2q.txt")
+	// line breaks and capitals, as in a phrase written down word by word
+	require.NoError(t, os.WriteFile(bvnPhrase, []byte("Domain damp hill depth label eye erode dutch\nimpulse betray floor donate bonus hover bitter ring\n"+
+		"unfold poet identify capital combine question profit april\n"), 0600))
+	require.NoError(t, os.WriteFile(x2qPhrase, []byte(mmNewX2q), 0600))
+	files := []string{"./test-files/new_bvn.json", "./test-files/new_x2q.json"}
+
+	var pairs MnemonicFiles
+	require.NoError(t, pairs.Set("new_bvn.json="+bvnPhrase))
+	require.NoError(t, pairs.Set("./test-files/new_x2q.json="+x2qPhrase))
+	phrases, err := pairs.Phrases(files)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{files[0]: mmNewBvn, files[1]: mmNewX2q}, phrases)
+
+	// the phrase files of a manifest are relative to it
+	manifest := filepath.Join(dir, "phrases.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte("new_bvn.json: bvn.txt\nnew_x2q.json: "+x2qPhrase+"\n"), 0600))
+	phrases, err = MnemonicFiles{manifest}.Phrases(files)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{files[0]: mmNewBvn, files[1]: mmNewX2q}, phrases)
+}
+
+func TestMnemonicFiles_Errors(t *testing.T) {
+	dir := t.TempDir()
+	phrase, short := filepath.Join(dir, "phrase.txt"), filepath.Join(dir, "short.txt")
+	require.NoError(t, os.WriteFile(phrase, []byte(mmNewBvn), 0600))
+	require.NoError(t, os.WriteFile(short, []byte("domain damp hill"), 0600))
+	manifest := filepath.Join(dir, "phrases.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte("- not a map\n"), 0600))
+	files := []string{"./test-files/new_bvn.json", "./test-files/new_x2q.json"}
+
+	tests := []struct {
+		name  string
+		flags MnemonicFiles
+		want  string
+	}{
+		{"missing backup", MnemonicFiles{"new_bvn.json=" + phrase}, "no -mnemonic-file for the backup file `./test-files/new_x2q.json`"},
+		{"word count", MnemonicFiles{"new_bvn.json=" + phrase, "new_x2q.json=" + short}, "wanted 24 phrase words but got 3"},
+		{"unreadable", MnemonicFiles{"new_bvn.json=" + phrase, "new_x2q.json=" + filepath.Join(dir, "none.txt")}, "unable to read the phrase file"},
+		{"unknown backup", MnemonicFiles{"new_bvn.json=" + phrase, "new_x2q.json=" + phrase, "new_u44.json=" + phrase}, "maps new_u44.json"},
+		{"twice", MnemonicFiles{"new_bvn.json=" + phrase, "new_bvn.json=" + phrase}, "more than one -mnemonic-file"},
+		{"manifest with pairs", MnemonicFiles{manifest, "new_bvn.json=" + phrase}, "A manifest file can only be given alone"},
+		{"invalid manifest", MnemonicFiles{manifest}, "manifest `" + manifest + "` is not valid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.flags.Phrases(files)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+	assert.Error(t, new(MnemonicFiles).Set(" "))
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// loadVaultNotes reads an operator annotations file: a JSON object mapping vault IDs to notes.
+func loadVaultNotes(file string) (map[string]string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to read the vault notes file `%s`: %s", file, err)
+	}
+	notes := make(map[string]string)
+	if err = json.Unmarshal(content, &notes); err != nil {
+		return nil, fmt.Errorf("⚠ the vault notes file `%s` must be a JSON object of vault IDs to notes: %s", file, err)
+	}
+	for vID, note := range notes {
+		notes[vID] = strings.TrimSpace(note)
+	}
+	return notes, nil
+}
+
+// annotateVaults attaches the notes to the vaults found in the backup files.
+func annotateVaults(vaults []ui.VaultPickerItem, notes map[string]string) {
+	for i := range vaults {
+		vaults[i].Note = notes[vaults[i].VaultID]
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotes_LoadAndAnnotate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"phrot42ltzawmn7nrm7mqvl5": " Treasury hot vault - sweep to Ledger X "}`), 0600))
+
+	notes, err := loadVaultNotes(file)
+	require.NoError(t, err)
+	vaults := []ui.VaultPickerItem{{VaultID: "phrot42ltzawmn7nrm7mqvl5"}, {VaultID: "other"}}
+	annotateVaults(vaults, notes)
+	assert.Equal(t, "Treasury hot vault - sweep to Ledger X", vaults[0].Note)
+	assert.Empty(t, vaults[1].Note)
+
+	require.NoError(t, os.WriteFile(file, []byte(`["not", "an", "object"]`), 0600))
+	_, err = loadVaultNotes(file)
+	assert.Error(t, err)
+}
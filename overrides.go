@@ -0,0 +1,85 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// VaultOverride is the reshare nonce and threshold to recover a vault at, when its latest reshare cannot be used.
+type VaultOverride struct {
+	Nonce, Threshold int
+}
+
+// VaultOverrides are the repeated -override vaultID=nonce:threshold flags. Unlike -nonce and -threshold, each only
+// applies to its own vault, so the right one is used whichever vault is picked.
+type VaultOverrides map[string]VaultOverride
+
+func (o VaultOverrides) String() string {
+	overrides := make([]string, 0, len(o))
+	for vID, override := range o {
+		overrides = append(overrides, fmt.Sprintf("%s=%d:%d", vID, override.Nonce, override.Threshold))
+	}
+	sort.Strings(overrides)
+	return strings.Join(overrides, ",")
+}
+
+// Set parses one -override flag.
+func (o VaultOverrides) Set(s string) error {
+	vID, values, ok := strings.Cut(s, "=")
+	nonceStr, thresholdStr, ok2 := strings.Cut(values, ":")
+	vID = strings.TrimSpace(vID)
+	if !ok || !ok2 || vID == "" {
+		return fmt.Errorf("⚠ invalid override `%s`: use vaultID=nonce:threshold, e.g. -override %s=0:2", s, "cl347wz8w00006sx3f1g23p4s")
+	}
+	nonce, err := strconv.Atoi(strings.TrimSpace(nonceStr))
+	if err != nil || nonce < 0 {
+		return fmt.Errorf("⚠ invalid reshare nonce in override `%s`: it must be 0 or more", s)
+	}
+	threshold, err := strconv.Atoi(strings.TrimSpace(thresholdStr))
+	if err != nil || threshold < 1 {
+		return fmt.Errorf("⚠ invalid threshold in override `%s`: it must be 1 or more", s)
+	}
+	if _, dup := o[vID]; dup {
+		return fmt.Errorf("⚠ vault `%s` is overridden more than once", vID)
+	}
+	o[vID] = VaultOverride{Nonce: nonce, Threshold: threshold}
+	return nil
+}
+
+// Check makes sure that every overridden vault is in the backup files, so that a mistyped vault ID is not silently
+// ignored.
+func (o VaultOverrides) Check(vaults []ui.VaultPickerItem) error {
+	known := make(map[string]bool, len(vaults))
+	for _, vault := range vaults {
+		known[vault.VaultID] = true
+	}
+	unknown := make([]string, 0)
+	for vID := range o {
+		if !known[vID] {
+			unknown = append(unknown, vID)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("⚠ -override names vaults that are not in the backup files: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// For returns the nonce and threshold overrides to recover a vault with: its own override if it has one, or else the
+// global -nonce and -threshold.
+func (o VaultOverrides) For(vID string, nonceOverride, quorumOverride *int) (*int, *int) {
+	override, ok := o[vID]
+	if !ok {
+		return nonceOverride, quorumOverride
+	}
+	return &override.Nonce, &override.Threshold
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultOverrides(t *testing.T) {
+	overrides := make(VaultOverrides)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(overrides, "override", "")
+	require.NoError(t, fs.Parse([]string{"-override", "vault-a=0:2", "-override", " vault-b = 3 : 4 "}))
+	assert.Equal(t, VaultOverrides{"vault-a": {0, 2}, "vault-b": {3, 4}}, overrides)
+	assert.Equal(t, "vault-a=0:2,vault-b=3:4", overrides.String())
+
+	for _, invalid := range []string{"vault-a", "vault-a=1", "=1:2", "vault-c=-1:2", "vault-c=1:0", "vault-c=x:2", "vault-a=1:2"} {
+		assert.Error(t, overrides.Set(invalid), invalid)
+	}
+
+	nonce, quorum := -1, 0
+	gotNonce, gotQuorum := overrides.For("vault-b", &nonce, &quorum)
+	assert.Equal(t, 3, *gotNonce)
+	assert.Equal(t, 4, *gotQuorum)
+	gotNonce, gotQuorum = overrides.For("vault-c", &nonce, &quorum)
+	assert.Same(t, &nonce, gotNonce)
+	assert.Same(t, &quorum, gotQuorum)
+
+	assert.NoError(t, overrides.Check([]ui.VaultPickerItem{{VaultID: "vault-a"}, {VaultID: "vault-b"}, {VaultID: "vault-c"}}))
+	assert.ErrorContains(t, overrides.Check([]ui.VaultPickerItem{{VaultID: "vault-a"}}), "not in the backup files: vault-b")
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/data"
+)
+
+const (
+	peekCmd = "peek"
+
+	// maxPeekEntrySize bounds how much of each entry is read into memory, so that a ZIP bomb cannot exhaust it
+	maxPeekEntrySize = 64 << 20
+)
+
+type (
+	// PeekEntry describes a JSON file in a backup ZIP from its unencrypted fields only.
+	PeekEntry struct {
+		Name      string
+		Size      uint64
+		Timestamp string
+		Vaults    []PeekVault
+		// Problem is set if the entry is not a readable backup file
+		Problem string
+	}
+	PeekVault struct {
+		ID       string
+		ReShares int
+	}
+)
+
+func runPeek(args []string) error {
+	fs := flag.NewFlagSet(peekCmd, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: recovery-tool %s backup.zip …\n\n"+
+			"Lists the backup files in each ZIP without extracting them. No mnemonics are needed and nothing is decrypted.\n", peekCmd)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errors.New("⚠ expected at least one ZIP file")
+	}
+	for _, file := range fs.Args() {
+		entries, err := peekZip(file)
+		if err != nil {
+			return err
+		}
+		printPeek(os.Stdout, file, entries)
+	}
+	return nil
+}
+
+// peekZip reads the JSON files of a ZIP into memory, one at a time, and nothing is written to disk.
+func peekZip(file string) ([]PeekEntry, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to open ZIP `%s`: %s", file, err)
+	}
+	defer zr.Close()
+	entries := make([]PeekEntry, 0, len(zr.File))
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".json") || strings.HasPrefix(path.Base(f.Name), "._") {
+			continue
+		}
+		entries = append(entries, peekEntry(f))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+func peekEntry(f *zip.File) PeekEntry {
+	entry := PeekEntry{Name: f.Name, Size: f.UncompressedSize64}
+	if f.UncompressedSize64 > maxPeekEntrySize {
+		entry.Problem = fmt.Sprintf("larger than %d MiB, not a backup file", maxPeekEntrySize>>20)
+		return entry
+	}
+	rc, err := f.Open()
+	if err != nil {
+		entry.Problem = fmt.Sprintf("unable to read: %s", err)
+		return entry
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(io.LimitReader(rc, maxPeekEntrySize+1))
+	switch {
+	case err != nil:
+		entry.Problem = fmt.Sprintf("unable to read: %s", err)
+		return entry
+	case len(content) > maxPeekEntrySize:
+		entry.Problem = fmt.Sprintf("larger than %d MiB, not a backup file", maxPeekEntrySize>>20)
+		return entry
+	}
+	if content, _, err = data.UnwrapBackup(content); err != nil {
+		entry.Problem = "a mobile app envelope holding more than one backup"
+		return entry
+	}
+	saveData := new(SavedData)
+	if err = json.Unmarshal(content, saveData); err != nil || len(saveData.Vaults) == 0 {
+		entry.Problem = "not a backup file"
+		return entry
+	}
+	entry.Timestamp = saveData.Timestamp
+	for vID, resharesMap := range saveData.Vaults {
+		entry.Vaults = append(entry.Vaults, PeekVault{ID: vID, ReShares: len(resharesMap)})
+	}
+	sort.Slice(entry.Vaults, func(i, j int) bool { return entry.Vaults[i].ID < entry.Vaults[j].ID })
+	return entry
+}
+
+// printPeek prints the listing of a ZIP. Vault names and signer labels are encrypted in backup files, so only vault
+// IDs and backup times can be shown.
+func printPeek(out io.Writer, file string, entries []PeekEntry) {
+	fmt.Fprintf(out, "%s:\n", file)
+	if len(entries) == 0 {
+		fmt.Fprintf(out, "  No JSON files.\n\n")
+		return
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(out, "  %s  %d bytes\n", entry.Name, entry.Size)
+		if entry.Problem != "" {
+			fmt.Fprintf(out, "    %s\n", entry.Problem)
+			continue
+		}
+		if entry.Timestamp != "" {
+			fmt.Fprintf(out, "    backed up %s\n", entry.Timestamp)
+		}
+		for _, vault := range entry.Vaults {
+			fmt.Fprintf(out, "    vault %s, %d reshares\n", vault.ID, vault.ReShares)
+		}
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeek_ListsBackupsWithoutExtracting(t *testing.T) {
+	dir := t.TempDir()
+	backup, err := os.ReadFile("./test-files/v2.json")
+	require.NoError(t, err)
+	file := filepath.Join(dir, "backups.zip")
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range map[string][]byte{
+		"signer-a/backup.json":            backup,
+		"notes.json":                      []byte(`{"hello": "world"}`),
+		"readme.txt":                      []byte("ignored"),
+		"__MACOSX/signer-a/._backup.json": []byte("resource fork"),
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(file, buf.Bytes(), 0600))
+
+	entries, err := peekZip(file)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "notes.json", entries[0].Name)
+	assert.Equal(t, "not a backup file", entries[0].Problem)
+	assert.Equal(t, "signer-a/backup.json", entries[1].Name)
+	assert.Equal(t, uint64(len(backup)), entries[1].Size)
+	assert.Empty(t, entries[1].Problem)
+	assert.Equal(t, "2024-07-03T10:45:23.694Z", entries[1].Timestamp)
+	require.Len(t, entries[1].Vaults, 1)
+	assert.Positive(t, entries[1].Vaults[0].ReShares)
+
+	out := new(bytes.Buffer)
+	printPeek(out, file, entries)
+	assert.Contains(t, out.String(), "vault "+entries[1].Vaults[0].ID)
+
+	// nothing was extracted
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	_, err = peekZip("./test-files/v2.json")
+	assert.Error(t, err)
+}
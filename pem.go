@@ -0,0 +1,43 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/pemkey"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// pemFilenames are the files written by -export-pem, tagged with the session ID: the PKCS#8 key under the given name,
+// and the SEC1 key, as read by `openssl ec`, next to it.
+func pemFilenames(name string) (pkcs8File, sec1File string) {
+	ext := filepath.Ext(name)
+	return ui.SessionFilename(name), ui.SessionFilename(strings.TrimSuffix(name, ext) + "-sec1" + ext)
+}
+
+// writePEMKeys writes the recovered ECDSA key as PKCS#8 and SEC1 PEM files, readable by the owner only. Existing files
+// are never overwritten.
+func writePEMKeys(name string, ecdsaSK []byte) ([]string, error) {
+	pkcs8File, sec1File := pemFilenames(name)
+	pkcs8PEM, err := pemkey.EncodePKCS8(pemkey.Secp256k1, ecdsaSK)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(pkcs8PEM)
+	sec1PEM, err := pemkey.EncodeSEC1(pemkey.Secp256k1, ecdsaSK)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(sec1PEM)
+	for file, data := range map[string][]byte{pkcs8File: pkcs8PEM, sec1File: sec1PEM} {
+		if err = writeNewFile(file, data); err != nil {
+			return nil, fmt.Errorf("⚠ failed to write the PEM key file: %w", err)
+		}
+	}
+	return []string{pkcs8File, sec1File}, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/config"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/pemkey"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPEM_WriteKeys(t *testing.T) {
+	dir := t.TempDir()
+	ecdsaSK, _ := hex.DecodeString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	name := filepath.Join(dir, "ecdsa.pem")
+
+	files, err := writePEMKeys(name, ecdsaSK)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "ecdsa-"+ui.SessionID+".pem"), filepath.Join(dir, "ecdsa-sec1-"+ui.SessionID+".pem")}, files)
+
+	sec1, err := os.ReadFile(files[1])
+	require.NoError(t, err)
+	block, _ := pem.Decode(sec1)
+	require.NotNil(t, block)
+	assert.Equal(t, pemkey.SEC1BlockType, block.Type)
+	_, d, err := pemkey.ParseSEC1(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, ecdsaSK, d)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the PEM files are bundled with the other files of the session, and never overwritten
+	artifacts, err := collectArtifacts(config.AppConfig{ExportPEMFile: name})
+	require.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+	_, err = writePEMKeys(name, ecdsaSK)
+	assert.Error(t, err)
+}
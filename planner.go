@@ -0,0 +1,105 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+type (
+	// RecoveryPlan is a checklist of the shares of a vault: which are held in the input files, and how many more are needed.
+	RecoveryPlan struct {
+		VaultID string
+		Name    string
+		Note    string
+		Quorum  int
+		// Timeline is the backup timeline of the input files holding the vault
+		Timeline []ui.TimelineEntry
+		Shares   []PlannedShare
+		Held     int
+		Needed   int
+		// SingleSigner is set when one backup file holds enough shares on its own, e.g. for a threshold 1 vault
+		SingleSigner bool
+	}
+	PlannedShare struct {
+		ShareID string
+		Files   []string
+		// Unknown is set for a held share that is not one of the vault's parties at this reshare nonce
+		Unknown bool
+	}
+)
+
+// planVaultRecovery works out which of a vault's shares are held, using the share IDs of all parties recorded in each share.
+func planVaultRecovery(vault ui.VaultPickerItem) RecoveryPlan {
+	plan := RecoveryPlan{VaultID: vault.VaultID, Name: vault.Name, Note: vault.Note, Quorum: vault.Quorum, Timeline: vault.Timeline,
+		SingleSigner: vault.SingleSigner()}
+	byShareID := make(map[string]int, len(vault.PartyShareIDs))
+	for _, shareID := range vault.PartyShareIDs {
+		if _, ok := byShareID[shareID]; ok {
+			continue
+		}
+		byShareID[shareID] = len(plan.Shares)
+		plan.Shares = append(plan.Shares, PlannedShare{ShareID: shareID})
+	}
+	for _, held := range vault.HeldShares {
+		i, ok := byShareID[held.ShareID]
+		if !ok {
+			i = len(plan.Shares)
+			byShareID[held.ShareID] = i
+			plan.Shares = append(plan.Shares, PlannedShare{ShareID: held.ShareID, Unknown: true})
+		}
+		if len(plan.Shares[i].Files) == 0 && !plan.Shares[i].Unknown {
+			plan.Held++
+		}
+		plan.Shares[i].Files = append(plan.Shares[i].Files, held.File)
+	}
+	plan.Needed = max(0, plan.Quorum-plan.Held)
+	return plan
+}
+
+func printRecoveryPlan(plan RecoveryPlan) {
+	fmt.Printf("%s%sRECOVERY PLAN FOR VAULT \"%s\" WITH ID %s%s\n\n", ui.AnsiCodes["bold"], ui.AnsiCodes["invertOn"], plan.Name, plan.VaultID, ui.AnsiCodes["reset"])
+	if plan.Note != "" {
+		fmt.Printf("Note: %s\n", plan.Note)
+	}
+	if plan.SingleSigner {
+		fmt.Printf("Single signer: one backup file holds all %d shares needed, so no other file or party is involved.\n\n", plan.Quorum)
+	} else {
+		fmt.Printf("Quorum: %d of %d parties. You hold %d distinct share(s).\n\n", plan.Quorum, len(plan.Shares), plan.Held)
+	}
+	for _, share := range plan.Shares {
+		switch {
+		case share.Unknown:
+			fmt.Printf(" [?] share %s  %s (not a party of this vault at this reshare nonce)\n", shortShareID(share.ShareID), strings.Join(share.Files, ", "))
+		case len(share.Files) > 0:
+			fmt.Printf(" [%s] share %s  %s\n", ui.Plain("✓"), shortShareID(share.ShareID), strings.Join(share.Files, ", "))
+		default:
+			fmt.Printf(" [ ] share %s  missing\n", shortShareID(share.ShareID))
+		}
+	}
+	if timeline := ui.FormatTimeline(plan.Timeline); timeline != "" {
+		fmt.Printf("\n%s", timeline)
+	}
+	if plan.SingleSigner {
+		fmt.Printf("\n%sThis vault can be recovered from that backup file alone.%s\n", ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+		return
+	}
+	if plan.Needed == 0 {
+		fmt.Printf("\n%sYou have enough shares to recover this vault.%s\n", ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+		return
+	}
+	fmt.Printf("\n%sYou need the backup files of %d more of the missing shares above.%s\n", ui.AnsiCodes["bold"], plan.Needed, ui.AnsiCodes["reset"])
+}
+
+// shortShareID abbreviates the long decimal share IDs for display.
+func shortShareID(shareID string) string {
+	if len(shareID) <= 16 {
+		return shareID
+	}
+	return ui.Plain(shareID[:8] + "…" + shareID[len(shareID)-6:])
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanner_FixtureVault(t *testing.T) {
+	manifest, err := generateFixtures(fixtureOptions{OutDir: t.TempDir(), Vaults: 1, Parties: 3, Threshold: 2, V2: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// hold the first file only
+	files := []ui.VaultsDataFile{{File: manifest.Files[0].File, Mnemonics: manifest.Files[0].Mnemonics}}
+	_, _, _, vaultsFormData, err := runTool(files, ToolOptions{})
+	if !assert.NoError(t, err) || !assert.Len(t, vaultsFormData, 1) {
+		return
+	}
+	plan := planVaultRecovery(vaultsFormData[0])
+	if !assert.Len(t, plan.Shares, 3) || !assert.Equal(t, 1, plan.Held) || !assert.Equal(t, 1, plan.Needed) {
+		return
+	}
+	held := 0
+	for _, share := range plan.Shares {
+		if len(share.Files) > 0 {
+			held++
+			assert.Equal(t, []string{manifest.Files[0].File}, share.Files)
+		}
+	}
+	assert.Equal(t, 1, held)
+	assert.False(t, plan.SingleSigner, "one file of a 2 of 3 vault is not a single signer")
+	if assert.Len(t, plan.Timeline, 1) {
+		assert.Equal(t, manifest.Files[0].File, plan.Timeline[0].File)
+		assert.False(t, plan.Timeline[0].BackedUpAt.IsZero(), "fixtures are stamped with their backup time")
+	}
+
+	// holding all files is enough
+	files = append(files, ui.VaultsDataFile{File: manifest.Files[1].File, Mnemonics: manifest.Files[1].Mnemonics},
+		ui.VaultsDataFile{File: manifest.Files[2].File, Mnemonics: manifest.Files[2].Mnemonics})
+	_, _, _, vaultsFormData, err = runTool(files, ToolOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	plan = planVaultRecovery(vaultsFormData[0])
+	assert.Equal(t, 3, plan.Held)
+	assert.Equal(t, 0, plan.Needed)
+}
+
+func TestPlanner_SingleSigner(t *testing.T) {
+	files := []ui.VaultsDataFile{{File: "./test-files/new_single.json", Mnemonics: mmNewSingle}}
+	_, _, _, vaultsFormData, err := runTool(files, ToolOptions{})
+	if !assert.NoError(t, err) || !assert.Len(t, vaultsFormData, 1) {
+		return
+	}
+	vault := vaultsFormData[0]
+	assert.True(t, vault.SingleSigner())
+	assert.Contains(t, vault.SharesSummary(), "new_single.json alone")
+
+	plan := planVaultRecovery(vault)
+	assert.True(t, plan.SingleSigner)
+	assert.Equal(t, 0, plan.Needed)
+
+	// recovery and HD derivation need nothing but that file
+	_, ecSK, _, _, err := runTool(files, ToolOptions{VaultID: vault.VaultID})
+	if !assert.NoError(t, err) || !assert.Len(t, ecSK, 32) {
+		return
+	}
+	out := new(bytes.Buffer)
+	if assert.NoError(t, printBIP85Seeds(out, ecSK, 12, []uint32{0})) {
+		assert.NotEmpty(t, out.String())
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// maxPolicySize keeps the policy readable on a single screen of the acknowledgement form.
+const maxPolicySize = 16 << 10
+
+// HandlingPolicy is the organization's key handling policy that must be accepted before any private key is shown.
+type HandlingPolicy struct {
+	Text string
+	// SHA256 identifies the exact policy text that was accepted
+	SHA256 string
+}
+
+func loadHandlingPolicy(file string) (*HandlingPolicy, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to read the policy file `%s`: %s", file, err)
+	}
+	if len(content) > maxPolicySize {
+		return nil, fmt.Errorf("⚠ the policy file `%s` is larger than %d KiB", file, maxPolicySize>>10)
+	}
+	text := strings.TrimSpace(string(content))
+	if text == "" {
+		return nil, fmt.Errorf("⚠ the policy file `%s` is empty", file)
+	}
+	hash := sha256.Sum256(content)
+	return &HandlingPolicy{Text: text, SHA256: hex.EncodeToString(hash[:])}, nil
+}
+
+// policyAcknowledgement is the line printed once the policy is accepted, so that saved logs of the session record it.
+func policyAcknowledgement(policy *HandlingPolicy, at time.Time) string {
+	return fmt.Sprintf("Key handling policy (SHA-256 %s) accepted at %s in session %s.", policy.SHA256, at.UTC().Format(time.RFC3339), ui.SessionID)
+}
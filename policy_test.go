@@ -0,0 +1,43 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_LoadAndAcknowledge(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.txt")
+	require.NoError(t, os.WriteFile(file, []byte("\nNever photograph or copy private keys.\n"), 0600))
+
+	policy, err := loadHandlingPolicy(file)
+	require.NoError(t, err)
+	assert.Equal(t, "Never photograph or copy private keys.", policy.Text)
+	assert.Len(t, policy.SHA256, 64)
+
+	ack := policyAcknowledgement(policy, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	assert.Contains(t, ack, policy.SHA256)
+	assert.Contains(t, ack, "2024-05-01T12:00:00Z")
+	assert.Contains(t, ack, ui.SessionID)
+
+	require.NoError(t, os.WriteFile(file, []byte(" \n"), 0600))
+	_, err = loadHandlingPolicy(file)
+	assert.ErrorContains(t, err, "empty")
+
+	require.NoError(t, os.WriteFile(file, []byte(strings.Repeat("a", maxPolicySize+1)), 0600))
+	_, err = loadHandlingPolicy(file)
+	assert.Error(t, err)
+
+	_, err = loadHandlingPolicy(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// postHookSecretsFD is the file descriptor on which -pass-secrets-fd hands the private keys to the post hook.
+const postHookSecretsFD = 3
+
+// HookSecrets are the private keys passed to the post hook with -pass-secrets-fd, as JSON.
+type HookSecrets struct {
+	ECDSAPrivateKey string `json:"ecdsaPrivateKey"`
+	EdDSAPrivateKey string `json:"eddsaPrivateKey,omitempty"`
+}
+
+// postHookEnv describes the outputs of the session to the post hook. It never holds secrets: artifact paths are
+// absolute and separated like PATH entries.
+func postHookEnv(vaultID, address string, artifacts []string, secretsFD bool) []string {
+	paths := make([]string, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		if abs, err := filepath.Abs(artifact); err == nil {
+			artifact = abs
+		}
+		paths = append(paths, artifact)
+	}
+	sort.Strings(paths)
+	env := append(os.Environ(),
+		"RECOVERY_SESSION_ID="+ui.SessionID,
+		"RECOVERY_VAULT_ID="+vaultID,
+		"RECOVERY_ADDRESS="+address,
+		"RECOVERY_ARTIFACTS="+strings.Join(paths, string(os.PathListSeparator)),
+	)
+	if secretsFD {
+		env = append(env, fmt.Sprintf("RECOVERY_SECRETS_FD=%d", postHookSecretsFD))
+	}
+	return env
+}
+
+// hookSecrets encodes the private keys for -pass-secrets-fd. edSK is nil for older vaults.
+func hookSecrets(ecSK, edSK []byte) []byte {
+	secrets := HookSecrets{ECDSAPrivateKey: hex.EncodeToString(ecSK)}
+	if edSK != nil {
+		secrets.EdDSAPrivateKey = hex.EncodeToString(edSK)
+	}
+	data, _ := json.Marshal(secrets)
+	return data
+}
+
+// runPostHook runs the -post-hook command with the session outputs in its environment, and the secrets on
+// postHookSecretsFD if they are given. Its output goes to the terminal, and it must exit successfully.
+func runPostHook(command string, env []string, secrets []byte) error {
+	cmd := hookCommand(command)
+	cmd.Env = env
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if secrets != nil {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		cmd.ExtraFiles = []*os.File{r}
+		go func() {
+			_, _ = w.Write(secrets)
+			_ = w.Close()
+		}()
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("⚠ the post hook failed: %v", err)
+	}
+	return nil
+}
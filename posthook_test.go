@@ -0,0 +1,57 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook commands are POSIX shell")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "env.txt")
+	artifacts := []string{filepath.Join(dir, "wallet.json"), filepath.Join(dir, "addresses.csv")}
+	env := postHookEnv("vault-1", "0xabc", artifacts, false)
+
+	require.NoError(t, runPostHook(`printf '%s\n%s\n%s\n%s\n' "$RECOVERY_SESSION_ID" "$RECOVERY_VAULT_ID" "$RECOVERY_ARTIFACTS" "$RECOVERY_SECRETS_FD" > "`+out+`"`, env, nil))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	lines := strings.Split(string(data), "\n")
+	assert.Equal(t, ui.SessionID, lines[0])
+	assert.Equal(t, "vault-1", lines[1])
+	assert.Equal(t, artifacts[1]+":"+artifacts[0], lines[2])
+	assert.Empty(t, lines[3])
+
+	assert.Error(t, runPostHook("exit 3", env, nil))
+}
+
+func TestPostHook_SecretsFD(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file descriptors are not inherited on Windows")
+	}
+	out := filepath.Join(t.TempDir(), "secrets.json")
+	ecSK := make([]byte, 32)
+	ecSK[31] = 1
+	env := postHookEnv("vault-1", "0xabc", nil, true)
+
+	require.NoError(t, runPostHook(`cat <&"$RECOVERY_SECRETS_FD" > "`+out+`"`, env, hookSecrets(ecSK, nil)))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	secrets := new(HookSecrets)
+	require.NoError(t, json.Unmarshal(data, secrets))
+	assert.Equal(t, strings.Repeat("0", 63)+"1", secrets.ECDSAPrivateKey)
+	assert.Empty(t, secrets.EdDSAPrivateKey)
+}
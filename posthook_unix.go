@@ -0,0 +1,19 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build !windows
+
+package main
+
+import (
+	"os/exec"
+)
+
+// postHookSecretsSupported tells whether the post hook can receive secrets on an inherited file descriptor.
+const postHookSecretsSupported = true
+
+// hookCommand runs the post hook through the shell, so that it can be a pipeline or use quoting.
+func hookCommand(command string) *exec.Cmd {
+	return exec.Command("/bin/sh", "-c", command)
+}
@@ -0,0 +1,20 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+)
+
+// postHookSecretsSupported tells whether the post hook can receive secrets on an inherited file descriptor. Windows
+// processes do not inherit extra file descriptors.
+const postHookSecretsSupported = false
+
+// hookCommand runs the post hook through cmd.exe, so that it can use its built-in commands and quoting.
+func hookCommand(command string) *exec.Cmd {
+	return exec.Command("cmd.exe", "/C", command)
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+)
+
+// Rough costs of the recovery, measured on typical backups. Compressed V2 shares inflate to about 4 times their size,
+// and the parsed save data, with its many big integers, takes about twice the size of its JSON in memory.
+const (
+	shareInflation  = 4
+	parsedOverhead  = 2
+	parseThroughput = 32 << 20 // bytes of share JSON parsed per second
+
+	// slowRecovery is how long a recovery can run before users think it hangs
+	slowRecovery = time.Minute
+)
+
+// Preflight is the estimated work of a recovery, made from the backup files before any phrase is entered.
+type Preflight struct {
+	Files, Vaults, Reshares int
+	// Decrypted is the size of the latest reshare of each vault in all files, which is decrypted and parsed
+	Decrypted uint64
+	// KDFMemory is the memory used by scrypt to encrypt the wallet v3 file, if one is exported
+	KDFMemory  uint64
+	PeakMemory uint64
+	Duration   time.Duration
+
+	// fileBytes and largestVault size the low memory mode, which only holds the shares of one vault at a time
+	fileBytes, largestVault uint64
+}
+
+// lowMemory is set by -max-memory when the estimated peak memory is over the ceiling. Vaults are then listed without
+// keeping their parsed shares, and memory is returned to the OS after each backup file.
+var lowMemory bool
+
+// estimatePreflight estimates the memory and time a recovery takes. Every vault of every file is decrypted and parsed
+// to list the vaults, then the chosen vault again to recover it. kdf is nil if no wallet v3 file is exported.
+func estimatePreflight(files []string, contents map[string][]byte, kdf *walletv3.KDFParams) (Preflight, error) {
+	p := Preflight{Files: len(files)}
+	vaults := make(map[string]uint64)
+	for _, file := range files {
+		content := contents[file]
+		if content == nil {
+			var err error
+			if content, err = os.ReadFile(file); err != nil {
+				return p, fmt.Errorf("⚠ unable to read `%s`: %s", file, err)
+			}
+		}
+		p.fileBytes += uint64(len(content))
+		saveData, err := loadSavedData(ui.VaultsDataFile{File: file, Content: content})
+		if err != nil {
+			return p, err
+		}
+		for vID, reshares := range saveData.Vaults {
+			p.Reshares += len(reshares)
+			latest := -1
+			for nonce := range reshares {
+				latest = max(latest, nonce)
+			}
+			size := uint64(len(reshares[latest].CipherTextB64)) * 3 / 4
+			vaults[vID] += size
+			p.Decrypted += size
+		}
+	}
+	p.Vaults = len(vaults)
+
+	for _, size := range vaults {
+		p.largestVault = max(p.largestVault, size)
+	}
+	inflated := p.Decrypted * shareInflation
+	p.PeakMemory = p.fileBytes + p.Decrypted + inflated*parsedOverhead
+	p.Duration = time.Duration(float64(inflated+p.largestVault*shareInflation) / parseThroughput * float64(time.Second))
+	if kdf != nil {
+		p.KDFMemory = 128 * uint64(kdf.N) * uint64(kdf.R)
+		p.PeakMemory = max(p.PeakMemory, p.KDFMemory)
+		if unlock, err := kdf.EstimateUnlockTime(); err == nil {
+			p.Duration += unlock
+		}
+	}
+	return p, nil
+}
+
+// String summarizes the estimate in a line.
+func (p Preflight) String() string {
+	duration := "a few seconds"
+	if p.Duration > 10*time.Second {
+		duration = "about " + p.Duration.Round(time.Second).String()
+	}
+	return fmt.Sprintf("Pre-flight check: %s, %s, %s, %s to decrypt. Estimated peak memory %s, time %s.",
+		countOf(p.Files, "file"), countOf(p.Vaults, "vault"), countOf(p.Reshares, "reshare"), formatSize(p.Decrypted), formatSize(p.PeakMemory), duration)
+}
+
+// countOf is a count and its noun, in the plural unless it is 1, e.g. for the single file of a single signer.
+func countOf(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// formatSize shows a size in KiB below a MiB, and in MiB above.
+func formatSize(size uint64) string {
+	if size < 1<<20 {
+		return fmt.Sprintf("%.0f KiB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%.0f MiB", float64(size)/(1<<20))
+}
+
+// Warnings tell the user when the machine looks under-resourced or the recovery slow, and how to reduce the work.
+// available is the free memory of the machine, or 0 if it is not known.
+func (p Preflight) Warnings(available uint64) (warnings []string) {
+	if available > 0 && p.PeakMemory > available {
+		warnings = append(warnings, fmt.Sprintf("⚠ The recovery may need about %s of memory, but only %s is available. Close other programs or use a machine with more memory.",
+			formatSize(p.PeakMemory), formatSize(available)))
+		if p.KDFMemory == p.PeakMemory {
+			warnings = append(warnings, "⚠ Most of it is for encrypting the wallet v3 file: a lighter -ks-kdf, like standard, needs less.")
+		}
+	}
+	if p.Duration > slowRecovery {
+		warnings = append(warnings, fmt.Sprintf("⚠ The recovery may take about %s on this machine. It is working even when nothing moves on screen, so do not stop it.",
+			p.Duration.Round(time.Second)))
+		if p.Files > 1 && p.Vaults > 1 {
+			warnings = append(warnings, "⚠ Every vault in the files is decrypted to list the vaults. Exporting backups that hold only the vault to recover reduces the work.")
+		}
+	}
+	return warnings
+}
+
+// LowMemoryPeak is the estimated peak memory in low memory mode, which only holds the shares of one vault at a time.
+func (p Preflight) LowMemoryPeak() uint64 {
+	return max(p.fileBytes+p.largestVault*(1+shareInflation*parsedOverhead), p.KDFMemory)
+}
+
+// MemoryPlan decides whether the recovery must run in low memory mode to stay under the -max-memory ceiling. The
+// wallet v3 file's scrypt memory cannot be reduced, so a ceiling under it is an error.
+func (p Preflight) MemoryPlan(ceiling uint64) (low bool, notes []string, err error) {
+	if p.KDFMemory > ceiling {
+		return false, nil, fmt.Errorf("⚠ encrypting the wallet v3 file needs %s of memory, over -max-memory %s: choose a lighter -ks-kdf",
+			formatSize(p.KDFMemory), formatSize(ceiling))
+	}
+	if p.PeakMemory <= ceiling {
+		return false, nil, nil
+	}
+	notes = append(notes, fmt.Sprintf("The estimated peak memory %s is over -max-memory %s, so the vaults are listed in low memory mode. "+
+		"It only keeps one vault's shares at a time and returns memory after each file, which is slower.", formatSize(p.PeakMemory), formatSize(ceiling)))
+	if low := p.LowMemoryPeak(); low > ceiling {
+		notes = append(notes, fmt.Sprintf("⚠ Even in low memory mode, the recovery may need about %s. It may fail on this machine.", formatSize(low)))
+	}
+	return true, notes, nil
+}
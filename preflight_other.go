@@ -0,0 +1,12 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+//go:build !linux
+
+package main
+
+// availableMemory is not known on this platform, so the memory check is skipped.
+func availableMemory() uint64 {
+	return 0
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflight(t *testing.T) {
+	files := []string{"./test-files/v2.json"}
+	p, err := estimatePreflight(files, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, p.Files)
+	assert.Equal(t, 1, p.Vaults)
+	assert.Positive(t, p.Reshares)
+	assert.Positive(t, p.Decrypted)
+	assert.Greater(t, p.PeakMemory, p.Decrypted*shareInflation)
+	assert.Zero(t, p.KDFMemory)
+	assert.Contains(t, p.String(), "1 file, 1 vault,")
+	assert.Contains(t, p.String(), "time a few seconds")
+	assert.Empty(t, p.Warnings(0))
+	assert.Empty(t, p.Warnings(1<<40))
+
+	kdf := walletv3.Presets["paranoid"]
+	withKDF, err := estimatePreflight(files, nil, &kdf)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1<<30), withKDF.KDFMemory)
+	assert.Equal(t, withKDF.KDFMemory, withKDF.PeakMemory)
+	warnings := withKDF.Warnings(512 << 20)
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "only 512 MiB is available")
+	assert.Contains(t, warnings[1], "-ks-kdf")
+
+	slow := Preflight{Files: 3, Vaults: 20, Duration: 3 * time.Minute}
+	warnings = slow.Warnings(0)
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "3m0s")
+	assert.Contains(t, warnings[0], "do not stop it")
+
+	low, notes, err := p.MemoryPlan(1 << 30)
+	require.NoError(t, err)
+	assert.False(t, low)
+	assert.Empty(t, notes)
+	many := Preflight{Vaults: 20, PeakMemory: 400 << 20, fileBytes: 4 << 20, largestVault: 1 << 20}
+	require.Less(t, many.LowMemoryPeak(), many.PeakMemory)
+	low, notes, err = many.MemoryPlan(many.LowMemoryPeak())
+	require.NoError(t, err)
+	assert.True(t, low)
+	require.Len(t, notes, 1)
+	assert.Contains(t, notes[0], "low memory mode")
+	low, notes, err = many.MemoryPlan(1 << 10)
+	require.NoError(t, err)
+	assert.True(t, low)
+	require.Len(t, notes, 2)
+	assert.Contains(t, notes[1], "may fail")
+	_, _, err = withKDF.MemoryPlan(512 << 20)
+	assert.ErrorContains(t, err, "lighter -ks-kdf")
+
+	_, err = estimatePreflight([]string{"./test-files/does-not-exist.json"}, nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/crypto/sha3"
+)
+
+// PublicKeyDetails are the representations of the recovered ECDSA public key, and the steps from it to the Ethereum address.
+type PublicKeyDetails struct {
+	Compressed   string
+	Uncompressed string
+	X, Y         string
+	Keccak256    string
+	Address      string
+}
+
+func ecdsaPublicKeyDetails(ecdsaSK []byte) PublicKeyDetails {
+	scl := secp256k1.ModNScalar{}
+	scl.SetByteSlice(ecdsaSK)
+	pk := secp256k1.NewPrivateKey(&scl).PubKey()
+	scl.Zero()
+
+	uncompressed := pk.SerializeUncompressed()
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(uncompressed[1:])
+	sum := hash.Sum(nil)
+	return PublicKeyDetails{
+		Compressed:   hex.EncodeToString(pk.SerializeCompressed()),
+		Uncompressed: hex.EncodeToString(uncompressed),
+		X:            hex.EncodeToString(uncompressed[1:33]),
+		Y:            hex.EncodeToString(uncompressed[33:]),
+		Keccak256:    hex.EncodeToString(sum),
+		Address:      common.BytesToAddress(sum[len(sum)-20:]).Hex(),
+	}
+}
+
+// printPublicKeyDetails prints the -show-pubkeys view, for integrations that need a specific public key encoding.
+func printPublicKeyDetails(out io.Writer, ecdsaSK, eddsaPK []byte) {
+	details := ecdsaPublicKeyDetails(ecdsaSK)
+	fmt.Fprintf(out, "\n%s%s PUBLIC KEYS %s\n", ui.AnsiCodes["invertOn"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "\nECDSA/secp256k1 public key\n")
+	fmt.Fprintf(out, "  Compressed (33 bytes):   %s\n", details.Compressed)
+	fmt.Fprintf(out, "  Uncompressed (65 bytes): %s\n", details.Uncompressed)
+	fmt.Fprintf(out, "  X: %s\n", details.X)
+	fmt.Fprintf(out, "  Y: %s\n", details.Y)
+	fmt.Fprintf(out, "\nEthereum address derivation\n")
+	fmt.Fprintf(out, "  1. Keccak-256 of X || Y (uncompressed key without the 04 prefix): %s\n", details.Keccak256)
+	fmt.Fprintf(out, "  2. Last 20 bytes of the hash, EIP-55 checksummed: %s\n", details.Address)
+	if eddsaPK != nil {
+		fmt.Fprintf(out, "\nEdDSA/Ed25519 public key (32 bytes): %s\n", hex.EncodeToString(eddsaPK))
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubKeys_Details(t *testing.T) {
+	privKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	pk := privKey.PubKey()
+	_, address, err := getTSSPubKeyForEthereum(pk.X(), pk.Y())
+	require.NoError(t, err)
+
+	details := ecdsaPublicKeyDetails(privKey.Serialize())
+	assert.Len(t, details.Compressed, 66)
+	assert.Len(t, details.Uncompressed, 130)
+	assert.Equal(t, "04"+details.X+details.Y, details.Uncompressed)
+	assert.Equal(t, details.Keccak256[24:], strings.ToLower(details.Address[2:]), "the address is the last 20 bytes of the hash")
+	assert.Equal(t, address, details.Address)
+
+	out := new(bytes.Buffer)
+	printPublicKeyDetails(out, privKey.Serialize(), nil)
+	assert.Contains(t, out.String(), details.Uncompressed)
+	assert.NotContains(t, out.String(), "Ed25519")
+}
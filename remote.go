@@ -0,0 +1,62 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/source"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// fetchRemoteInputs downloads the URL inputs into contents, keyed by their display names, so that the signatures in the
+// URLs are never shown. It returns the inputs with each URL replaced by its display name, and the local files among them.
+// URLs are only accepted with -remote; without it the tool never touches the network.
+func fetchRemoteInputs(inputs []string, remote, qrInput bool, contents map[string][]byte) (names, localFiles []string, err error) {
+	for _, input := range inputs {
+		if !source.IsRemote(input) {
+			names, localFiles = append(names, input), append(localFiles, input)
+			continue
+		}
+		name := source.DisplayName(input)
+		switch {
+		case !remote:
+			return nil, nil, fmt.Errorf("⚠ `%s` is a URL. Backups are only downloaded with -remote", name)
+		case qrInput:
+			return nil, nil, fmt.Errorf("⚠ -qr reads local directories of images, and cannot be used for the URL `%s`", name)
+		}
+		if _, ok := contents[name]; ok {
+			return nil, nil, fmt.Errorf("⚠ duplicate remote backup `%s`", name)
+		}
+		ui.Printf("Downloading %s …\n", name)
+		content, err := source.FetchRemote(input)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(content) == 0 || content[0] != '{' {
+			return nil, nil, fmt.Errorf("⚠ invalid remote backup `%s`, expecting json", name)
+		}
+		contents[name] = content
+		names = append(names, name)
+	}
+	return names, localFiles, nil
+}
+
+// checkDuplicateInputs ensures that a downloaded backup was not also given as a local copy.
+func checkDuplicateInputs(names []string, contents map[string][]byte) error {
+	all := make(map[string][]byte, len(names))
+	for _, name := range names {
+		content, ok := contents[name]
+		if !ok {
+			var err error
+			if content, err = os.ReadFile(name); err != nil {
+				return fmt.Errorf("⚠ unable to read file `%s`: %s", name, err)
+			}
+		}
+		all[name] = content
+	}
+	return ui.CheckDuplicateContent(names, all)
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchRemoteInputs_Offline(t *testing.T) {
+	contents := map[string][]byte{}
+	names, local, err := fetchRemoteInputs([]string{"./test-files/v2.json", "./test-files/new_single.json"}, false, false, contents)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"./test-files/v2.json", "./test-files/new_single.json"}, names)
+	assert.Equal(t, names, local)
+	assert.Empty(t, contents)
+
+	// URLs are never downloaded without -remote, and the signature is not shown
+	_, _, err = fetchRemoteInputs([]string{"https://bucket.s3.amazonaws.com/v2.json?X-Amz-Signature=secret"}, false, false, contents)
+	if assert.ErrorContains(t, err, "-remote") {
+		assert.NotContains(t, err.Error(), "secret")
+	}
+	_, _, err = fetchRemoteInputs([]string{"https://bucket.s3.amazonaws.com/qr"}, true, true, contents)
+	assert.ErrorContains(t, err, "-qr")
+}
+
+func TestCheckDuplicateInputs(t *testing.T) {
+	content, err := os.ReadFile("./test-files/v2.json")
+	require.NoError(t, err)
+	names := []string{"./test-files/v2.json", "https://bucket.s3.amazonaws.com/v2.json"}
+
+	err = checkDuplicateInputs(names, map[string][]byte{names[1]: content})
+	assert.ErrorContains(t, err, "same backup file")
+	assert.NoError(t, checkDuplicateInputs(names, map[string][]byte{names[1]: []byte(`{"vaults":{}}`)}))
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/observe"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// showSecretsPhrase must be typed before the private keys are printed, so that a recovery re-run from the shell
+// history in front of others does not reveal them by accident.
+const showSecretsPhrase = "show secrets"
+
+// SecretsChallenge is the -confirm-word check before the private keys are shown: the first word of the phrase of one
+// of the backup files, picked at random.
+type SecretsChallenge struct {
+	File string
+	word string
+}
+
+// newSecretsChallenge picks the backup file whose phrase's first word must be entered again.
+func newSecretsChallenge(files []ui.VaultsDataFile) (*SecretsChallenge, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(files))))
+	if err != nil {
+		return nil, err
+	}
+	file := files[i.Int64()]
+	var word string
+	if words := strings.Fields(file.Mnemonics); len(words) > 0 {
+		word = words[0]
+	}
+	return &SecretsChallenge{File: filepath.Base(file.File), word: word}, nil
+}
+
+// Check reports whether the entered word is the first word of the challenged phrase.
+func (c *SecretsChallenge) Check(entered string) bool {
+	entered = strings.ToLower(strings.TrimSpace(entered))
+	return c.word != "" && subtle.ConstantTimeCompare([]byte(entered), []byte(strings.ToLower(c.word))) == 1
+}
+
+// isShowSecretsPhrase reports whether the operator typed the confirmation phrase.
+func isShowSecretsPhrase(typed string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(typed), " "), showSecretsPhrase)
+}
+
+// observerWarning is the banner shown before the private keys are revealed while programs that may capture them, e.g.
+// a recorded video call, are running.
+func observerWarning(observers []observe.Observer) string {
+	lines := make([]string, 0, len(observers)+2)
+	for _, observer := range observers {
+		lines = append(lines, fmt.Sprintf("• %s, a %s", observer.Name, observer.Kind))
+	}
+	lines = append(lines, "", "Stop any screen sharing or recording and close clipboard managers before the keys are shown, or do not show them.",
+		"This check is best effort: programs it does not know are not found.")
+	return ui.WarningBox("Programs that may capture the private keys are running", lines)
+}
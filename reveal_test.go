@@ -0,0 +1,60 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/observe"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsShowSecretsPhrase(t *testing.T) {
+	assert.True(t, isShowSecretsPhrase("show secrets"))
+	assert.True(t, isShowSecretsPhrase("  Show   Secrets\n"))
+	assert.False(t, isShowSecretsPhrase("show"))
+	assert.False(t, isShowSecretsPhrase("yes"))
+	assert.False(t, isShowSecretsPhrase(""))
+}
+
+func TestSecretsChallenge(t *testing.T) {
+	files := []ui.VaultsDataFile{
+		{File: "./test-files/a.json", Mnemonics: "  Abandon ability able about"},
+		{File: "./test-files/b.json", Mnemonics: "zoo zone zero"},
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		challenge, err := newSecretsChallenge(files)
+		require.NoError(t, err)
+		seen[challenge.File] = true
+		switch challenge.File {
+		case "a.json":
+			assert.True(t, challenge.Check("abandon"))
+			assert.True(t, challenge.Check(" ABANDON "))
+			assert.False(t, challenge.Check("zoo"))
+		case "b.json":
+			assert.True(t, challenge.Check("zoo"))
+			assert.False(t, challenge.Check("abandon"))
+		default:
+			t.Fatalf("unexpected file %s", challenge.File)
+		}
+		assert.False(t, challenge.Check(""))
+	}
+	assert.Len(t, seen, 2)
+
+	empty, err := newSecretsChallenge([]ui.VaultsDataFile{{File: "c.json"}})
+	require.NoError(t, err)
+	assert.False(t, empty.Check(""))
+}
+
+func TestObserverWarning(t *testing.T) {
+	warning := observerWarning([]observe.Observer{{Name: "Zoom", Kind: observe.ScreenSharing}, {Name: "CopyQ", Kind: observe.ClipboardManager}})
+	assert.Contains(t, warning, "Programs that may capture the private keys are running")
+	assert.Contains(t, warning, "Zoom, a screen sharing or remote desktop")
+	assert.Contains(t, warning, "CopyQ, a clipboard history manager")
+	assert.Contains(t, warning, "best effort")
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/sweep"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/wif"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// generateRotationKeys creates brand-new keys from the system's CSPRNG. They are never derived from vault data.
+// The EdDSA key is returned in the same scalar format as the recovered EdDSA key, so it works with the same downstream tools.
+func generateRotationKeys(withEdDSA bool) (address string, ecdsaSK, eddsaSK, eddsaPK []byte, err error) {
+	ecPrivKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return "", nil, nil, nil, fmt.Errorf("⚠ could not generate a new ECDSA key: %v", err)
+	}
+	ecdsaSK = ecPrivKey.Serialize()
+	pk := ecPrivKey.PubKey()
+	ecPrivKey.Zero()
+	if _, address, err = getTSSPubKeyForEthereum(pk.X(), pk.Y()); err != nil {
+		return "", nil, nil, nil, err
+	}
+	if !withEdDSA {
+		return address, ecdsaSK, nil, nil, nil
+	}
+
+	// the scalar is drawn directly: edwards.GeneratePrivateKey goes through ecdsa.GenerateKey, which BoringCrypto
+	// builds reject for non-NIST curves
+	d, err := rand.Int(rand.Reader, new(big.Int).Sub(edwards.Edwards().N, big.NewInt(1)))
+	if err != nil {
+		clear(ecdsaSK)
+		return "", nil, nil, nil, fmt.Errorf("⚠ could not generate a new EdDSA key: %v", err)
+	}
+	eddsaSK = leftPadTo32Bytes(d.Add(d, big.NewInt(1)))
+	_, edPK, err := edwards.PrivKeyFromScalar(eddsaSK)
+	if err != nil {
+		clear(ecdsaSK)
+		clear(eddsaSK)
+		return "", nil, nil, nil, err
+	}
+	return address, ecdsaSK, eddsaSK, edPK.SerializeCompressed(), nil
+}
+
+// loadSweepParams reads the chain state of the recovered addresses to build the sweep transactions from.
+func loadSweepParams(file string) (*sweep.Params, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ unable to read the sweep params file `%s`: %s", file, err)
+	}
+	params := new(sweep.Params)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(params); err != nil {
+		return nil, fmt.Errorf("⚠ the sweep params file `%s` is not a JSON object with \"ethereum\" and/or \"bitcoin\" params: %s", file, err)
+	}
+	if params.Ethereum == nil && params.Bitcoin == nil {
+		return nil, fmt.Errorf("⚠ the sweep params file `%s` has neither \"ethereum\" nor \"bitcoin\" params", file)
+	}
+	return params, nil
+}
+
+// printRotationPlan generates fresh keys and prints them with the addresses to sweep funds from and to, chain by chain.
+// The tool is offline and cannot know balances, fees or account nonces, so the unsigned Ethereum and Bitcoin sweep
+// transactions are only built from the chain state given in params, if any. They are signed in a wallet with the
+// recovered key.
+func printRotationPlan(out io.Writer, recoveredECSK, recoveredEdPK []byte, chains ChainSelection, params *sweep.Params) error {
+	address, ecSK, edSK, edPK, err := generateRotationKeys(recoveredEdPK != nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		clear(ecSK)
+		clear(edSK)
+	}()
+
+	fmt.Fprintf(out, "\n%s%s KEY ROTATION %s\n", ui.AnsiCodes["invertOn"], ui.AnsiCodes["bold"], ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "\nA brand-new key has been generated for you. It is NOT derived from your vault. Write it down and keep safe.\n")
+	fmt.Fprintf(out, "New Ethereum address: %s%s%s\n", ui.AnsiCodes["bold"], address, ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "New ECDSA private key (for ETH/MetaMask, Tron/TronLink): %s%s%s\n",
+		ui.AnsiCodes["bold"], hex.EncodeToString(ecSK), ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "New mainnet WIF (for BTC/Electrum Wallet): %s%s%s\n", ui.AnsiCodes["bold"],
+		wif.ToBitcoinWIF(ecSK, false, true), ui.AnsiCodes["reset"])
+	if edSK != nil {
+		fmt.Fprintf(out, "New EdDSA/Ed25519 private key (for XRPL, SOL, TAO, etc): %s%s%s\n",
+			ui.AnsiCodes["bold"], hex.EncodeToString(edSK), ui.AnsiCodes["reset"])
+		fmt.Fprintf(out, "New EdDSA/Ed25519 public key (for XRPL tool): %s%s%s\n",
+			ui.AnsiCodes["bold"], hex.EncodeToString(edPK), ui.AnsiCodes["reset"])
+	}
+
+	// both keys have the same chains, so their addresses line up
+	recovered := chains.Addresses(vaultAddresses(recoveredECSK, recoveredEdPK))
+	rotated := chains.Addresses(vaultAddresses(ecSK, edPK))
+	fmt.Fprintf(out, "\nSweep all funds off the recovered key as soon as possible, from and to these addresses:\n")
+	for i, a := range recovered {
+		fmt.Fprintf(out, "  %-34s %s → %s%s%s\n", a.Chain+":", a.Address, ui.AnsiCodes["bold"], rotated[i].Address, ui.AnsiCodes["reset"])
+	}
+
+	if params == nil {
+		fmt.Fprintf(out, "\nSet -sweep-params to also build the unsigned Ethereum and Bitcoin sweep transactions. ")
+		fmt.Fprintf(out, "Otherwise, import the recovered key into your wallet and send the full balance of each asset to the new address.\n")
+		return nil
+	}
+	if params.Ethereum != nil && chains.Shows("ethereum") {
+		tx, err := sweep.Ethereum(*params.Ethereum, common.HexToAddress(address))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\nUnsigned Ethereum sweep (chain ID %d, nonce %d) of %s wei, paying up to %s wei in fees:\n",
+			params.Ethereum.ChainID, params.Ethereum.Nonce, tx.Value, tx.Fee)
+		fmt.Fprintf(out, "Unsigned transaction: %s%s%s\n", ui.AnsiCodes["bold"], hexutil.Encode(tx.Unsigned), ui.AnsiCodes["reset"])
+		fmt.Fprintf(out, "Signing hash: %s\n", hexutil.Encode(tx.SigningHash))
+	}
+	if params.Bitcoin != nil && chains.Shows("bitcoin") {
+		fromPK, _ := hex.DecodeString(ecdsaPublicKeyDetails(recoveredECSK).Compressed)
+		toPK, _ := hex.DecodeString(ecdsaPublicKeyDetails(ecSK).Compressed)
+		tx, err := sweep.Bitcoin(*params.Bitcoin, fromPK, toPK)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\nUnsigned Bitcoin mainnet (P2WPKH) sweep of %d sat, paying %d sat in fees, as a PSBT to load into Electrum or Sparrow:\n",
+			tx.Amount, tx.Fee)
+		fmt.Fprintf(out, "%s%s%s\n", ui.AnsiCodes["bold"], base64.StdEncoding.EncodeToString(tx.PSBT), ui.AnsiCodes["reset"])
+	}
+	fmt.Fprintf(out, "\nCheck the amounts and the new addresses before signing with the recovered key. Tokens and the other chains are swept from your wallet.\n")
+	return nil
+}
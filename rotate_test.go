@@ -0,0 +1,64 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/sweep"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotate_PrintRotationPlan(t *testing.T) {
+	ecSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	params := &sweep.Params{
+		Ethereum: &sweep.EthereumParams{ChainID: 1, Nonce: 3, BalanceWei: "1000000000000000000", MaxFeePerGasWei: "20000000000", MaxPriorityFeePerGasWei: "1000000000"},
+		Bitcoin: &sweep.BitcoinParams{FeeRateSatPerVByte: 5, UTXOs: []sweep.UTXO{
+			{TxID: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", Vout: 0, ValueSat: 100000},
+		}},
+	}
+	out := new(bytes.Buffer)
+	require.NoError(t, printRotationPlan(out, ecSK, nil, ChainSelection{"ethereum": true, "bitcoin": true}, params))
+
+	// the recovered addresses are mapped to the new ones, for the selected chains only
+	assert.Regexp(t, `Ethereum & EVM chains:\s+0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf → \S*0x`, out.String())
+	assert.Regexp(t, `Bitcoin mainnet \(P2WPKH\):\s+bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4 → \S*bc1q`, out.String())
+	assert.NotContains(t, out.String(), "Tron:")
+	assert.Contains(t, out.String(), "Unsigned transaction: "+ui.AnsiCodes["bold"]+"0x02")
+	assert.Contains(t, out.String(), "of 999580000000000000 wei, paying up to 420000000000000 wei in fees")
+	assert.Contains(t, out.String(), "sweep of 99450 sat, paying 550 sat in fees")
+	assert.Contains(t, out.String(), ui.AnsiCodes["bold"]+"cHNidP8B", "a base64 PSBT")
+
+	out.Reset()
+	require.NoError(t, printRotationPlan(out, ecSK, nil, nil, nil))
+	assert.Contains(t, out.String(), "Set -sweep-params")
+	assert.NotContains(t, out.String(), "Unsigned")
+
+	params.Ethereum.BalanceWei = "1"
+	assert.ErrorContains(t, printRotationPlan(new(bytes.Buffer), ecSK, nil, nil, params), "nothing to sweep")
+}
+
+func TestRotate_LoadSweepParams(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sweep.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"bitcoin": {"feeRateSatPerVByte": 2, "utxos": [{"txid": "00", "vout": 1, "valueSat": 5000}]}}`), 0600))
+	params, err := loadSweepParams(file)
+	require.NoError(t, err)
+	assert.Nil(t, params.Ethereum)
+	assert.Equal(t, []sweep.UTXO{{TxID: "00", Vout: 1, ValueSat: 5000}}, params.Bitcoin.UTXOs)
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"ethereum": {"balance": "1"}}`), 0600))
+	_, err = loadSweepParams(file)
+	assert.ErrorContains(t, err, "not a JSON object", "unknown fields are typos, not ignored")
+	require.NoError(t, os.WriteFile(file, []byte(`{}`), 0600))
+	_, err = loadSweepParams(file)
+	assert.ErrorContains(t, err, "neither")
+}
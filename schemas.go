@@ -0,0 +1,94 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/schema"
+)
+
+// The schemas of the backup file and of a decrypted vault. Only the fields that the tool reads are checked, so that
+// fields added by newer exports are not refused.
+var (
+	cipheredVaultSchema = &schema.Node{
+		Kind: schema.Object,
+		Fields: map[string]*schema.Node{
+			"ciphertext": {Kind: schema.String, Format: base64Format},
+			"cipherparams": {
+				Kind: schema.Object,
+				Fields: map[string]*schema.Node{
+					"iv":  {Kind: schema.String, Format: hexFormat},
+					"tag": {Kind: schema.String, Format: hexFormat},
+				},
+				Required: []string{"iv", "tag"},
+			},
+			"cipher": {Kind: schema.String},
+			"hash":   {Kind: schema.String, Format: hexFormat},
+		},
+		Required: []string{"ciphertext", "cipherparams"},
+	}
+
+	savedDataSchema = &schema.Node{
+		Kind: schema.Object,
+		Fields: map[string]*schema.Node{
+			"timestamp": {Kind: schema.String},
+			"vaults": {
+				Kind: schema.Map,
+				Elem: &schema.Node{Kind: schema.Map, Key: reshareNonceKey, Elem: cipheredVaultSchema},
+			},
+			"kdf":           {Kind: schema.String},
+			"mnemonicCheck": cipheredVaultSchema,
+		},
+		Required: []string{"vaults"},
+	}
+
+	clearVaultSchema = &schema.Node{
+		Kind: schema.Object,
+		Fields: map[string]*schema.Node{
+			"name":      {Kind: schema.String},
+			"threshold": {Kind: schema.Integer},
+			"shares":    {Kind: schema.Array, Elem: &schema.Node{Kind: schema.String}, Nullable: true},
+			"curves": {
+				Kind: schema.Array,
+				Elem: &schema.Node{
+					Kind: schema.Object,
+					Fields: map[string]*schema.Node{
+						"algorithm": {Kind: schema.String},
+						"curve":     {Kind: schema.String},
+						"publicKey": {Kind: schema.String, Format: hexFormat},
+						"shares":    {Kind: schema.Array, Elem: &schema.Node{Kind: schema.String}, Nullable: true},
+					},
+					Required: []string{"algorithm"},
+				},
+				Nullable: true,
+			},
+		},
+	}
+)
+
+func hexFormat(s string) error {
+	if _, err := hex.DecodeString(s); err != nil {
+		return errors.New("not hex")
+	}
+	return nil
+}
+
+func base64Format(s string) error {
+	if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return errors.New("not base64")
+	}
+	return nil
+}
+
+func reshareNonceKey(key string) error {
+	if nonce, err := strconv.Atoi(key); err != nil || nonce < 0 {
+		return errors.New("not a reshare number")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/schema"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemas_TestFiles(t *testing.T) {
+	files, err := filepath.Glob("./test-files/*.json")
+	require.NoError(t, err)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NoError(t, schema.Validate(content, savedDataSchema), file)
+	}
+}
+
+func TestSchemas_MalformedBackup(t *testing.T) {
+	content, err := os.ReadFile("./test-files/new_single.json")
+	require.NoError(t, err)
+	// corrupt the iv of the first reshare of the vault
+	vaultIV := regexp.MustCompile(`"phrot42ltzawmn7nrm7mqvl5":\s*\{\s*"\d+":\s*\{[^}]*"iv":\s*"`)
+	loc := vaultIV.FindIndex(content)
+	require.NotNil(t, loc)
+	content[loc[1]] = 'x'
+
+	_, err = loadSavedData(ui.VaultsDataFile{File: "single.json", Content: content})
+	require.Error(t, err)
+	assert.Regexp(t, `^⚠ single.json is not a valid backup file: vaults\.phrot42ltzawmn7nrm7mqvl5\.\d+\.cipherparams\.iv: not hex at offset \d+$`, err.Error())
+
+	file := filepath.Join(t.TempDir(), "single.json")
+	require.NoError(t, os.WriteFile(file, content, 0600))
+	stats := backupFileStats(1, file)
+	assert.Regexp(t, `^vaults\.\*\.\*\.cipherparams\.iv: not hex at offset \d+$`, stats.ParseError)
+}
+
+func TestSchemas_ReshareNonce(t *testing.T) {
+	err := schema.Validate([]byte(`{"vaults": {"abc123": {"latest": {}}}}`), savedDataSchema)
+	assert.EqualError(t, err, "vaults.abc123.latest: not a reshare number at offset 23")
+}
+
+func TestSchemas_ClearVault(t *testing.T) {
+	assert.NoError(t, schema.Validate([]byte(`{"name": "Treasury", "threshold": 2, "shares": null, "curves": [{"algorithm": "ecdsa", "shares": ["a"]}]}`), clearVaultSchema))
+
+	err := schema.Validate([]byte(`{"name": "Treasury", "threshold": "2"}`), clearVaultSchema)
+	assert.EqualError(t, err, "threshold: expected an integer, got a string at offset 34")
+	err = schema.Validate([]byte(`{"curves": [{"algorithm": "eddsa", "shares": [1]}]}`), clearVaultSchema)
+	assert.True(t, strings.HasPrefix(err.Error(), "curves.0.shares.0: expected a string, got a number"), err.Error())
+}
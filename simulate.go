@@ -0,0 +1,182 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// simulationPrime is the small field of the -simulate polynomials, so that every number fits on a line. Real vault
+// shares are points of the same kind of polynomial over a 256-bit field.
+const simulationPrime = 7919
+
+type (
+	// QuorumSimulation replays with made-up numbers how the shares of a vault combine, with its real quorum, parties
+	// and held shares. Nothing in it is derived from the backup files' secrets.
+	QuorumSimulation struct {
+		Quorum int
+		Secret int64
+		// Coefficients of the polynomial f, from the constant term f(0) = Secret
+		Coefficients []int64
+		Parties      []SimulatedShare
+		// Used are the held shares interpolated, at most Quorum of them
+		Used          []SimulatedShare
+		Reconstructed int64
+		// Mixed is the result of interpolating Used with its last share taken from a reshared polynomial instead
+		Mixed int64
+	}
+	SimulatedShare struct {
+		Party   int
+		ShareID string
+		Files   []string
+		Y       int64
+		// Reshared is the party's point on the polynomial of another reshare nonce, with the same secret
+		Reshared int64
+	}
+)
+
+// simulateQuorum makes up a secret and polynomials of the vault's degree, gives each party of the plan a point on
+// them, and interpolates the held ones.
+func simulateQuorum(plan RecoveryPlan, rng *rand.Rand) QuorumSimulation {
+	quorum := max(plan.Quorum, 1)
+	sim := QuorumSimulation{Quorum: quorum, Secret: rng.Int64N(simulationPrime)}
+	sim.Coefficients = randomPolynomial(sim.Secret, quorum, rng)
+	reshared := randomPolynomial(sim.Secret, quorum, rng)
+	for _, share := range plan.Shares {
+		if share.Unknown {
+			continue
+		}
+		x := int64(len(sim.Parties) + 1)
+		party := SimulatedShare{Party: int(x), ShareID: share.ShareID, Files: share.Files, Y: evalPolynomial(sim.Coefficients, x),
+			Reshared: evalPolynomial(reshared, x)}
+		// the reshared point must differ, or mixing it in would not show anything
+		for quorum > 1 && party.Reshared == party.Y {
+			reshared = randomPolynomial(sim.Secret, quorum, rng)
+			party.Reshared = evalPolynomial(reshared, x)
+		}
+		sim.Parties = append(sim.Parties, party)
+		if len(share.Files) > 0 && len(sim.Used) < quorum {
+			sim.Used = append(sim.Used, party)
+		}
+	}
+	if len(sim.Used) > 0 {
+		points := make([][2]int64, len(sim.Used))
+		for i, share := range sim.Used {
+			points[i] = [2]int64{int64(share.Party), share.Y}
+		}
+		sim.Reconstructed = lagrangeAtZero(points)
+		last := sim.Used[len(sim.Used)-1]
+		points[len(points)-1][1] = last.Reshared
+		sim.Mixed = lagrangeAtZero(points)
+	}
+	return sim
+}
+
+func randomPolynomial(secret int64, quorum int, rng *rand.Rand) []int64 {
+	coefficients := []int64{secret}
+	for i := 1; i < quorum; i++ {
+		coefficients = append(coefficients, 1+rng.Int64N(simulationPrime-1))
+	}
+	return coefficients
+}
+
+func evalPolynomial(coefficients []int64, x int64) int64 {
+	y := int64(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = (y*x + coefficients[i]) % simulationPrime
+	}
+	return y
+}
+
+// lagrangeAtZero interpolates the polynomial through the points and returns its value at 0, as recovery does with the
+// shares.
+func lagrangeAtZero(points [][2]int64) int64 {
+	result := int64(0)
+	for i, pi := range points {
+		num, den := int64(1), int64(1)
+		for j, pj := range points {
+			if i == j {
+				continue
+			}
+			num = num * (simulationPrime - pj[0]) % simulationPrime
+			den = den * ((pi[0] - pj[0] + simulationPrime) % simulationPrime) % simulationPrime
+		}
+		term := pi[1] * num % simulationPrime * modInverse(den) % simulationPrime
+		result = (result + term) % simulationPrime
+	}
+	return result
+}
+
+// modInverse uses Fermat's little theorem, as simulationPrime is prime.
+func modInverse(a int64) int64 {
+	result, base, exp := int64(1), a%simulationPrime, int64(simulationPrime-2)
+	for exp > 0 {
+		if exp&1 == 1 {
+			result = result * base % simulationPrime
+		}
+		base = base * base % simulationPrime
+		exp >>= 1
+	}
+	return result
+}
+
+func formatPolynomial(coefficients []int64) string {
+	terms := []string{fmt.Sprint(coefficients[0])}
+	for i, c := range coefficients[1:] {
+		switch i + 1 {
+		case 1:
+			terms = append(terms, fmt.Sprintf("%d·x", c))
+		default:
+			terms = append(terms, fmt.Sprintf("%d·x^%d", c, i+1))
+		}
+	}
+	return strings.Join(terms, " + ")
+}
+
+func printQuorumSimulation(out io.Writer, sim QuorumSimulation) {
+	fmt.Fprintf(out, "\n%s%sSIMULATION WITH MADE-UP NUMBERS, NOT YOUR KEYS%s\n\n", ui.AnsiCodes["bold"], ui.AnsiCodes["invertOn"], ui.AnsiCodes["reset"])
+	fmt.Fprintf(out, "The vault key is a secret number s. At keygen, a random polynomial f of degree %d (the quorum %d, minus 1) "+
+		"was chosen with f(0) = s, and each party got one point of it as its share. Here is the same with small numbers, modulo %d:\n\n",
+		sim.Quorum-1, sim.Quorum, simulationPrime)
+	fmt.Fprintf(out, "  s = %d    f(x) = %s\n\n", sim.Secret, formatPolynomial(sim.Coefficients))
+	for _, party := range sim.Parties {
+		status, files := "[ ]", "missing"
+		if len(party.Files) > 0 {
+			status, files = "["+ui.Plain("✓")+"]", strings.Join(party.Files, ", ")
+		}
+		fmt.Fprintf(out, " %s party %d  share %s  point (%d, %d)  %s\n", status, party.Party, shortShareID(party.ShareID), party.Party, party.Y, files)
+	}
+
+	used := make([]string, len(sim.Used))
+	for i, share := range sim.Used {
+		used[i] = fmt.Sprint(share.Party)
+	}
+	switch {
+	case len(sim.Used) == 0:
+		fmt.Fprintf(out, "\nYou hold none of the %d points needed, so nothing can be computed.\n", sim.Quorum)
+	case len(sim.Used) >= sim.Quorum:
+		fmt.Fprintf(out, "\nAny %d points determine a polynomial of degree %d, so its value at 0, the secret, can be computed "+
+			"(Lagrange interpolation). Using the points of parties %s: s = %d, which is right.\n",
+			sim.Quorum, sim.Quorum-1, strings.Join(used, ", "), sim.Reconstructed)
+	default:
+		fmt.Fprintf(out, "\nYou hold %d of the %d points needed. Through %d points pass polynomials of degree %d for every possible "+
+			"secret, so they reveal nothing about it: interpolating the points of parties %s anyway gives %d instead of %d. "+
+			"This is why the tool stops when there are too few shares.\n",
+			len(sim.Used), sim.Quorum, len(sim.Used), sim.Quorum-1, strings.Join(used, ", "), sim.Reconstructed, sim.Secret)
+	}
+
+	if sim.Quorum > 1 && len(sim.Used) >= sim.Quorum {
+		last := sim.Used[len(sim.Used)-1]
+		fmt.Fprintf(out, "\nA reshare gives every party a point of a new polynomial with the same secret, and the old points stop matching. "+
+			"If party %d's file held an older reshare, with point (%d, %d), the same computation gives %d instead of %d. "+
+			"This is why the backup files must be at the same reshare nonce, and why the tool may ask for -nonce and -threshold "+
+			"when they are not.\n", last.Party, last.Party, last.Reshared, sim.Mixed, sim.Secret)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLagrangeAtZero(t *testing.T) {
+	f := []int64{1234, 567, 89}
+	points := [][2]int64{{1, evalPolynomial(f, 1)}, {3, evalPolynomial(f, 3)}, {4, evalPolynomial(f, 4)}}
+	assert.Equal(t, int64(1234), lagrangeAtZero(points))
+	assert.Equal(t, int64(1234+567+89), evalPolynomial(f, 1))
+	assert.Equal(t, int64(1), 5*modInverse(5)%simulationPrime)
+	assert.Equal(t, "1234 + 567·x + 89·x^2", formatPolynomial(f))
+}
+
+func TestSimulateQuorum(t *testing.T) {
+	plan := RecoveryPlan{Quorum: 3, Shares: []PlannedShare{
+		{ShareID: "111", Files: []string{"a.json"}},
+		{ShareID: "222"},
+		{ShareID: "333", Files: []string{"c.json"}},
+		{ShareID: "444", Files: []string{"d.json", "d2.json"}},
+		{ShareID: "999", Files: []string{"old.json"}, Unknown: true},
+	}}
+	for seed := uint64(0); seed < 20; seed++ {
+		sim := simulateQuorum(plan, rand.New(rand.NewPCG(seed, seed)))
+		require.Len(t, sim.Parties, 4)
+		require.Len(t, sim.Used, 3)
+		assert.Equal(t, []int{1, 3, 4}, []int{sim.Used[0].Party, sim.Used[1].Party, sim.Used[2].Party})
+		assert.Equal(t, sim.Secret, sim.Reconstructed)
+		assert.NotEqual(t, sim.Secret, sim.Mixed)
+	}
+
+	sim := simulateQuorum(plan, rand.New(rand.NewPCG(1, 2)))
+	out := new(bytes.Buffer)
+	printQuorumSimulation(out, sim)
+	assert.Contains(t, out.String(), "MADE-UP NUMBERS, NOT YOUR KEYS")
+	assert.Contains(t, out.String(), "party 2  share 222")
+	assert.Contains(t, out.String(), "missing")
+	assert.Contains(t, out.String(), "d.json, d2.json")
+	assert.Contains(t, out.String(), "Using the points of parties 1, 3, 4")
+	assert.Contains(t, out.String(), "older reshare")
+	assert.NotContains(t, out.String(), "999")
+
+	plan.Shares[2].Files, plan.Shares[3].Files = nil, nil
+	sim = simulateQuorum(plan, rand.New(rand.NewPCG(1, 2)))
+	require.Len(t, sim.Used, 1)
+	out.Reset()
+	printQuorumSimulation(out, sim)
+	assert.Contains(t, out.String(), "You hold 1 of the 3 points needed")
+	assert.NotContains(t, out.String(), "older reshare")
+
+	single := simulateQuorum(RecoveryPlan{Quorum: 1, Shares: []PlannedShare{{ShareID: "1", Files: []string{"s.json"}}}}, rand.New(rand.NewPCG(3, 4)))
+	assert.Equal(t, single.Secret, single.Reconstructed)
+	assert.Equal(t, single.Secret, single.Parties[0].Y)
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// verboseOutput is set by -verbose: the size of every inflated share is printed as well as the summary.
+var verboseOutput bool
+
+type (
+	// ShareStats receives the sizes of the compressed V2 shares of a vault as they are inflated.
+	ShareStats interface {
+		Inflated(vaultID, shareID string, compressed, inflated int)
+	}
+
+	// InflationStats sums up the inflated shares of each vault, to report them in one line instead of one per share.
+	InflationStats struct {
+		verbose bool
+		vaults  map[string]*InflationSummary
+	}
+
+	// InflationSummary holds no IDs, so that it is kept as is when a log is redacted for a support bundle.
+	InflationSummary struct {
+		Shares          int
+		CompressedBytes int
+		InflatedBytes   int
+	}
+)
+
+func newInflationStats(verbose bool) *InflationStats {
+	return &InflationStats{verbose: verbose, vaults: make(map[string]*InflationSummary)}
+}
+
+func (s *InflationStats) Inflated(vaultID, shareID string, compressed, inflated int) {
+	summary, ok := s.vaults[vaultID]
+	if !ok {
+		summary = new(InflationSummary)
+		s.vaults[vaultID] = summary
+	}
+	summary.Shares++
+	summary.CompressedBytes += compressed
+	summary.InflatedBytes += inflated
+	if s.verbose {
+		ui.Printf("Processing V2 share %s.\t %.1f KB → %.1f KB\n", shareID, float64(compressed)/1024, float64(inflated)/1024)
+	}
+}
+
+// Summary of the inflated shares of a vault. It is empty if the vault has no V2 shares.
+func (s *InflationStats) Summary(vaultID string) InflationSummary {
+	if summary, ok := s.vaults[vaultID]; ok {
+		return *summary
+	}
+	return InflationSummary{}
+}
+
+// Ratio is how many times larger the shares are once inflated.
+func (s InflationSummary) Ratio() float64 {
+	if s.CompressedBytes == 0 {
+		return 0
+	}
+	return float64(s.InflatedBytes) / float64(s.CompressedBytes)
+}
+
+func (s InflationSummary) String() string {
+	return fmt.Sprintf("Inflated %d V2 shares: %.1f KB → %.1f KB (%.1fx).", s.Shares,
+		float64(s.CompressedBytes)/1024, float64(s.InflatedBytes)/1024, s.Ratio())
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/support"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInflationStats_Summary(t *testing.T) {
+	stats := newInflationStats(false)
+	stats.Inflated("vault-1", "1", 1024, 3072)
+	stats.Inflated("vault-1", "2", 1024, 4096)
+	stats.Inflated("vault-2", "3", 512, 512)
+
+	summary := stats.Summary("vault-1")
+	assert.Equal(t, InflationSummary{Shares: 2, CompressedBytes: 2048, InflatedBytes: 7168}, summary)
+	assert.InDelta(t, 3.5, summary.Ratio(), 0.001)
+	assert.Equal(t, "Inflated 2 V2 shares: 2.0 KB → 7.0 KB (3.5x).", summary.String())
+	assert.Equal(t, 1, stats.Summary("vault-2").Shares)
+
+	empty := stats.Summary("vault-3")
+	assert.Zero(t, empty.Shares)
+	assert.Zero(t, empty.Ratio())
+}
+
+func TestInflationStats_SummarySurvivesRedaction(t *testing.T) {
+	// the summary is what a support bundle -log shows of the shares, so it must not look like a secret
+	summary := InflationSummary{Shares: 36, CompressedBytes: 389120, InflatedBytes: 1310720}
+	assert.Equal(t, summary.String(), support.Redact(summary.String()))
+}
+
+func TestInflateSharesForCurve_ReportsSizes(t *testing.T) {
+	shareID := big.NewInt(42)
+	share, err := encodeFixtureShare(shareID, struct {
+		ShareID *big.Int `json:"shareID"`
+	}{shareID}, true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	stats := newInflationStats(false)
+	_, err = inflateSharesForCurve[struct{}]([]string{share}, "vault-1", stats)
+	assert.NoError(t, err)
+	summary := stats.Summary("vault-1")
+	assert.Equal(t, 1, summary.Shares)
+	assert.Positive(t, summary.CompressedBytes)
+	assert.Positive(t, summary.InflatedBytes)
+}
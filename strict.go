@@ -0,0 +1,63 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// WritePolicy is the -strict-writes list of the outputs named on the command line. Writing anything else is an error.
+type WritePolicy struct {
+	files map[string]bool
+	dirs  map[string]bool
+}
+
+// strictWrites is set for the whole run by -strict-writes. When nil, the tool may write where its options default to.
+var strictWrites *WritePolicy
+
+// newWritePolicy allows writing the given files, and files directly in the given directories.
+func newWritePolicy(files, dirs []string) *WritePolicy {
+	p := &WritePolicy{files: make(map[string]bool, len(files)), dirs: make(map[string]bool, len(dirs))}
+	for _, file := range files {
+		p.files[absPath(file)] = true
+	}
+	for _, dir := range dirs {
+		p.dirs[absPath(dir)] = true
+	}
+	return p
+}
+
+// Check is called before every file or directory the tool creates. It allows everything if strict writes are off.
+func (p *WritePolicy) Check(path string) error {
+	if p == nil {
+		return nil
+	}
+	abs := absPath(path)
+	if p.files[abs] || p.dirs[abs] || p.dirs[filepath.Dir(abs)] {
+		return nil
+	}
+	return fmt.Errorf("⚠ -strict-writes: refusing to write `%s`, which is not an output named on the command line", path)
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// strictOutputsList describes the allowed outputs of -strict-writes, to print at the start of the run.
+func strictOutputsList(files, dirs []string) string {
+	if len(files) == 0 && len(dirs) == 0 {
+		return "none"
+	}
+	list := append([]string{}, files...)
+	for _, dir := range dirs {
+		list = append(list, filepath.Join(dir, "*"))
+	}
+	return strings.Join(list, ", ")
+}
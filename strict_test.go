@@ -0,0 +1,42 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictWrites(t *testing.T) {
+	dir := t.TempDir()
+	named := filepath.Join(dir, "wallet-1a2b3c4d.json")
+	shares := filepath.Join(dir, "shares")
+
+	var off *WritePolicy
+	assert.NoError(t, off.Check(filepath.Join(dir, "anything.json")))
+
+	policy := newWritePolicy([]string{named}, []string{shares})
+	assert.NoError(t, policy.Check(named))
+	assert.NoError(t, policy.Check(shares))
+	assert.NoError(t, policy.Check(filepath.Join(shares, "vault-party-1.json")))
+	assert.ErrorContains(t, policy.Check(filepath.Join(dir, "wallet.json")), "not an output named on the command line")
+	assert.Error(t, policy.Check(filepath.Join(shares, "nested", "vault-party-1.json")))
+
+	// every write of the tool goes through the policy while it is set
+	strictWrites = policy
+	t.Cleanup(func() { strictWrites = nil })
+	require.NoError(t, writeNewFile(named, []byte("{}")))
+	unexpected := filepath.Join(dir, "recovery-vault.zip")
+	assert.Error(t, writeNewFile(unexpected, []byte("{}")))
+	_, err := os.Stat(unexpected)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Equal(t, "none", strictOutputsList(nil, nil))
+	assert.Equal(t, named+", "+filepath.Join(shares, "*"), strictOutputsList([]string{named}, []string{shares}))
+}
@@ -0,0 +1,239 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// RecoverySummary is the -export-summary document of a recovery, for the incident records. It is built from the
+// -output json result without secrets, so it can be carried off the air-gapped machine.
+type RecoverySummary struct {
+	Result      RecoveryResult
+	RecoveredAt time.Time
+	SessionID   string
+	Label       string
+	ToolVersion string
+	// Operator and Host are the OS account and machine that ran the recovery
+	Operator    string
+	Host        string
+	BackupFiles []string
+}
+
+// validateSummaryFile checks the -export-summary filename, whose extension picks the format.
+func validateSummaryFile(filename string) error {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".md", ".html":
+		return nil
+	}
+	return fmt.Errorf("⚠ -export-summary writes Markdown or HTML, so the filename must end with .md or .html: %s", filename)
+}
+
+// recoverySummary collects the summary of a recovery. The keys and BIP85 phrases are always left out of its result.
+func recoverySummary(result RecoveryResult, label string, backupFiles []string, recoveredAt time.Time) RecoverySummary {
+	result.SecretsShown, result.ECDSA, result.EdDSA = false, nil, nil
+	if result.BIP85 != nil {
+		bip85 := *result.BIP85
+		bip85.RootXPRV, bip85.Seeds = "", make([]ResultBIP85Child, 0, len(result.BIP85.Seeds))
+		for _, seed := range result.BIP85.Seeds {
+			bip85.Seeds = append(bip85.Seeds, ResultBIP85Child{Index: seed.Index, Path: seed.Path})
+		}
+		result.BIP85 = &bip85
+	}
+	summary := RecoverySummary{
+		Result:      result,
+		RecoveredAt: recoveredAt.UTC(),
+		SessionID:   ui.SessionID,
+		Label:       label,
+		ToolVersion: ui.Version,
+		Operator:    "unknown",
+		Host:        "unknown",
+	}
+	if u, err := user.Current(); err == nil {
+		summary.Operator = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		summary.Host = host
+	}
+	for _, file := range backupFiles {
+		summary.BackupFiles = append(summary.BackupFiles, filepath.Base(file))
+	}
+	return summary
+}
+
+// summaryMarkdown is the Markdown summary. The blanks are filled in by hand for the incident records.
+var summaryMarkdown = template.Must(template.New("summary.md").Funcs(template.FuncMap{"cell": markdownCell}).Parse(`# Vault recovery summary
+
+This summary holds no private keys or phrases.
+
+| | |
+|---|---|
+| Vault | {{cell .Result.VaultName}} |
+| Vault ID | ` + "`{{.Result.VaultID}}`" + ` |
+| Ethereum address | ` + "`{{.Result.Address}}`" + ` |
+| Recovered at | {{.RecoveredAt.Format "2006-01-02 15:04:05 MST"}} |
+| Session ID | {{.SessionID}} |
+{{- if .Label}}
+| Label | {{cell .Label}} |
+{{- end}}
+| Operator | {{cell .Operator}} on {{cell .Host}} |
+| Tool version | recovery-tool {{.ToolVersion}} |
+| Backup files | {{range $i, $f := .BackupFiles}}{{if $i}}, {{end}}{{cell $f}}{{end}} |
+{{- if .Result.Chains}}
+| Chains | {{range $i, $c := .Result.Chains}}{{if $i}}, {{end}}{{$c}}{{end}} |
+{{- end}}
+
+## Addresses
+
+| Chain | Address | Key |
+|---|---|---|
+{{- range .Result.Addresses}}
+| {{.Chain}} | ` + "`{{.Address}}`" + ` | {{.Key}} |
+{{- end}}
+{{- with .Result.BIP85}}
+
+## BIP85 child seeds
+
+{{.Words}} word seeds issued from the BIP85 root with fingerprint ` + "`{{.RootFingerprint}}`" + `. Record which system each index was issued to.
+
+| Index | Path | Issued to |
+|---|---|---|
+{{- range .Seeds}}
+| {{.Index}} | ` + "`{{.Path}}`" + ` | |
+{{- end}}
+{{- end}}
+
+## Written files
+{{range .Result.WrittenFiles}}
+- {{.}}
+{{- else}}
+None.
+{{- end}}
+
+## Incident record
+
+- Reason for the recovery: ________________
+- Approved by: ________________
+- Witnesses: ________________
+
+## Next steps
+
+- [ ] Confirm the addresses above are the vault's addresses on the platform or an explorer.
+- [ ] Sweep the funds of every address to new keys, as the recovered keys were exposed on this machine.
+- [ ] Securely delete the written files that hold private keys once the funds are swept.
+- [ ] Store the backup files and phrases back in their safe places, or rotate them.
+- [ ] File this summary with the incident records.
+`))
+
+// summaryHTML is the HTML summary, with the same content as the Markdown one.
+var summaryHTML = htmltemplate.Must(htmltemplate.New("summary.html").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Vault recovery summary: {{.Result.VaultID}}</title></head>
+<body>
+<h1>Vault recovery summary</h1>
+<p>This summary holds no private keys or phrases.</p>
+<table>
+<tr><th>Vault</th><td>{{.Result.VaultName}}</td></tr>
+<tr><th>Vault ID</th><td><code>{{.Result.VaultID}}</code></td></tr>
+<tr><th>Ethereum address</th><td><code>{{.Result.Address}}</code></td></tr>
+<tr><th>Recovered at</th><td>{{.RecoveredAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+<tr><th>Session ID</th><td>{{.SessionID}}</td></tr>
+{{- if .Label}}
+<tr><th>Label</th><td>{{.Label}}</td></tr>
+{{- end}}
+<tr><th>Operator</th><td>{{.Operator}} on {{.Host}}</td></tr>
+<tr><th>Tool version</th><td>recovery-tool {{.ToolVersion}}</td></tr>
+<tr><th>Backup files</th><td>{{range $i, $f := .BackupFiles}}{{if $i}}, {{end}}{{$f}}{{end}}</td></tr>
+{{- if .Result.Chains}}
+<tr><th>Chains</th><td>{{range $i, $c := .Result.Chains}}{{if $i}}, {{end}}{{$c}}{{end}}</td></tr>
+{{- end}}
+</table>
+<h2>Addresses</h2>
+<table>
+<tr><th>Chain</th><th>Address</th><th>Key</th></tr>
+{{- range .Result.Addresses}}
+<tr><td>{{.Chain}}</td><td><code>{{.Address}}</code></td><td>{{.Key}}</td></tr>
+{{- end}}
+</table>
+{{- with .Result.BIP85}}
+<h2>BIP85 child seeds</h2>
+<p>{{.Words}} word seeds issued from the BIP85 root with fingerprint <code>{{.RootFingerprint}}</code>. Record which system each index was issued to.</p>
+<table>
+<tr><th>Index</th><th>Path</th><th>Issued to</th></tr>
+{{- range .Seeds}}
+<tr><td>{{.Index}}</td><td><code>{{.Path}}</code></td><td></td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Written files</h2>
+{{- if .Result.WrittenFiles}}
+<ul>
+{{- range .Result.WrittenFiles}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>None.</p>
+{{- end}}
+<h2>Incident record</h2>
+<ul>
+<li>Reason for the recovery: ________________</li>
+<li>Approved by: ________________</li>
+<li>Witnesses: ________________</li>
+</ul>
+<h2>Next steps</h2>
+<ul>
+<li>&#9744; Confirm the addresses above are the vault's addresses on the platform or an explorer.</li>
+<li>&#9744; Sweep the funds of every address to new keys, as the recovered keys were exposed on this machine.</li>
+<li>&#9744; Securely delete the written files that hold private keys once the funds are swept.</li>
+<li>&#9744; Store the backup files and phrases back in their safe places, or rotate them.</li>
+<li>&#9744; File this summary with the incident records.</li>
+</ul>
+</body>
+</html>
+`))
+
+// markdownCell escapes text for a Markdown table cell, e.g. a vault name with a pipe.
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", "\\|", "\r", " ", "\n", " ").Replace(text)
+}
+
+// renderSummary renders the summary as Markdown or HTML, by the extension of filename.
+func renderSummary(filename string, summary RecoverySummary) ([]byte, error) {
+	out := new(bytes.Buffer)
+	var err error
+	if strings.ToLower(filepath.Ext(filename)) == ".html" {
+		err = summaryHTML.Execute(out, summary)
+	} else {
+		err = summaryMarkdown.Execute(out, summary)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("⚠ failed to render the recovery summary: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// writeSummary writes the summary to filename, tagged with the session ID. Existing files are never overwritten.
+func writeSummary(filename string, summary RecoverySummary) (string, error) {
+	data, err := renderSummary(filename, summary)
+	if err != nil {
+		return "", err
+	}
+	filename = ui.SessionFilename(filename)
+	if err = writeNewFile(filename, data); err != nil {
+		return "", fmt.Errorf("⚠ failed to write the recovery summary: %w", err)
+	}
+	return filename, nil
+}
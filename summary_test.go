@@ -0,0 +1,81 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverySummary(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	eddsaSK := make([]byte, 32)
+	eddsaSK[0] = 0x07
+	eddsaPK := make([]byte, 32)
+	eddsaPK[0] = 0x42
+	// even a result with the keys shown never puts them in the summary
+	result := recoveryResult("vault-1", "Treasury | hot", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", ecdsaSK, eddsaSK, eddsaPK, nil, true, false,
+		[]string{"wallet.json"})
+	bip85Seeds, err := bip85Result(ecdsaSK, 12, []uint32{3}, true)
+	require.NoError(t, err)
+	result.BIP85 = bip85Seeds
+	recoveredAt := time.Date(2025, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+	summary := recoverySummary(result, "INC-42", []string{"/media/usb/party-1.json", "party-2.json"}, recoveredAt)
+	assert.Nil(t, summary.Result.ECDSA)
+	assert.Nil(t, summary.Result.EdDSA)
+	assert.Equal(t, []ResultBIP85Child{{Index: 3, Path: "m/83696968'/39'/0'/12'/3'"}}, summary.Result.BIP85.Seeds)
+	assert.NotEmpty(t, result.BIP85.Seeds[0].Mnemonic, "the result itself is left as is")
+	assert.Equal(t, []string{"party-1.json", "party-2.json"}, summary.BackupFiles)
+	assert.NotEmpty(t, summary.Operator)
+
+	for _, filename := range []string{"summary.md", "summary.HTML"} {
+		t.Run(filename, func(t *testing.T) {
+			content, err := renderSummary(filename, summary)
+			require.NoError(t, err)
+			text := string(content)
+			assert.Contains(t, text, "vault-1")
+			assert.Contains(t, text, "INC-42")
+			assert.Contains(t, text, "2025-03-04 04:06:07 UTC")
+			assert.Contains(t, text, "party-1.json, party-2.json")
+			assert.Contains(t, text, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4")
+			assert.Contains(t, text, "wallet.json")
+			assert.Contains(t, text, bip85Seeds.RootFingerprint)
+			assert.NotContains(t, text, bip85Seeds.Seeds[0].Mnemonic)
+			assert.NotContains(t, text, bip85Seeds.RootXPRV)
+			assert.NotContains(t, text, strings.Repeat("0", 63)+"1")
+			assert.NotContains(t, text, "07"+strings.Repeat("0", 62))
+			assert.NotContains(t, text, "/media/usb")
+		})
+	}
+	markdown, err := renderSummary("summary.md", summary)
+	require.NoError(t, err)
+	assert.Contains(t, string(markdown), `| Vault | Treasury \| hot |`)
+	assert.Contains(t, string(markdown), "| 3 | `m/83696968'/39'/0'/12'/3'` | |")
+	html, err := renderSummary("summary.html", summary)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "<td>Treasury | hot</td>")
+
+	dir := t.TempDir()
+	summaryFile, err := writeSummary(filepath.Join(dir, "summary.md"), summary)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "summary-"+ui.SessionID+".md"), summaryFile)
+	written, err := os.ReadFile(summaryFile)
+	require.NoError(t, err)
+	assert.Equal(t, markdown, written)
+	// existing files are never overwritten
+	_, err = writeSummary(filepath.Join(dir, "summary.md"), summary)
+	assert.Error(t, err)
+
+	assert.NoError(t, validateSummaryFile("summary.HTML"))
+	assert.Error(t, validateSummaryFile("summary.pdf"))
+}
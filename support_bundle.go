@@ -0,0 +1,205 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/bundle"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/canonical"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/data"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/schema"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/support"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+const supportBundleCmd = "support-bundle"
+
+type (
+	// SupportBundle is the secrets-free summary of the environment and backup files attached to support tickets.
+	SupportBundle struct {
+		GeneratedAt string `json:"generatedAt"`
+		// GeneratedAtLocal is the same time in the local timezone, with its offset, to line up with local event logs
+		GeneratedAtLocal string             `json:"generatedAtLocal"`
+		TimeZone         string             `json:"timeZone"`
+		ToolVersion      string             `json:"toolVersion"`
+		GoVersion        string             `json:"goVersion"`
+		OS               string             `json:"os"`
+		Arch             string             `json:"arch"`
+		NumCPU           int                `json:"numCpu"`
+		Files            []SupportFileStats `json:"files"`
+	}
+	// SupportFileStats are anonymized statistics of a backup file: no names, vault IDs or ciphertexts.
+	SupportFileStats struct {
+		Index           int      `json:"index"`
+		SizeBytes       int64    `json:"sizeBytes"`
+		SHA256          string   `json:"sha256,omitempty"`
+		ModTime         string   `json:"modTime,omitempty"`
+		ParseError      string   `json:"parseError,omitempty"`
+		Vaults          int      `json:"vaults"`
+		ReShares        int      `json:"reshares"`
+		MaxReShareNonce int      `json:"maxReshareNonce"`
+		CipherTextBytes int      `json:"cipherTextBytes"`
+		Ciphers         []string `json:"ciphers"`
+		// Envelope is the top-level key of the mobile app envelope the backup was shared in, if any
+		Envelope string `json:"envelope,omitempty"`
+	}
+)
+
+func runSupportBundle(args []string) error {
+	fs := flag.NewFlagSet(supportBundleCmd, flag.ContinueOnError)
+	out := fs.String("out", fmt.Sprintf("support-bundle-%s.zip", time.Now().UTC().Format("20060102-150405")), "Filename of the support bundle ZIP to write.")
+	logFile := fs.String("log", "", "(Optional) A saved log of the tool's output to include, after redaction.")
+	signKeyFile := fs.String("sign-key", "", "(Optional) An Ed25519 private key in PKCS#8 PEM format to add a detached signature of bundle.json as bundle.json.sig.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: recovery-tool %s [-flags] file1.json file2.json …\n\nNo mnemonics are needed. Flags:\n", supportBundleCmd)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var signKey ed25519.PrivateKey
+	if *signKeyFile != "" {
+		var err error
+		if signKey, err = canonical.LoadSigningKey(*signKeyFile); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	zone, _ := now.Zone()
+	bundle := SupportBundle{
+		GeneratedAt:      now.UTC().Format(time.RFC3339),
+		GeneratedAtLocal: now.Format(time.RFC3339),
+		TimeZone:         zone,
+		ToolVersion:      ui.Version,
+		GoVersion:        runtime.Version(),
+		OS:               runtime.GOOS,
+		Arch:             runtime.GOARCH,
+		NumCPU:           runtime.NumCPU(),
+		Files:            make([]SupportFileStats, 0, fs.NArg()),
+	}
+	for i, file := range fs.Args() {
+		bundle.Files = append(bundle.Files, backupFileStats(i+1, file))
+	}
+	// canonical, so that downstream systems can verify the signature over the exact bytes
+	bundleJSON, err := canonical.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+
+	// bundle.json only holds the statistics above, and redacting it would hide the file hashes
+	entries := map[string][]byte{"bundle.json": bundleJSON}
+	if signKey != nil {
+		entries["bundle.json.sig"] = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(signKey, bundleJSON)) + "\n")
+	}
+	if *logFile != "" {
+		content, err := os.ReadFile(*logFile)
+		if err != nil {
+			return fmt.Errorf("⚠ unable to read log file `%s`: %s", *logFile, err)
+		}
+		entries["log.txt"] = []byte(support.Redact(string(content)))
+	}
+	if err = writeSupportBundle(*out, entries); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote support bundle to: %s. Secrets, mnemonics and addresses were redacted; please review it before sending.\n", *out)
+	if signKey != nil {
+		fmt.Printf("Signed bundle.json with the key with public key fingerprint (SHA-256): %s\n", canonical.Fingerprint(signKey))
+	}
+	return nil
+}
+
+// backupFileStats reads the unencrypted structure of a backup file. No mnemonics are needed and nothing is decrypted.
+func backupFileStats(index int, file string) SupportFileStats {
+	stats := SupportFileStats{Index: index, MaxReShareNonce: -1, Ciphers: []string{}}
+	info, err := os.Stat(file)
+	if err != nil {
+		stats.ParseError = "unable to stat file"
+		return stats
+	}
+	stats.SizeBytes = info.Size()
+	stats.ModTime = info.ModTime().UTC().Format(time.RFC3339)
+	content, err := os.ReadFile(file)
+	if err != nil {
+		stats.ParseError = "unable to read file"
+		return stats
+	}
+	hash := sha256.Sum256(content)
+	stats.SHA256 = hex.EncodeToString(hash[:])
+	if content, stats.Envelope, err = data.UnwrapBackup(content); err != nil {
+		stats.ParseError = "mobile app envelope with more than one backup"
+		return stats
+	}
+	saveData := new(SavedData)
+	var syntaxErr *json.SyntaxError
+	var schemaErr *schema.Error
+	if err = json.Unmarshal(content, saveData); errors.As(err, &syntaxErr) {
+		stats.ParseError = fmt.Sprintf("invalid json at offset %d", syntaxErr.Offset)
+		return stats
+	}
+	if errors.As(schema.Validate(content, savedDataSchema), &schemaErr) {
+		// the path of the malformed field, without the vault IDs
+		stats.ParseError = schemaErr.Anonymized()
+		return stats
+	}
+	if err != nil {
+		stats.ParseError = "unexpected json structure"
+		return stats
+	}
+	ciphers := make(map[string]struct{})
+	stats.Vaults = len(saveData.Vaults)
+	for _, resharesMap := range saveData.Vaults {
+		stats.ReShares += len(resharesMap)
+		for nonce, cipheredVault := range resharesMap {
+			stats.MaxReShareNonce = max(stats.MaxReShareNonce, nonce)
+			stats.CipherTextBytes += len(cipheredVault.CipherTextB64)
+			ciphers[cipheredVault.Cipher] = struct{}{}
+		}
+	}
+	for cipher := range ciphers {
+		stats.Ciphers = append(stats.Ciphers, cipher)
+	}
+	sort.Strings(stats.Ciphers)
+	return stats
+}
+
+func writeSupportBundle(filename string, entries map[string][]byte) error {
+	zipped, err := bundle.Zip(entries)
+	if err != nil {
+		return err
+	}
+	if err = writeNewFile(filename, zipped); err != nil {
+		return fmt.Errorf("⚠ could not create the support bundle `%s`: %s", filename, err)
+	}
+	return nil
+}
+
+// writeNewFile writes a file that must not exist yet, readable only by the current user.
+func writeNewFile(filename string, data []byte) error {
+	if err := strictWrites.Check(filename); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
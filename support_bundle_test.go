@@ -0,0 +1,98 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"archive/zip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportBundle_StatsWithoutSecrets(t *testing.T) {
+	stats := backupFileStats(1, "./test-files/v2.json")
+	assert.Empty(t, stats.ParseError)
+	assert.Equal(t, 1, stats.Vaults)
+	assert.Positive(t, stats.ReShares)
+	assert.Equal(t, []string{"aes-256-gcm"}, stats.Ciphers)
+	assert.Len(t, stats.SHA256, 64)
+
+	missing := backupFileStats(2, "./test-files/does-not-exist.json")
+	assert.NotEmpty(t, missing.ParseError)
+}
+
+func TestSupportBundle_RedactsLog(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "log.txt")
+	secret := "ECDSA private key: 0x" + strings.Repeat("ab", 32)
+	require.NoError(t, os.WriteFile(logFile, []byte(secret), 0600))
+	out := filepath.Join(dir, "bundle.zip")
+
+	require.NoError(t, runSupportBundle([]string{"-out", out, "-log", logFile, "./test-files/v2.json"}))
+
+	zr, err := zip.OpenReader(out)
+	require.NoError(t, err)
+	defer zr.Close()
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), strings.Repeat("ab", 32))
+		assert.NotContains(t, string(content), "v2.json")
+	}
+	assert.ElementsMatch(t, []string{"bundle.json", "log.txt"}, names)
+}
+
+func TestSupportBundle_Signed(t *testing.T) {
+	dir := t.TempDir()
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "signing.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	out := filepath.Join(dir, "bundle.zip")
+
+	require.NoError(t, runSupportBundle([]string{"-out", out, "-sign-key", keyFile, "./test-files/v2.json"}))
+
+	zr, err := zip.OpenReader(out)
+	require.NoError(t, err)
+	defer zr.Close()
+	contents := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		contents[f.Name], err = io.ReadAll(rc)
+		_ = rc.Close()
+		require.NoError(t, err)
+	}
+	require.Contains(t, contents, "bundle.json.sig")
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents["bundle.json.sig"])))
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(pub, contents["bundle.json"], sig))
+
+	// bundle.json is canonical: compact, with sorted keys
+	bundle := new(SupportBundle)
+	require.NoError(t, json.Unmarshal(contents["bundle.json"], bundle))
+	assert.True(t, strings.HasPrefix(string(contents["bundle.json"]), `{"arch":`))
+	assert.NotContains(t, string(contents["bundle.json"]), "\n")
+
+	require.Error(t, runSupportBundle([]string{"-out", filepath.Join(dir, "other.zip"), "-sign-key", filepath.Join(dir, "missing.pem"), "./test-files/v2.json"}))
+}
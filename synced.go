@@ -0,0 +1,104 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// syncedFolder is a folder name used by a cloud sync client. Files written under it are uploaded as soon as they are
+// written.
+type syncedFolder struct {
+	Service string
+	Name    string
+	Prefix  bool
+}
+
+// syncedFolders are the default folders of Dropbox, OneDrive, iCloud Drive and Google Drive on Windows, macOS
+// (including ~/Library/CloudStorage) and Linux.
+var syncedFolders = []syncedFolder{
+	{"Dropbox", "Dropbox", false},
+	{"Dropbox", "Dropbox (", true},
+	{"OneDrive", "OneDrive", false},
+	{"OneDrive", "OneDrive - ", true},
+	{"OneDrive", "OneDrive-", true},
+	{"iCloud Drive", "iCloud Drive", false},
+	{"iCloud Drive", "iCloudDrive", false},
+	{"iCloud Drive", "com~apple~CloudDocs", false},
+	{"Google Drive", "Google Drive", false},
+	{"Google Drive", "My Drive", false},
+	{"Google Drive", "GoogleDrive-", true},
+}
+
+// syncedFolderEnv are the variables the Windows OneDrive client sets to the folders it syncs, which may be renamed.
+var syncedFolderEnv = map[string]string{
+	"OneDrive":           "OneDrive",
+	"OneDriveConsumer":   "OneDrive",
+	"OneDriveCommercial": "OneDrive",
+}
+
+// syncedService returns the cloud sync service whose folder the path is in, or "" if it is in none. Symbolic links of
+// the existing part of the path are resolved, so that a link to a synced folder is caught too.
+func syncedService(path string) string {
+	for _, p := range []string{absPath(path), resolvedPath(path)} {
+		for variable, service := range syncedFolderEnv {
+			if dir := os.Getenv(variable); dir != "" && isUnder(p, absPath(dir)) {
+				return service
+			}
+		}
+		for dir := p; ; dir = filepath.Dir(dir) {
+			name := filepath.Base(dir)
+			for _, folder := range syncedFolders {
+				if strings.EqualFold(name, folder.Name) ||
+					folder.Prefix && len(name) > len(folder.Name) && strings.EqualFold(name[:len(folder.Name)], folder.Name) {
+					return folder.Service
+				}
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	return ""
+}
+
+// resolvedPath resolves the symbolic links of the deepest existing directory of the path.
+func resolvedPath(path string) string {
+	abs := absPath(path)
+	for dir, rest := abs, ""; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		if filepath.Dir(dir) == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+	}
+}
+
+func isUnder(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checkSyncedOutputs refuses outputs in cloud synced folders, unless -allow-synced-path is set. Then, it returns a
+// warning for each of them.
+func checkSyncedOutputs(outputs []string, allow bool) (warnings []string, err error) {
+	for _, output := range outputs {
+		service := syncedService(output)
+		if service == "" {
+			continue
+		}
+		if !allow {
+			return nil, fmt.Errorf("⚠ `%s` is in a %s folder, which uploads it to the internet as soon as it is written. "+
+				"Write it to a local folder that is not synced, or set -allow-synced-path if this is really intended", output, service)
+		}
+		warnings = append(warnings, fmt.Sprintf("⚠ WARNING: `%s` is in a %s folder. It will be uploaded to the internet as soon as it is written.", output, service))
+	}
+	return warnings, nil
+}
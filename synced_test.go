@@ -0,0 +1,69 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncedService(t *testing.T) {
+	for _, variable := range []string{"OneDrive", "OneDriveConsumer", "OneDriveCommercial"} {
+		t.Setenv(variable, "")
+	}
+	dir := t.TempDir()
+	tests := map[string]string{
+		filepath.Join(dir, "wallet.json"):                                         "",
+		filepath.Join(dir, "Dropbox", "keys", "wallet.json"):                      "Dropbox",
+		filepath.Join(dir, "Dropbox (Acme Corp)", "wallet.json"):                  "Dropbox",
+		filepath.Join(dir, "dropbox", "wallet.json"):                              "Dropbox",
+		filepath.Join(dir, "DropboxBackups", "wallet.json"):                       "",
+		filepath.Join(dir, "OneDrive - Acme Corp", "Documents", "wallet.json"):    "OneDrive",
+		filepath.Join(dir, "Library", "CloudStorage", "OneDrive-Personal", "w"):   "OneDrive",
+		filepath.Join(dir, "Library", "Mobile Documents", "com~apple~CloudDocs"):  "iCloud Drive",
+		filepath.Join(dir, "iCloudDrive", "wallet.json"):                          "iCloud Drive",
+		filepath.Join(dir, "Library", "CloudStorage", "GoogleDrive-a@b.com", "x"): "Google Drive",
+		filepath.Join(dir, "My Drive", "wallet.json"):                             "Google Drive",
+		filepath.Join(dir, "Google Drive", "tss"):                                 "Google Drive",
+	}
+	for path, service := range tests {
+		assert.Equal(t, service, syncedService(path), path)
+	}
+
+	// a renamed OneDrive folder is known from the environment
+	t.Setenv("OneDriveCommercial", filepath.Join(dir, "Work Files"))
+	assert.Equal(t, "OneDrive", syncedService(filepath.Join(dir, "Work Files", "wallet.json")))
+	assert.Equal(t, "", syncedService(filepath.Join(dir, "Work Files Local", "wallet.json")))
+
+	if runtime.GOOS != "windows" {
+		synced := filepath.Join(dir, "Dropbox")
+		require.NoError(t, os.Mkdir(synced, 0o700))
+		require.NoError(t, os.Symlink(synced, filepath.Join(dir, "exports")))
+		assert.Equal(t, "Dropbox", syncedService(filepath.Join(dir, "exports", "new", "wallet.json")))
+	}
+}
+
+func TestCheckSyncedOutputs(t *testing.T) {
+	dir := t.TempDir()
+	local, synced := filepath.Join(dir, "wallet.json"), filepath.Join(dir, "Dropbox", "wallet.json")
+
+	warnings, err := checkSyncedOutputs([]string{local}, false)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	_, err = checkSyncedOutputs([]string{local, synced}, false)
+	assert.ErrorContains(t, err, "is in a Dropbox folder")
+	assert.ErrorContains(t, err, "-allow-synced-path")
+
+	warnings, err = checkSyncedOutputs([]string{local, synced}, true)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "uploaded to the internet")
+}
@@ -5,9 +5,7 @@
 package main
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/sha512"
+	"bytes"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -15,11 +13,17 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
+	"runtime/debug"
 	"sort"
 	"strings"
+	"time"
 
+	crypto2 "github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/crypto"
 	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/data"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/schema"
 	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
 	"github.com/binance-chain/tss-lib/crypto"
 	"github.com/binance-chain/tss-lib/crypto/vss"
 	ecdsa_keygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
@@ -27,28 +31,53 @@ import (
 	"github.com/binance-chain/tss-lib/tss"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/google/uuid"
 	errors2 "github.com/pkg/errors"
 	"github.com/tyler-smith/go-bip39"
 	"golang.org/x/crypto/sha3"
+	"golang.org/x/sync/errgroup"
 )
 
-func runTool(vaultsDataFile []ui.VaultsDataFile, vaultID *string, nonceOverride, quorumOverride *int, exportKSFile, passwordForKS *string) (
+// ToolOptions are the options of a runTool call. The zero value lists the vaults in the files.
+type ToolOptions struct {
+	// VaultID is the vault to recover. Without it, the vaults in the files are only listed
+	VaultID string
+	// NonceOverride replaces the reshare nonce of the vault unless nil or -1, and QuorumOverride its quorum unless nil or 0
+	NonceOverride, QuorumOverride *int
+	// ExportKSFile is the wallet v3 file to write the ECDSA key to, encrypted with PasswordForKS under KSKDF, or the
+	// standard preset if KSKDF is zero
+	ExportKSFile, PasswordForKS string
+	KSKDF                       walletv3.KDFParams
+	// ExportTSSShareDir is the directory to write the TSS share bundles of the parties to
+	ExportTSSShareDir string
+	// ExportLabel is embedded in the metadata of the exported files
+	ExportLabel string
+	// Force shows the keys even if they do not match the public keys recorded in the vault metadata
+	Force bool
+	// Commitments are the keygen commitments to verify the shares against, by vault ID
+	Commitments VaultCommitments
+}
+
+func runTool(vaultsDataFile []ui.VaultsDataFile, opts ToolOptions) (
 	address string, ecdsaSK, eddsaSK []byte, orderedVaults []ui.VaultPickerItem, welp error) {
 
-	if nonceOverride != nil && *nonceOverride > -1 {
-		fmt.Printf("\n⚠ Using reshare nonce override: %d. Be sure to set the threshold of the vault at this reshare point with -threshold, or recovery will produce incorrect data.\n", *nonceOverride)
+	if opts.NonceOverride != nil && *opts.NonceOverride > -1 {
+		ui.Printf("\n⚠ Using reshare nonce override: %d. Be sure to set the threshold of the vault at this reshare point with -threshold, or recovery will produce incorrect data.\n", *opts.NonceOverride)
 	}
-	if quorumOverride != nil && *quorumOverride > 0 {
-		fmt.Printf("\n⚠ Using vault quorum override: %d.\n", *quorumOverride)
+	if opts.QuorumOverride != nil && *opts.QuorumOverride > 0 {
+		ui.Printf("\n⚠ Using vault quorum override: %d.\n", *opts.QuorumOverride)
 	}
-	if (nonceOverride != nil && *nonceOverride > -1) || (quorumOverride != nil && *quorumOverride > 0) {
-		println()
+	if (opts.NonceOverride != nil && *opts.NonceOverride > -1) || (opts.QuorumOverride != nil && *opts.QuorumOverride > 0) {
+		ui.Printf("\n")
 	}
 
-	justListingVaults := vaultID == nil || *vaultID == ""
+	justListingVaults := opts.VaultID == ""
+	// the share sizes are only reported when recovering
+	inflation := newInflationStats(verboseOutput)
+	var shareStats ShareStats
+	if !justListingVaults {
+		shareStats = inflation
+	}
 
 	// Internal & returned data structures
 	clearVaults := make(ClearVaultMap, len(vaultsDataFile)*16)
@@ -56,18 +85,18 @@ func runTool(vaultsDataFile []ui.VaultsDataFile, vaultID *string, nonceOverride,
 	vaultAllSharesEDDSA := make(VaultAllSharesEdDSA, len(vaultsDataFile)*16)
 	vaultHasEDDSA := make(map[string]bool, len(vaultsDataFile)*16)
 	vaultLastNonces := make(map[string]int, len(vaultsDataFile)*16)
+	vaultShareCounts := make(map[string]int, len(vaultsDataFile)*16)
+	vaultHeldShares := make(map[string][]ui.HeldShare, len(vaultsDataFile)*16)
+	vaultPartyShareIDs := make(map[string][]string, len(vaultsDataFile)*16)
+	vaultTimelines := make(map[string][]ui.TimelineEntry, len(vaultsDataFile)*16)
+	// vault ID -> algorithm -> public keys recorded in the vault metadata of each file
+	vaultMetadataPubKeys := make(map[string]map[string][]string, len(vaultsDataFile)*16)
 
 	// // Do the main routine
 	for _, file := range vaultsDataFile {
-		saveData := new(SavedData)
-
-		content, err := os.ReadFile(file.File)
+		saveData, err := loadSavedData(file)
 		if err != nil {
-			welp = fmt.Errorf("⚠ file to read from file(%s): %s", file, err)
-			return
-		}
-		if err := json.Unmarshal(content, saveData); err != nil {
-			welp = errors2.Wrapf(err, "⚠ invalid saveData format - is this an old backup file? (code: 1)")
+			welp = err
 			return
 		}
 
@@ -81,7 +110,7 @@ func runTool(vaultsDataFile []ui.VaultsDataFile, vaultID *string, nonceOverride,
 		// decrypt the vaults into clear vaults
 		for vID, resharesMap := range saveData.Vaults {
 			// only look at the vault we're interested in, if one was supplied
-			if !justListingVaults && vID != *vaultID {
+			if !justListingVaults && vID != opts.VaultID {
 				continue
 			}
 
@@ -89,7 +118,7 @@ func runTool(vaultsDataFile []ui.VaultsDataFile, vaultID *string, nonceOverride,
 			lastReshareNonce := -1
 			for nonce := range resharesMap {
 				// support the -nonce flag to override the last reshare nonce we use
-				if !justListingVaults && nonceOverride != nil && *nonceOverride > -1 && *nonceOverride != nonce {
+				if !justListingVaults && opts.NonceOverride != nil && *opts.NonceOverride > -1 && *opts.NonceOverride != nonce {
 					continue
 				}
 				if nonce > lastReshareNonce {
@@ -101,65 +130,55 @@ func runTool(vaultsDataFile []ui.VaultsDataFile, vaultID *string, nonceOverride,
 				continue // not a show stopper
 			}
 			if glbLastReShareNonce, ok := vaultLastNonces[vID]; ok && glbLastReShareNonce != lastReshareNonce {
-				fmt.Printf("\n⚠ Non matching reshare nonce for vault `%s`. You may have to specify prior reshare config with -nonce and -threshold when recovering that vault.\n", vID)
+				ui.Printf("\n⚠ Non matching reshare nonce for vault `%s`. You may have to specify prior reshare config with -nonce and -threshold when recovering that vault.\n", vID)
 				if lastReshareNonce-1 >= 0 {
-					fmt.Printf("⚠ If you have problems recovering that vault, you could try: -vault-id %s -nonce %d -threshold x. Replace x with previous vault threshold.\n", vID, lastReshareNonce-1)
+					ui.Printf("⚠ If you have problems recovering that vault, you could try: -vault-id %s -nonce %d -threshold x, or -override %s=%d:x. Replace x with previous vault threshold.\n", vID, lastReshareNonce-1, vID, lastReshareNonce-1)
 				} else {
-					println()
+					ui.Printf("\n")
 				}
 			}
 			vaultLastNonces[vID] = lastReshareNonce
+			nonces := make([]int, 0, len(resharesMap))
+			for nonce := range resharesMap {
+				nonces = append(nonces, nonce)
+			}
+			sort.Ints(nonces)
+			vaultTimelines[vID] = append(vaultTimelines[vID], ui.NewTimelineEntry(file.File, saveData.Timestamp, nonces))
 			cipheredVault := resharesMap[lastReshareNonce]
 
 			// DECRYPT
-			aesNonce, err := hex.DecodeString(cipheredVault.CipherParams.IV)
-			if err != nil {
-				welp = errors2.Errorf("⚠ failed to decrypt vault %s: %s (on nonce decode)", vID, err)
-				return
-			}
-			aesTag, err := hex.DecodeString(cipheredVault.CipherParams.Tag)
-			if err != nil {
-				welp = errors2.Errorf("⚠ failed to decrypt vault %s: %s (on tag decode)", vID, err)
-				return
-			}
-			aesCT, err := base64.StdEncoding.DecodeString(cipheredVault.CipherTextB64)
-			if err != nil {
-				welp = errors2.Errorf("⚠ failed to decrypt vault %s: %s (on ciphertext decode)", vID, err)
-				return
-			}
-
-			// init AES-GCM cipher
-			aesBlk, err := aes.NewCipher(aesKey32)
-			if err != nil {
-				welp = errors2.Errorf("⚠ failed to decrypt vault %s: %s (on cipher init 1)", vID, err)
-				return
-			}
-			aesGCM, err := cipher.NewGCM(aesBlk)
+			plainload, err := crypto2.OpenEnvelope(aesKey32, crypto2.Envelope{
+				CipherTextB64: cipheredVault.CipherTextB64,
+				IV:            cipheredVault.CipherParams.IV,
+				Tag:           cipheredVault.CipherParams.Tag,
+				Hash:          cipheredVault.Hash,
+			})
 			if err != nil {
-				welp = errors2.Errorf("⚠ failed to decrypt vault %s: %s (on cipher init 2)", vID, err)
+				welp = errors2.Errorf("⚠ failed to decrypt vault %s: %s", vID, err)
 				return
 			}
 
-			// append the tag to the ciphertext, which is what golang's GCM implementation expects
-			aesCT = append(aesCT, aesTag...)
-			plainload, err := aesGCM.Open(nil, aesNonce, aesCT, nil)
-			if err != nil {
-				welp = errors2.Errorf("⚠ failed to decrypt vault %s: %s (on decrypt)", vID, err)
-				return
-			}
-			expHash := sha512.Sum512(plainload)
-			if hex.EncodeToString(expHash[:]) != cipheredVault.Hash {
-				welp = errors2.Errorf("⚠ failed to decrypt vault %s: %s (hash mismatch)", vID, err)
+			// decode vault from json
+			if err = schema.Validate(plainload, clearVaultSchema); err != nil {
+				welp = errors2.Errorf("⚠ the decrypted vault %s (reshare %d) is not valid: %s", vID, lastReshareNonce, err)
 				return
 			}
-
-			// decode vault from json
 			clearVaults[vID] = new(ClearVault)
 			if err = json.Unmarshal(plainload, clearVaults[vID]); err != nil {
 				welp = errors2.Wrapf(err, "invalid saveData format - is this an old backup file? (code: 3)")
 				return
 			}
 			clearVaults[vID].LastReShareNonce = lastReshareNonce
+			for _, curve := range clearVaults[vID].Curves {
+				if curve.PublicKey == "" {
+					continue
+				}
+				if _, ok := vaultMetadataPubKeys[vID]; !ok {
+					vaultMetadataPubKeys[vID] = make(map[string][]string, 2)
+				}
+				algorithm := strings.ToUpper(curve.Algorithm)
+				vaultMetadataPubKeys[vID][algorithm] = append(vaultMetadataPubKeys[vID][algorithm], curve.PublicKey)
+			}
 
 			// rack up the shares
 			sharesECDSA, sharesEDDSA := clearVaults[vID].SharesLegacy, ([]string)(nil)
@@ -186,29 +205,53 @@ func runTool(vaultsDataFile []ui.VaultsDataFile, vaultID *string, nonceOverride,
 				welp = fmt.Errorf("no legacy or new shares found for vault %s %s", vID, clearVaults[vID].Name)
 				return
 			}
-			if vaultSharesECDSA, welp = inflateSharesForCurve[ecdsa_keygen.LocalPartySaveData](sharesECDSA, justListingVaults); welp != nil {
+			if vaultSharesECDSA, welp = inflateSharesForCurve[ecdsa_keygen.LocalPartySaveData](sharesECDSA, vID, shareStats); welp != nil {
 				return
 			}
+			vaultShareCounts[vID] += len(vaultSharesECDSA)
 			if _, ok := vaultAllSharesECDSA[vID]; !ok {
 				vaultAllSharesECDSA[vID] = make([]*ecdsa_keygen.LocalPartySaveData, 0, len(sharesECDSA))
 			}
-			vaultAllSharesECDSA[vID] = append(vaultAllSharesECDSA[vID], vaultSharesECDSA...)
+			// listing only needs the share IDs, so in low memory mode the parsed shares are left to the GC
+			if !justListingVaults || !lowMemory {
+				vaultAllSharesECDSA[vID] = append(vaultAllSharesECDSA[vID], vaultSharesECDSA...)
+			}
+			for _, share := range vaultSharesECDSA {
+				vaultHeldShares[vID] = append(vaultHeldShares[vID], ui.HeldShare{ShareID: share.ShareID.String(), File: file.File})
+				if _, ok := vaultPartyShareIDs[vID]; !ok && len(share.Ks) > 0 {
+					// every share knows the share IDs of all the parties of the vault
+					for _, k := range share.Ks {
+						vaultPartyShareIDs[vID] = append(vaultPartyShareIDs[vID], k.String())
+					}
+				}
+			}
 			// / ECDSA
 			// EDDSA
 			if sharesEDDSA != nil {
-				if vaultSharesEDDSA, welp = inflateSharesForCurve[eddsa_keygen.LocalPartySaveData](sharesEDDSA, justListingVaults); welp != nil {
+				if vaultSharesEDDSA, welp = inflateSharesForCurve[eddsa_keygen.LocalPartySaveData](sharesEDDSA, vID, shareStats); welp != nil {
 					return
 				}
 				if _, ok := vaultAllSharesEDDSA[vID]; !ok {
 					vaultAllSharesEDDSA[vID] = make([]*eddsa_keygen.LocalPartySaveData, 0, len(sharesEDDSA))
 					vaultHasEDDSA[vID] = true
 				}
-				vaultAllSharesEDDSA[vID] = append(vaultAllSharesEDDSA[vID], vaultSharesEDDSA...)
+				if !justListingVaults || !lowMemory {
+					vaultAllSharesEDDSA[vID] = append(vaultAllSharesEDDSA[vID], vaultSharesEDDSA...)
+				}
 			}
 			// / EDDSA
+			if justListingVaults && lowMemory {
+				clearVaults[vID].SharesLegacy = nil
+				for i := range clearVaults[vID].Curves {
+					clearVaults[vID].Curves[i].Shares = nil
+				}
+			}
 		}
 
 		clear(aesKey32)
+		if lowMemory {
+			debug.FreeOSMemory()
+		}
 	}
 
 	// populate vault IDs
@@ -222,7 +265,9 @@ func runTool(vaultsDataFile []ui.VaultsDataFile, vaultID *string, nonceOverride,
 	orderedVaults = make([]ui.VaultPickerItem, 0, len(vaultIDs))
 	for _, vID := range vaultIDs {
 		vault := clearVaults[vID]
-		vaultFormData := ui.VaultPickerItem{VaultID: vID, Name: vault.Name, Quorum: vault.Quroum, NumberOfShares: len(vaultAllSharesECDSA[vID])}
+		vaultFormData := ui.VaultPickerItem{VaultID: vID, Name: vault.Name, Quorum: vault.Quroum, LastReShareNonce: vault.LastReShareNonce,
+			NumberOfShares: vaultShareCounts[vID], HeldShares: vaultHeldShares[vID], PartyShareIDs: vaultPartyShareIDs[vID],
+			Timeline: vaultTimelines[vID]}
 		orderedVaults = append(orderedVaults, vaultFormData)
 	}
 
@@ -231,132 +276,404 @@ func runTool(vaultsDataFile []ui.VaultsDataFile, vaultID *string, nonceOverride,
 		return "", nil, nil, orderedVaults, nil
 	}
 
-	println()
-	if _, ok := vaultAllSharesECDSA[*vaultID]; !ok {
-		welp = fmt.Errorf("⚠ provided files do not contain data for vault `%s` with the expected reshare nonce", *vaultID)
+	if summary := inflation.Summary(opts.VaultID); summary.Shares > 0 {
+		ui.Printf("%s\n", summary)
+	}
+	ui.Printf("\n")
+	if _, ok := vaultAllSharesECDSA[opts.VaultID]; !ok {
+		welp = fmt.Errorf("⚠ provided files do not contain data for vault `%s` with the expected reshare nonce", opts.VaultID)
 		return
 	}
-	if vaultHasEDDSA[*vaultID] && len(vaultAllSharesEDDSA[*vaultID]) != len(vaultAllSharesECDSA[*vaultID]) {
+	if vaultHasEDDSA[opts.VaultID] && len(vaultAllSharesEDDSA[opts.VaultID]) != len(vaultAllSharesECDSA[opts.VaultID]) {
 		welp = fmt.Errorf("⚠ count of EDDSA shares %d != count of ECDSA shares %d for vault `%s`",
-			len(vaultAllSharesEDDSA[*vaultID]), len(vaultAllSharesECDSA[*vaultID]), *vaultID)
+			len(vaultAllSharesEDDSA[opts.VaultID]), len(vaultAllSharesECDSA[opts.VaultID]), opts.VaultID)
 		return
 	}
 
-	tPlus1 := clearVaults[*vaultID].Quroum
-	if quorumOverride != nil && *quorumOverride > 0 {
-		tPlus1 = *quorumOverride
+	tPlus1 := clearVaults[opts.VaultID].Quroum
+	if opts.QuorumOverride != nil && *opts.QuorumOverride > 0 {
+		tPlus1 = *opts.QuorumOverride
 	}
-	vssSharesECDSA := make(vss.Shares, len(vaultAllSharesECDSA[*vaultID]))
-	vssSharesEDDSA := make(vss.Shares, len(vaultAllSharesEDDSA[*vaultID]))
-	if len(vaultAllSharesECDSA[*vaultID]) < tPlus1 {
-		welp = fmt.Errorf("⚠ not enough shares to recover the key for vault %s (need %d, have %d)", *vaultID, tPlus1, len(vaultAllSharesECDSA[*vaultID]))
+	if len(vaultAllSharesECDSA[opts.VaultID]) < tPlus1 {
+		welp = fmt.Errorf("⚠ not enough shares to recover the key for vault %s (need %d, have %d)", opts.VaultID, tPlus1, len(vaultAllSharesECDSA[opts.VaultID]))
 		return
 	}
-	var share0ECDSAPubKey, share0EDDSAPubKey *crypto.ECPoint
-	for i, el := range vaultAllSharesECDSA[*vaultID] {
-		vssSharesECDSA[i] = &vss.Share{
-			Threshold: tPlus1 - 1,
-			ID:        el.ShareID,
-			Share:     el.Xi,
+	// verify every share against the keygen commitments, so that a bad share is pinpointed before anything is combined
+	if vs, ok := opts.Commitments[opts.VaultID]; ok {
+		ecdsaShares, eddsaShares := make([]committedShare, 0, len(vaultAllSharesECDSA[opts.VaultID])), make([]committedShare, 0, len(vaultAllSharesEDDSA[opts.VaultID]))
+		for i, share := range vaultAllSharesECDSA[opts.VaultID] {
+			ecdsaShares = append(ecdsaShares, committedShare{ID: share.ShareID, Xi: share.Xi, File: vaultHeldShares[opts.VaultID][i].File})
+		}
+		// EdDSA shares are held in the same order as the ECDSA shares
+		for i, share := range vaultAllSharesEDDSA[opts.VaultID] {
+			eddsaShares = append(eddsaShares, committedShare{ID: share.ShareID, Xi: share.Xi, File: vaultHeldShares[opts.VaultID][i].File})
+		}
+		if welp = verifyShareCommitments(tss.S256(), "ECDSA", opts.VaultID, vs.ECDSA, ecdsaShares); welp != nil {
+			return
+		}
+		if len(vs.EdDSA) > 0 && len(eddsaShares) > 0 {
+			if welp = verifyShareCommitments(tss.Edwards(), "EdDSA", opts.VaultID, vs.EdDSA, eddsaShares); welp != nil {
+				return
+			}
 		}
-		if i == 0 {
-			share0ECDSAPubKey = el.ECDSAPub
+		ui.Printf("All shares of vault %s match the commitments.\n", opts.VaultID)
+	}
+
+	exportingKS := opts.ExportKSFile != "" && opts.PasswordForKS != ""
+	kdf := walletv3.Presets["standard"]
+	if opts.KSKDF != (walletv3.KDFParams{}) {
+		kdf = opts.KSKDF
+	}
+	if exportingKS {
+		if estimate, err := kdf.EstimateUnlockTime(); err == nil {
+			ui.Printf("Encrypting the wallet v3 file with %s. Unlocking it will take about %s on this machine.\n", kdf, estimate.Round(100*time.Millisecond))
 		}
 	}
-	if vaultHasEDDSA[*vaultID] {
-		for i, el := range vaultAllSharesEDDSA[*vaultID] {
-			vssSharesEDDSA[i] = &vss.Share{
-				Threshold: tPlus1 - 1,
-				ID:        el.ShareID,
-				Share:     el.Xi,
+
+	// Re-construct and verify the secret keys of both curves concurrently.
+	// The keystore encryption (scrypt) is the slowest step, so it runs in the ECDSA branch as soon as the key is verified.
+	var (
+		g       errgroup.Group
+		keyfile []byte
+	)
+	g.Go(func() error {
+		var privKey *secp256k1.PrivateKey
+		var err error
+		if ecdsaSK, privKey, err = recoverECDSAKey(vaultAllSharesECDSA[opts.VaultID], tPlus1); err != nil {
+			return err
+		}
+		// checked before the keystore is encrypted, so that nothing is exported for a mismatching key
+		if err = verifyMetadataPublicKey("ECDSA", vaultMetadataPubKeys[opts.VaultID]["ECDSA"], privKey.PubKey().SerializeUncompressed(), opts.Force); err != nil {
+			return err
+		}
+		// encode Ethereum address for human sanity check
+		pk := privKey.PubKey()
+		if _, address, err = getTSSPubKeyForEthereum(pk.X(), pk.Y()); err != nil {
+			return err
+		}
+		if exportingKS {
+			keyfile, err = encryptKeystore(ecdsaSK, address, opts.PasswordForKS, kdf, &opts.ExportLabel)
+		}
+		return err
+	})
+	if vaultHasEDDSA[opts.VaultID] {
+		g.Go(func() (err error) {
+			if eddsaSK, err = recoverEdDSAKey(vaultAllSharesEDDSA[opts.VaultID], tPlus1); err != nil {
+				return
 			}
-			if i == 0 {
-				share0EDDSAPubKey = el.EDDSAPub
+			_, edPK, err := edwards.PrivKeyFromScalar(eddsaSK)
+			if err != nil {
+				return
 			}
-		}
+			return verifyMetadataPublicKey("EDDSA", vaultMetadataPubKeys[opts.VaultID]["EDDSA"], edPK.SerializeCompressed(), opts.Force)
+		})
+	}
+	if welp = g.Wait(); welp != nil {
+		clear(ecdsaSK)
+		clear(eddsaSK)
+		return "", nil, nil, nil, welp
 	}
 
-	// Re-construct the secret keys
-	var ecdsaSKI, eddsaSKI *big.Int
-	if ecdsaSKI, welp = vssSharesECDSA.ReConstruct(tss.S256()); welp != nil {
-		return
+	// write out the per-party TSS share bundles, only once the shares have been proven consistent
+	if opts.ExportTSSShareDir != "" {
+		var written []string
+		if written, welp = exportTSSShareBundles(opts.ExportTSSShareDir, opts.VaultID, clearVaults[opts.VaultID], tPlus1,
+			vaultAllSharesECDSA[opts.VaultID], vaultAllSharesEDDSA[opts.VaultID], &opts.ExportLabel); welp != nil {
+			return
+		}
+		fmt.Printf("\nWrote %d TSS share bundles (for re-import into a signing cluster) to: %s.\n\n", len(written), opts.ExportTSSShareDir)
 	}
-	if vaultHasEDDSA[*vaultID] {
-		if eddsaSKI, welp = vssSharesEDDSA.ReConstruct(tss.Edwards()); welp != nil {
+
+	// write out keystore file
+	if opts.ExportKSFile != "" {
+		if !exportingKS {
+			fmt.Printf("NOTE: -password flag is required to export wallet v3 file `%s`. A wallet v3 file will not be created this time.\n\n", opts.ExportKSFile)
+			return
+		}
+		if welp = strictWrites.Check(opts.ExportKSFile); welp != nil {
 			return
 		}
-		eddsaSK = leftPadTo32Bytes(eddsaSKI)
-		eddsaSKI.SetInt64(0)
+		if welp = os.WriteFile(opts.ExportKSFile, keyfile, 0600); welp != nil {
+			return
+		}
+		fmt.Printf("\nWrote a MetaMask wallet v3 (for ECDSA key only) to: %s.\n\n", opts.ExportKSFile)
 	}
-	ecdsaSK = leftPadTo32Bytes(ecdsaSKI)
-	ecdsaSKI.SetInt64(0)
+	return address, ecdsaSK, eddsaSK, orderedVaults, nil
+}
+
+// recoverECDSAKey re-constructs the ECDSA secret key from the shares and ensures it matches the public key of share 0.
+func recoverECDSAKey(shares []*ecdsa_keygen.LocalPartySaveData, tPlus1 int) ([]byte, *secp256k1.PrivateKey, error) {
+	vssShares := make(vss.Shares, len(shares))
+	for i, el := range shares {
+		vssShares[i] = &vss.Share{
+			Threshold: tPlus1 - 1,
+			ID:        el.ShareID,
+			Share:     el.Xi,
+		}
+	}
+	ski, err := vssShares.ReConstruct(tss.S256())
+	if err != nil {
+		return nil, nil, err
+	}
+	sk := leftPadTo32Bytes(ski)
+	ski.SetInt64(0)
 
 	// ensure the ECDSA PK matches our expected share 0 PK
 	scl := secp256k1.ModNScalar{}
-	scl.SetByteSlice(ecdsaSK)
+	scl.SetByteSlice(sk)
 	privKey := secp256k1.NewPrivateKey(&scl)
-	pk := privKey.PubKey()
-	if !pk.ToECDSA().Equal(share0ECDSAPubKey.ToBtcecPubKey().ToECDSA()) {
-		welp = fmt.Errorf("⚠ recovered ECDSA public key did not match the expected share 0 public key! did you input the right threshold?")
-		return
+	if !privKey.PubKey().ToECDSA().Equal(shares[0].ECDSAPub.ToBtcecPubKey().ToECDSA()) {
+		clear(sk)
+		return nil, nil, fmt.Errorf("⚠ recovered ECDSA public key did not match the expected share 0 public key! did you input the right threshold?")
 	}
+	return sk, privKey, nil
+}
 
-	// if applicable, ensure the EDDSA PK matches our expected share 0 PK
-	if vaultHasEDDSA[*vaultID] {
-		_, edPK, err := edwards.PrivKeyFromScalar(eddsaSK)
-		if err != nil {
-			welp = err
-			return
+// recoverEdDSAKey re-constructs the EdDSA secret key from the shares and ensures it matches the public key of share 0.
+func recoverEdDSAKey(shares []*eddsa_keygen.LocalPartySaveData, tPlus1 int) ([]byte, error) {
+	vssShares := make(vss.Shares, len(shares))
+	for i, el := range shares {
+		vssShares[i] = &vss.Share{
+			Threshold: tPlus1 - 1,
+			ID:        el.ShareID,
+			Share:     el.Xi,
+		}
+	}
+	ski, err := vssShares.ReConstruct(tss.Edwards())
+	if err != nil {
+		return nil, err
+	}
+	sk := leftPadTo32Bytes(ski)
+	ski.SetInt64(0)
+
+	// ensure the EDDSA PK matches our expected share 0 PK
+	_, edPK, err := edwards.PrivKeyFromScalar(sk)
+	if err != nil {
+		clear(sk)
+		return nil, err
+	}
+	edPKPt, err := crypto.NewECPoint(tss.Edwards(), edPK.X, edPK.Y)
+	if err != nil {
+		clear(sk)
+		return nil, err
+	}
+	if !edPKPt.Equals(shares[0].EDDSAPub) {
+		clear(sk)
+		return nil, fmt.Errorf("⚠ recovered EdDSA public key did not match the expected share 0 public key! did you input the right threshold?")
+	}
+	return sk, nil
+}
+
+// verifyMetadataPublicKey checks a recovered public key against the public keys recorded in the vault metadata of each
+// file. Unlike the public key of share 0, these do not come from the shares being combined, so shares mixed from
+// different vaults or reshares are caught. Vaults without public keys in their metadata pass.
+func verifyMetadataPublicKey(algorithm string, expected []string, recovered []byte, force bool) error {
+	for _, expectedHex := range expected {
+		if metadataPublicKeyMatches(algorithm, expectedHex, recovered) {
+			continue
+		}
+		msg := fmt.Sprintf("⚠ recovered %s public key %x does not match the public key %s in the vault metadata. "+
+			"The backup files may mix shares of different vaults or reshares", algorithm, recovered, expectedHex)
+		if !force {
+			return fmt.Errorf("%s. No keys are shown. If advised to, run again with -force to show them anyway", msg)
+		}
+		ui.Printf("\n%s. Continuing because of -force.\n", msg)
+	}
+	return nil
+}
+
+func metadataPublicKeyMatches(algorithm, expectedHex string, recovered []byte) bool {
+	expected, err := hex.DecodeString(strings.TrimPrefix(expectedHex, "0x"))
+	if err != nil {
+		return false
+	}
+	if algorithm != "ECDSA" {
+		// EdDSA keys are recorded in the standard 32 byte encoding, or as 02/03 (Y parity) || X like a compressed ECDSA key
+		if len(expected) != 33 {
+			return bytes.Equal(expected, recovered)
 		}
-		edPKPt, err := crypto.NewECPoint(tss.Edwards(), edPK.X, edPK.Y)
+		recoveredPK, err := edwards.ParsePubKey(recovered)
 		if err != nil {
-			welp = err
-			return
+			return false
 		}
-		if !edPKPt.Equals(share0EDDSAPubKey) {
-			welp = fmt.Errorf("⚠ recovered EdDSA public key did not match the expected share 0 public key! did you input the right threshold?")
-			return
+		var x [32]byte
+		recoveredPK.X.FillBytes(x[:])
+		return expected[0] == 0x02|byte(recoveredPK.Y.Bit(0)) && bytes.Equal(expected[1:], x[:])
+	}
+	// ECDSA keys may be recorded compressed or uncompressed
+	expectedPK, err := secp256k1.ParsePubKey(expected)
+	if err != nil {
+		return false
+	}
+	recoveredPK, err := secp256k1.ParsePubKey(recovered)
+	return err == nil && expectedPK.IsEqual(recoveredPK)
+}
+
+// encryptKeystore creates the wallet v3 JSON for the ECDSA key, with the -label watermark if one was given.
+func encryptKeystore(ecdsaSK []byte, address, password string, kdf walletv3.KDFParams, label *string) ([]byte, error) {
+	keyfile, err := walletv3.Encrypt(ecdsaSK, address, password, kdf)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ could not create the wallet v3 file json: %v", err)
+	}
+	if label != nil && len(*label) > 0 {
+		return labelKeystoreJSON(keyfile, *label)
+	}
+	return keyfile, nil
+}
+
+// loadSavedData parses the backup file JSON, from the file's content if it was already loaded, e.g. from QR code images.
+func loadSavedData(file ui.VaultsDataFile) (*SavedData, error) {
+	content := file.Content
+	if content == nil {
+		var err error
+		if content, err = os.ReadFile(file.File); err != nil {
+			return nil, fmt.Errorf("⚠ file to read from file(%s): %s", file.File, err)
 		}
 	}
+	// backups shared from the mobile app are wrapped in an envelope
+	content, _, err := data.UnwrapBackup(content)
+	if err != nil {
+		return nil, fmt.Errorf("⚠ %s: %s", file.File, strings.TrimPrefix(err.Error(), "⚠ "))
+	}
+	if err = schema.Validate(content, savedDataSchema); err != nil {
+		return nil, fmt.Errorf("⚠ %s is not a valid backup file: %s", file.File, err)
+	}
+	saveData := new(SavedData)
+	if err := json.Unmarshal(content, saveData); err != nil {
+		return nil, errors2.Wrapf(err, "⚠ invalid saveData format - is this an old backup file? (code: 1)")
+	}
+	if err := backupCompatibility.Check(file.File, saveData); err != nil {
+		return nil, err
+	}
+	return saveData, nil
+}
 
-	// encode Ethereum address for human sanity check
-	if _, address, welp = getTSSPubKeyForEthereum(pk.X(), pk.Y()); welp != nil {
-		return
+// checkMnemonics catches a wrong phrase while it is entered. Newer backup files are checked against their mnemonic check
+// ciphertext; older ones by decrypting the latest reshare of each vault. It returns the number of vaults found in the file.
+func checkMnemonics(file ui.VaultsDataFile) (int, error) {
+	saveData, err := loadSavedData(file)
+	if err != nil {
+		return 0, err
 	}
+	aesKey32, err := bip39.EntropyFromMnemonic(file.Mnemonics)
+	if err != nil {
+		return 0, fmt.Errorf("⚠ failed to generate key from mnemonic, are your words correct? %s", err)
+	}
+	defer clear(aesKey32)
 
-	// write out keystore file
-	if exportKSFile != nil && len(*exportKSFile) > 0 {
-		if passwordForKS == nil || len(*passwordForKS) == 0 {
-			fmt.Printf("NOTE: -password flag is required to export wallet v3 file `%s`. A wallet v3 file will not be created this time.\n\n", *exportKSFile)
-			return
+	if saveData.MnemonicCheck != nil {
+		if !openMnemonicCheck(aesKey32, saveData.MnemonicCheck) {
+			return 0, errors.New("⚠ the phrase does not belong to this file")
 		}
-		ksUuid, err2 := uuid.NewRandom()
-		if err2 != nil {
-			welp = fmt.Errorf("⚠ could not create random uuid: %v", err2)
-			return
+		return len(saveData.Vaults), nil
+	}
+
+	vaults := 0
+	for vID, resharesMap := range saveData.Vaults {
+		lastReshareNonce := -1
+		for nonce := range resharesMap {
+			lastReshareNonce = max(lastReshareNonce, nonce)
 		}
-		key := &keystore.Key{
-			Id:         ksUuid,
-			Address:    common.HexToAddress(address),
-			PrivateKey: privKey.ToECDSA(),
+		if lastReshareNonce == -1 {
+			continue
 		}
-		keyfile, err2 := keystore.EncryptKey(key, *passwordForKS, keystore.StandardScryptN, keystore.StandardScryptP)
-		if err2 != nil {
-			welp = fmt.Errorf("⚠ could not create the wallet v3 file json: %v", err2)
-			return
+		cipheredVault := resharesMap[lastReshareNonce]
+		plainload, err := crypto2.OpenEnvelope(aesKey32, crypto2.Envelope{
+			CipherTextB64: cipheredVault.CipherTextB64,
+			IV:            cipheredVault.CipherParams.IV,
+			Tag:           cipheredVault.CipherParams.Tag,
+			Hash:          cipheredVault.Hash,
+		})
+		if err != nil {
+			return 0, errors2.Errorf("⚠ failed to decrypt vault %s: %s", vID, err)
 		}
+		clear(plainload)
+		vaults++
+	}
+	return vaults, nil
+}
 
-		if welp = os.WriteFile(*exportKSFile, keyfile, os.ModePerm); welp != nil {
-			return
+// matchMnemonicCheck tells whether a phrase belongs to a backup file by its mnemonic check ciphertext, to pair phrases
+// entered for the wrong file. It is false for older files without one.
+func matchMnemonicCheck(file ui.VaultsDataFile) bool {
+	saveData, err := loadSavedData(file)
+	if err != nil || saveData.MnemonicCheck == nil {
+		return false
+	}
+	aesKey32, err := bip39.EntropyFromMnemonic(file.Mnemonics)
+	if err != nil {
+		return false
+	}
+	defer clear(aesKey32)
+	return openMnemonicCheck(aesKey32, saveData.MnemonicCheck)
+}
+
+// openMnemonicCheck decrypts the mnemonic check ciphertext. The GCM tag and the hash of the plaintext are compared in
+// constant time, and the plaintext itself carries no meaning.
+func openMnemonicCheck(aesKey32 []byte, check *CipheredVault) bool {
+	plainload, err := crypto2.OpenEnvelope(aesKey32, crypto2.Envelope{
+		CipherTextB64: check.CipherTextB64,
+		IV:            check.CipherParams.IV,
+		Tag:           check.CipherParams.Tag,
+		Hash:          check.Hash,
+	})
+	clear(plainload)
+	return err == nil
+}
+
+// exportTSSShareBundles writes one JSON bundle per party containing its full ECDSA (and, if present, EdDSA) save data.
+// Shares of both curves are appended in file order, so the share at index i of each list belongs to the same party.
+func exportTSSShareBundles(dir, vID string, vault *ClearVault, tPlus1 int,
+	sharesECDSA []*ecdsa_keygen.LocalPartySaveData, sharesEDDSA []*eddsa_keygen.LocalPartySaveData, label *string) ([]string, error) {
+	if len(sharesEDDSA) > 0 && len(sharesEDDSA) != len(sharesECDSA) {
+		return nil, fmt.Errorf("⚠ count of EDDSA shares %d != count of ECDSA shares %d for vault `%s`", len(sharesEDDSA), len(sharesECDSA), vID)
+	}
+	if err := strictWrites.Check(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("⚠ could not create the TSS share export directory `%s`: %v", dir, err)
+	}
+	written := make([]string, 0, len(sharesECDSA))
+	for i, shareECDSA := range sharesECDSA {
+		bundle := TSSShareBundle{
+			VaultID:      vID,
+			VaultName:    vault.Name,
+			Threshold:    tPlus1 - 1,
+			ReShareNonce: vault.LastReShareNonce,
+			ShareID:      shareECDSA.ShareID.String(),
+			ECDSA:        shareECDSA,
+		}
+		if len(sharesEDDSA) > 0 {
+			bundle.EdDSA = sharesEDDSA[i]
+		}
+		if label != nil {
+			bundle.Label = *label
+		}
+		bz, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("⚠ could not encode the TSS share bundle for party %d: %v", i+1, err)
+		}
+		filename := filepath.Join(dir, ui.SessionFilename(fmt.Sprintf("%s-party-%d.json", vID, i+1)))
+		if err = strictWrites.Check(filename); err != nil {
+			return nil, err
+		}
+		if err = os.WriteFile(filename, bz, 0600); err != nil {
+			return nil, fmt.Errorf("⚠ could not write the TSS share bundle `%s`: %v", filename, err)
 		}
-		fmt.Printf("\nWrote a MetaMask wallet v3 (for ECDSA key only) to: %s.\n\n", *exportKSFile)
+		written = append(written, filename)
 	}
-	return address, ecdsaSK, eddsaSK, orderedVaults, nil
+	return written, nil
 }
 
-func inflateSharesForCurve[T SaveData](shares []string, justListingVaults bool) ([]*T, error) {
+// inflateSharesForCurve parses the shares of a vault, inflating the compressed V2 ones. Their sizes are reported to
+// stats, unless it is nil.
+func inflateSharesForCurve[T SaveData](shares []string, vaultID string, stats ShareStats) ([]*T, error) {
 	shareDatas := make([]*T, len(shares))
 	for j, strShare := range shares {
+		if err := backupCompatibility.CheckShare(strShare); err != nil {
+			return nil, err
+		}
 		// handle compressed "V2" format (ECDSA)
 		hadPrefix := strings.HasPrefix(strShare, v2MagicPrefix)
 		if hadPrefix {
@@ -389,10 +706,8 @@ func inflateSharesForCurve[T SaveData](shares []string, justListingVaults bool)
 			}
 			strShare = string(inflated)
 
-			// log deflated vs inflated sizes in KB
-			if !justListingVaults {
-				fmt.Printf("Processing V2 share %s.\t %.1f KB → %.1f KB\n",
-					abridgedData.ShareID, float64(len(deflated))/1024, float64(len(inflated))/1024)
+			if stats != nil {
+				stats.Inflated(vaultID, abridgedData.ShareID.String(), len(deflated), len(inflated))
 			}
 		}
 		// proceed with regular json unmarshal
@@ -407,10 +722,15 @@ func inflateSharesForCurve[T SaveData](shares []string, justListingVaults bool)
 }
 
 func getTSSPubKeyForEthereum(x, y *big.Int) (*secp256k1.PublicKey, string, error) {
-	if x == nil || y == nil {
+	if x == nil || y == nil || x.BitLen() > 256 || y.BitLen() > 256 {
 		return nil, "", errors.New("invalid public key coordinates")
 	}
-	pubKey, err := secp256k1.ParsePubKey(append([]byte{0x04}, append(x.Bytes(), y.Bytes()...)...))
+	// the coordinates are padded to 32 bytes, as big.Int drops leading zero bytes
+	var uncompressed [65]byte
+	uncompressed[0] = 0x04
+	x.FillBytes(uncompressed[1:33])
+	y.FillBytes(uncompressed[33:])
+	pubKey, err := secp256k1.ParsePubKey(uncompressed[:])
 	if err != nil {
 		return nil, "", err
 	}
@@ -6,11 +6,19 @@ package main
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/walletv3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Test fixture mnemonics. Used only for this purpose.
@@ -37,7 +45,7 @@ func TestTool_New_V2_List(t *testing.T) {
 	}
 
 	// use the correct file path for tests
-	address, ecSK, edSK, vaultFormData, err := runTool(files, nil, nil, nil, nil, nil)
+	address, ecSK, edSK, vaultFormData, err := runTool(files, ToolOptions{})
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -83,7 +91,7 @@ func TestTool_New_V2_Export_lqns(t *testing.T) {
 		{File: "./test-files/new_u44.json", Mnemonics: mmNewU44},
 	}
 
-	address, ecSK, edSK, vaultsFormData, err := runTool(files, &vaultID, nil, nil, nil, nil)
+	address, ecSK, edSK, vaultsFormData, err := runTool(files, ToolOptions{VaultID: vaultID})
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -111,7 +119,7 @@ func TestTool_NewSingle_V2_List(t *testing.T) {
 		{File: "./test-files/new_single.json", Mnemonics: mmNewSingle},
 	}
 	// use the correct file path for tests
-	address, _, edSK, vaultFormData, err := runTool(files, nil, nil, nil, nil, nil)
+	address, _, edSK, vaultFormData, err := runTool(files, ToolOptions{})
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -135,12 +143,60 @@ func TestTool_NewSingle_V2_List_BadMnemonic(t *testing.T) {
 		{File: "./test-files/new_single.json", Mnemonics: mmV2},
 	}
 	// use the correct file path for tests
-	_, _, _, _, err := runTool(files, nil, nil, nil, nil, nil)
+	_, _, _, _, err := runTool(files, ToolOptions{})
 	if !assert.Error(t, err) {
 		return
 	}
 }
 
+func TestTool_CheckMnemonics(t *testing.T) {
+	vaults, err := checkMnemonics(ui.VaultsDataFile{File: "./test-files/new_single.json", Mnemonics: mmNewSingle})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 1, vaults)
+
+	_, err = checkMnemonics(ui.VaultsDataFile{File: "./test-files/new_single.json", Mnemonics: mmV2})
+	assert.Error(t, err)
+}
+
+func TestTool_CheckMnemonics_MnemonicCheck(t *testing.T) {
+	manifest, err := generateFixtures(fixtureOptions{OutDir: t.TempDir(), Vaults: 2, Parties: 2, Threshold: 2})
+	require.NoError(t, err)
+	first, second := manifest.Files[0], manifest.Files[1]
+
+	vaults, err := checkMnemonics(ui.VaultsDataFile{File: first.File, Mnemonics: first.Mnemonics})
+	require.NoError(t, err)
+	assert.Equal(t, 2, vaults)
+	_, err = checkMnemonics(ui.VaultsDataFile{File: first.File, Mnemonics: second.Mnemonics})
+	assert.ErrorContains(t, err, "does not belong to this file")
+
+	assert.True(t, matchMnemonicCheck(ui.VaultsDataFile{File: second.File, Mnemonics: second.Mnemonics}))
+	assert.False(t, matchMnemonicCheck(ui.VaultsDataFile{File: second.File, Mnemonics: first.Mnemonics}))
+	// older files without a mnemonic check are never matched quickly
+	assert.False(t, matchMnemonicCheck(ui.VaultsDataFile{File: "./test-files/new_single.json", Mnemonics: mmNewSingle}))
+}
+
+func TestTool_MobileEnvelope(t *testing.T) {
+	backup, err := os.ReadFile("./test-files/new_single.json")
+	require.NoError(t, err)
+	// as shared from the mobile app, with the backup under another key, next to metadata
+	wrapped, err := json.Marshal(map[string]any{"app": "io.vault mobile", "exportedAt": "2025-01-02T03:04:05Z", "signerData": json.RawMessage(backup)})
+	require.NoError(t, err)
+	file := ui.VaultsDataFile{File: "shared.json", Mnemonics: mmNewSingle, Content: wrapped}
+
+	vaults, err := checkMnemonics(file)
+	require.NoError(t, err)
+	assert.Equal(t, 1, vaults)
+	_, _, _, listed, err := runTool([]ui.VaultsDataFile{file}, ToolOptions{})
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+
+	file.Content = []byte(`{"first":` + string(backup) + `,"second":` + string(backup) + `}`)
+	_, err = checkMnemonics(file)
+	assert.ErrorContains(t, err, "shared.json: the mobile app envelope holds more than one backup")
+}
+
 func TestTool_NewSingle_V2_Export_qvl5(t *testing.T) {
 	// use the correct file path for tests
 	vaultID := "phrot42ltzawmn7nrm7mqvl5"
@@ -148,7 +204,7 @@ func TestTool_NewSingle_V2_Export_qvl5(t *testing.T) {
 	files := []ui.VaultsDataFile{
 		{File: "./test-files/new_single.json", Mnemonics: mmNewSingle},
 	}
-	_, ecSK, edSK, vaultsFormData, err := runTool(files, &vaultID, nil, nil, nil, nil)
+	_, ecSK, edSK, vaultsFormData, err := runTool(files, ToolOptions{VaultID: vaultID})
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -175,7 +231,7 @@ func TestTool_NewSingle_V2_Export_qvl5_BadMnemonic(t *testing.T) {
 	files := []ui.VaultsDataFile{
 		{File: "./test-files/new_single.json", Mnemonics: mmV2},
 	}
-	_, _, _, _, err := runTool(files, &vaultID, nil, nil, nil, nil)
+	_, _, _, _, err := runTool(files, ToolOptions{VaultID: vaultID})
 	if !assert.Error(t, err) {
 		return
 	}
@@ -187,7 +243,7 @@ func TestTool_Legacy_V2_List(t *testing.T) {
 	}
 
 	// use the correct file path for tests
-	address, ecSK, edSK, vaultsFormData, err := runTool(files, nil, nil, nil, nil, nil)
+	address, ecSK, edSK, vaultsFormData, err := runTool(files, ToolOptions{})
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -213,7 +269,7 @@ func TestTool_Legacy_V2_Export_c20x(t *testing.T) {
 		{File: "./test-files/v2.json", Mnemonics: mmV2},
 	}
 
-	address, ecSK, edSK, vaultsFormData, err := runTool(files, &vaultID, nil, nil, nil, nil)
+	address, ecSK, edSK, vaultsFormData, err := runTool(files, ToolOptions{VaultID: vaultID})
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -243,7 +299,7 @@ func TestTool_Legacy_V1_IL_List(t *testing.T) {
 		{File: "./test-files/l.json", Mnemonics: mmL},
 	}
 
-	address, ecSK, edSK, vaultsFormData, err := runTool(files, nil, nil, nil, nil, nil)
+	address, ecSK, edSK, vaultsFormData, err := runTool(files, ToolOptions{})
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -273,7 +329,7 @@ func TestTool_Legacy_V1_IL_Export_m0k(t *testing.T) {
 		{File: "./test-files/l.json", Mnemonics: mmL},
 	}
 
-	address, ecSK, edSK, vaultFormData, err := runTool(files, &vaultID, nil, nil, nil, nil)
+	address, ecSK, edSK, vaultFormData, err := runTool(files, ToolOptions{VaultID: vaultID})
 
 	if !assert.NoError(t, err) {
 		return
@@ -306,7 +362,7 @@ func TestTool_Legacy_V1_ILM_List(t *testing.T) {
 		{File: "./test-files/l.json", Mnemonics: mmL},
 	}
 
-	address, ecSK, edSK, vaultsFormData, err := runTool(files, nil, nil, nil, nil, nil)
+	address, ecSK, edSK, vaultsFormData, err := runTool(files, ToolOptions{})
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -337,7 +393,7 @@ func TestTool_Legacy_V1_ILM_Export_m0k(t *testing.T) {
 		{File: "./test-files/l.json", Mnemonics: mmL},
 	}
 
-	address, ecSK, edSK, vaultsFormData, err := runTool(files, &vaultID, nil, nil, nil, nil)
+	address, ecSK, edSK, vaultsFormData, err := runTool(files, ToolOptions{VaultID: vaultID})
 
 	if !assert.NoError(t, err) {
 		return
@@ -394,3 +450,182 @@ func TestLeftPadTo32Bytes(t *testing.T) {
 		})
 	}
 }
+
+func TestTool_New_V2_ExportTSSShare_lqns(t *testing.T) {
+	vaultID := "yz5x2a7zhwwt7r0lv4gklqns"
+	exportDir := t.TempDir()
+	label := "ticket-1234"
+
+	files := []ui.VaultsDataFile{
+		{File: "./test-files/new_bvn.json", Mnemonics: mmNewBvn},
+		{File: "./test-files/new_x2q.json", Mnemonics: mmNewX2q},
+		{File: "./test-files/new_u44.json", Mnemonics: mmNewU44},
+	}
+
+	_, _, _, vaultsFormData, err := runTool(files, ToolOptions{VaultID: vaultID, ExportTSSShareDir: exportDir, ExportLabel: label})
+	if !assert.NoError(t, err) || !assert.Len(t, vaultsFormData, 1) {
+		return
+	}
+	bundleFiles, err := filepath.Glob(filepath.Join(exportDir, vaultID+"-party-*.json"))
+	if !assert.NoError(t, err) || !assert.Len(t, bundleFiles, 3) {
+		return
+	}
+	for _, bundleFile := range bundleFiles {
+		content, err := os.ReadFile(bundleFile)
+		if !assert.NoError(t, err) {
+			return
+		}
+		bundle := new(TSSShareBundle)
+		if !assert.NoError(t, json.Unmarshal(content, bundle)) {
+			return
+		}
+		if !assert.Equal(t, vaultID, bundle.VaultID) ||
+			!assert.Equal(t, vaultsFormData[0].Quorum-1, bundle.Threshold, "the tss-lib threshold t is one less than the quorum") ||
+			!assert.Equal(t, label, bundle.Label) ||
+			!assert.Equal(t, bundle.ShareID, bundle.ECDSA.ShareID.String()) ||
+			!assert.True(t, bundle.ECDSA.LocalPreParams.Validate(), "paillier and ntilde material must be present") ||
+			!assert.NotNil(t, bundle.EdDSA) {
+			return
+		}
+	}
+}
+
+func TestTool_New_V2_ExportKeystore_lqns(t *testing.T) {
+	vaultID := "yz5x2a7zhwwt7r0lv4gklqns"
+	ksFile := filepath.Join(t.TempDir(), "wallet.json")
+	password := "password"
+
+	files := []ui.VaultsDataFile{
+		{File: "./test-files/new_bvn.json", Mnemonics: mmNewBvn},
+		{File: "./test-files/new_x2q.json", Mnemonics: mmNewX2q},
+		{File: "./test-files/new_u44.json", Mnemonics: mmNewU44},
+	}
+
+	kdf := walletv3.KDFParams{N: 1 << 14, R: 8, P: 2}
+	address, ecSK, edSK, _, err := runTool(files, ToolOptions{VaultID: vaultID, ExportKSFile: ksFile, PasswordForKS: password, KSKDF: kdf})
+	if !assert.NoError(t, err) || !assert.NotNil(t, edSK) {
+		return
+	}
+	keyfile, err := os.ReadFile(ksFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(keyfile), `"n":16384,"p":2,"r":8`)
+	key, err := keystore.DecryptKey(keyfile, password)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, ecSK, leftPadTo32Bytes(key.PrivateKey.D))
+	assert.Equal(t, strings.ToLower(address), strings.ToLower(key.Address.Hex()))
+}
+
+func TestRotate_GenerateRotationKeys(t *testing.T) {
+	address, ecSK, edSK, edPK, err := generateRotationKeys(true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, ecSK, 32) || !assert.Len(t, edSK, 32) || !assert.Len(t, edPK, 32) {
+		return
+	}
+	// the address must belong to the generated key
+	scl := secp256k1.ModNScalar{}
+	scl.SetByteSlice(ecSK)
+	pk := secp256k1.NewPrivateKey(&scl).PubKey()
+	_, expAddress, err := getTSSPubKeyForEthereum(pk.X(), pk.Y())
+	if !assert.NoError(t, err) || !assert.Equal(t, expAddress, address) {
+		return
+	}
+	// keys must be fresh on every call
+	address2, _, edSK2, _, err := generateRotationKeys(false)
+	if !assert.NoError(t, err) || !assert.NotEqual(t, address, address2) || !assert.Nil(t, edSK2) {
+		return
+	}
+}
+
+func TestTool_NewSingle_V2_Export_qvl5_FromContent(t *testing.T) {
+	vaultID := "phrot42ltzawmn7nrm7mqvl5"
+	content, err := os.ReadFile("./test-files/new_single.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the file name is only a label when the content has been decoded already, e.g. from QR codes
+	files := []ui.VaultsDataFile{
+		{File: "./scans/new_single", Mnemonics: mmNewSingle, Content: content},
+	}
+	_, ecSK, _, _, err := runTool(files, ToolOptions{VaultID: vaultID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "0a8376f6cb75d7e4197d35d2f7254f60f08827d5604589ea57843c3f754983b7", hex.EncodeToString(ecSK))
+}
+
+func TestTool_GetTSSPubKeyForEthereum_ShortCoordinate(t *testing.T) {
+	// find a key with a leading zero byte in X, which big.Int drops
+	var pk *secp256k1.PublicKey
+	for k := uint32(1); pk == nil; k++ {
+		scl := secp256k1.ModNScalar{}
+		scl.SetInt(k)
+		if candidate := secp256k1.NewPrivateKey(&scl).PubKey(); candidate.X().BitLen() <= 248 {
+			pk = candidate
+		}
+	}
+	parsed, _, err := getTSSPubKeyForEthereum(pk.X(), pk.Y())
+	if assert.NoError(t, err) {
+		assert.True(t, pk.IsEqual(parsed))
+	}
+
+	_, _, err = getTSSPubKeyForEthereum(new(big.Int).Lsh(big.NewInt(1), 256), pk.Y())
+	assert.Error(t, err)
+}
+
+func TestTool_VerifyMetadataPublicKey(t *testing.T) {
+	pubKey := func(k uint32) *secp256k1.PublicKey {
+		scl := secp256k1.ModNScalar{}
+		scl.SetInt(k)
+		return secp256k1.NewPrivateKey(&scl).PubKey()
+	}
+	recovered := pubKey(1).SerializeUncompressed()
+
+	assert.NoError(t, verifyMetadataPublicKey("ECDSA", nil, recovered, false), "no public key in the metadata")
+	assert.NoError(t, verifyMetadataPublicKey("ECDSA", []string{
+		hex.EncodeToString(pubKey(1).SerializeUncompressed()),
+		hex.EncodeToString(pubKey(1).SerializeCompressed()),
+	}, recovered, false))
+
+	other := []string{hex.EncodeToString(pubKey(2).SerializeUncompressed())}
+	assert.ErrorContains(t, verifyMetadataPublicKey("ECDSA", other, recovered, false), "-force")
+	assert.NoError(t, verifyMetadataPublicKey("ECDSA", other, recovered, true))
+	assert.Error(t, verifyMetadataPublicKey("ECDSA", []string{"not hex"}, recovered, false))
+
+	// the EdDSA key of vault lqns, recorded in both encodings
+	edPK, _ := hex.DecodeString("23cd2271b6c5f036a8405b4afd03fbfd3cb3707ebb978f28430291742e9e96f7")
+	assert.NoError(t, verifyMetadataPublicKey("EDDSA", []string{
+		"23cd2271b6c5f036a8405b4afd03fbfd3cb3707ebb978f28430291742e9e96f7",
+		"030b353c1f2e9527e0f2bb971f273618bbb8e4beae7349c1907a56f03640af4b31",
+	}, edPK, false))
+	assert.Error(t, verifyMetadataPublicKey("EDDSA", []string{"020b353c1f2e9527e0f2bb971f273618bbb8e4beae7349c1907a56f03640af4b31"}, edPK, false))
+}
+
+func TestTool_New_V2_List_LowMemory(t *testing.T) {
+	files := []ui.VaultsDataFile{
+		{File: "./test-files/new_bvn.json", Mnemonics: mmNewBvn},
+		{File: "./test-files/new_x2q.json", Mnemonics: mmNewX2q},
+		{File: "./test-files/new_u44.json", Mnemonics: mmNewU44},
+	}
+	_, _, _, expected, err := runTool(files, ToolOptions{})
+	require.NoError(t, err)
+
+	lowMemory = true
+	t.Cleanup(func() { lowMemory = false })
+	_, _, _, listed, err := runTool(files, ToolOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, listed)
+
+	// the shares are parsed again when a vault is recovered
+	vaultID := "yz5x2a7zhwwt7r0lv4gklqns"
+	address, ecSK, _, _, err := runTool(files, ToolOptions{VaultID: vaultID})
+	require.NoError(t, err)
+	assert.NotEmpty(t, address)
+	assert.Len(t, ecSK, 32)
+}
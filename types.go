@@ -11,7 +11,14 @@ import (
 
 type (
 	SavedData struct {
-		Vaults map[string]CipheredVaultMap `json:"vaults"`
+		// Timestamp is when the backup file was created, in RFC 3339 format
+		Timestamp string                      `json:"timestamp,omitempty"`
+		Vaults    map[string]CipheredVaultMap `json:"vaults"`
+		// KDF names the key derivation of the phrase, in newer exports. Only its name is checked, for compatibility
+		KDF string `json:"kdf,omitempty"`
+		// MnemonicCheck is a small ciphertext under the same key as the vaults, in newer exports, to verify a phrase
+		// without decrypting any vault
+		MnemonicCheck *CipheredVault `json:"mnemonicCheck,omitempty"`
 	}
 
 	CipheredVaultMap map[int]CipheredVault
@@ -29,7 +36,10 @@ type (
 
 	ClearVaultMap   map[string]*ClearVault
 	ClearVaultCurve struct {
-		Algorithm string   `json:"algorithm"`
+		Algorithm string `json:"algorithm"`
+		Curve     string `json:"curve,omitempty"`
+		// PublicKey is the hex public key of the vault recorded at keygen, if the backup has it
+		PublicKey string   `json:"publicKey,omitempty"`
 		Shares    []string `json:"shares"`
 	}
 	ClearVault struct {
@@ -40,6 +50,20 @@ type (
 		Curves           []ClearVaultCurve `json:"curves"`
 	}
 
+	// TSSShareBundle is the re-importable save data of a single party, as written by -export-tss-share.
+	TSSShareBundle struct {
+		VaultID   string `json:"vaultId"`
+		VaultName string `json:"vaultName"`
+		// Threshold is the tss-lib threshold t of the vault: t+1 parties, its quorum, are needed to sign
+		Threshold    int                              `json:"threshold"`
+		ReShareNonce int                              `json:"reshareNonce"`
+		ShareID      string                           `json:"shareId"`
+		ECDSA        *ecdsa_keygen.LocalPartySaveData `json:"ecdsa"`
+		EdDSA        *eddsa_keygen.LocalPartySaveData `json:"eddsa,omitempty"`
+		// Label is the -label watermark of the session that exported the bundle
+		Label string `json:"label,omitempty"`
+	}
+
 	VaultAllSharesECDSA map[string][]*ecdsa_keygen.LocalPartySaveData
 	VaultAllSharesEdDSA map[string][]*eddsa_keygen.LocalPartySaveData
 
@@ -0,0 +1,53 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// UnsupportedChain is a chain that vault keys cannot control, with a way to recognize its addresses.
+type UnsupportedChain struct {
+	Name string
+	// Reason completes "vault keys cannot control <Name> because …"
+	Reason  string
+	Address *regexp.Regexp
+}
+
+// unsupportedChains are recognized so that the tool explains why they are out of reach, instead of reporting a plain
+// mismatch or suggesting keys that control nothing on them.
+var unsupportedChains = []UnsupportedChain{
+	{
+		Name:    "Monero",
+		Reason:  "Monero accounts are a pair of spend and view keys on Ed25519, and addresses are derived from both; a single vault key is neither",
+		Address: regexp.MustCompile(`^[48][1-9A-HJ-NP-Za-km-z]{94}$`),
+	},
+	{
+		Name:    "Zcash shielded",
+		Reason:  "shielded (Sapling) addresses use Jubjub spending keys rather than secp256k1, so only transparent t1 addresses can be recovered",
+		Address: regexp.MustCompile(`^zs1[02-9ac-hj-np-z]{75}$`),
+	},
+	{
+		Name:    "Cardano",
+		Reason:  "Cardano addresses use BIP32-Ed25519 extended keys with a chain code and a staking key, which vault keys do not have",
+		Address: regexp.MustCompile(`^addr1[02-9ac-hj-np-z]{50,}$`),
+	},
+	{
+		Name:    "Polkadot",
+		Reason:  "Polkadot wallets use sr25519 or derive ed25519 keys from a seed phrase, and cannot import a raw vault key",
+		Address: regexp.MustCompile(`^1[1-9A-HJ-NP-Za-km-z]{46,47}$`),
+	},
+}
+
+// unsupportedChainVerdict explains why an address of an unsupported chain cannot belong to the vault.
+func unsupportedChainVerdict(pasted string) (string, bool) {
+	for _, chain := range unsupportedChains {
+		if chain.Address.MatchString(pasted) {
+			return fmt.Sprintf("⚠ It looks like a %s address. Unsupported: vault keys cannot control %s because %s.", chain.Name, chain.Name, chain.Reason), true
+		}
+	}
+	return "", false
+}
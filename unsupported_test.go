@@ -0,0 +1,36 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsupportedChains(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	addresses := vaultAddresses(ecdsaSK, make([]byte, 32))
+
+	tests := map[string]string{
+		"44AFFq5kSiGBoZ4NMDwYtN18obc8AemS33DBLWs3H7otXft3XjrpDtQGv7SqSsaBYBb98uNbr2VBBEt7f2wfn3RVGQBEP3A":         "Monero",
+		"zs1z7rejlpsa98s2rrrfkwmaxu53e4ue0ulcrw0h4x5g8jl04tak0d3mm47vdtahatqrlkngh9slya":                          "Zcash shielded",
+		"addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x": "Cardano",
+		"15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5":                                                        "Polkadot",
+	}
+	for pasted, chain := range tests {
+		verdict, ok := checkPastedAddress(pasted, addresses)
+		assert.False(t, ok, chain)
+		assert.Contains(t, verdict, "Unsupported: vault keys cannot control "+chain+" because", chain)
+	}
+
+	// supported chains and plain mismatches are not reported as unsupported
+	verdict, _ := checkPastedAddress("0x0000000000000000000000000000000000000001", addresses)
+	assert.NotContains(t, verdict, "Unsupported")
+	verdict, ok := checkPastedAddress("t1UYsZVJkLPeMjxEtACvSxfWuNmddpWfxzs", addresses)
+	assert.True(t, ok)
+	assert.NotContains(t, verdict, "Unsupported")
+}
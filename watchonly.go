@@ -0,0 +1,82 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/address"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/descriptor"
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+)
+
+// electrumImportedSeedVersion is the seed version of Electrum "imported addresses" wallet files. Electrum upgrades
+// older wallet files to its current version when it opens them.
+const electrumImportedSeedVersion = 18
+
+// ElectrumWatchOnlyWallet is an Electrum wallet file of imported addresses, without keys: it can only watch them.
+type ElectrumWatchOnlyWallet struct {
+	Addresses     map[string]struct{} `json:"addresses"`
+	SeedVersion   int                 `json:"seed_version"`
+	UseEncryption bool                `json:"use_encryption"`
+	WalletType    string              `json:"wallet_type"`
+}
+
+// watchOnlyFilenames are the files written by -export-watch-only, tagged with the session ID: the Electrum wallets of
+// the mainnet and testnet addresses, and the output descriptor for other wallets.
+func watchOnlyFilenames(name string) (mainnetFile, testnetFile, descriptorFile string) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	return ui.SessionFilename(name), ui.SessionFilename(base + "-testnet" + ext), ui.SessionFilename(base + "-descriptor.txt")
+}
+
+// watchOnlyWallets makes the watch-only wallet files of the recovered ECDSA key's Bitcoin addresses. They hold only
+// public data.
+func watchOnlyWallets(vaultID string, ecdsaSK []byte) (mainnet, testnet, descriptorText []byte, err error) {
+	compressedPK, err := hex.DecodeString(ecdsaPublicKeyDetails(ecdsaSK).Compressed)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	desc, err := descriptor.WPKH(compressedPK)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	mainnetAddress, testnetAddress := address.BitcoinP2WPKH(compressedPK, false), address.BitcoinP2WPKH(compressedPK, true)
+	for _, wallet := range []struct {
+		address string
+		data    *[]byte
+	}{{mainnetAddress, &mainnet}, {testnetAddress, &testnet}} {
+		if *wallet.data, err = json.MarshalIndent(ElectrumWatchOnlyWallet{
+			Addresses:   map[string]struct{}{wallet.address: {}},
+			SeedVersion: electrumImportedSeedVersion,
+			WalletType:  "imported",
+		}, "", "    "); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	descriptorText = []byte(fmt.Sprintf("# Watch-only output descriptor of the ECDSA master key of vault %s (no derivation). No secrets.\n"+
+		"# Bitcoin mainnet address: %s\n# Bitcoin testnet address: %s\n%s\n", vaultID, mainnetAddress, testnetAddress, desc))
+	return mainnet, testnet, descriptorText, nil
+}
+
+// writeWatchOnlyWallets writes the watch-only wallet files. Existing files are never overwritten.
+func writeWatchOnlyWallets(name, vaultID string, ecdsaSK []byte) ([]string, error) {
+	mainnet, testnet, descriptorText, err := watchOnlyWallets(vaultID, ecdsaSK)
+	if err != nil {
+		return nil, err
+	}
+	mainnetFile, testnetFile, descriptorFile := watchOnlyFilenames(name)
+	files := []string{mainnetFile, testnetFile, descriptorFile}
+	for i, data := range [][]byte{mainnet, testnet, descriptorText} {
+		if err = writeNewFile(files[i], data); err != nil {
+			return nil, fmt.Errorf("⚠ failed to write the watch-only wallet: %w", err)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IoFinnet/io-vault-disaster-recovery-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchOnlyWallets(t *testing.T) {
+	ecdsaSK, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	dir := t.TempDir()
+	files, err := writeWatchOnlyWallets(filepath.Join(dir, "vault.json"), "vault-1", ecdsaSK)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "vault-"+ui.SessionID+".json"),
+		filepath.Join(dir, "vault-testnet-"+ui.SessionID+".json"),
+		filepath.Join(dir, "vault-descriptor-"+ui.SessionID+".txt"),
+	}, files)
+
+	for i, expected := range []string{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"} {
+		data, err := os.ReadFile(files[i])
+		require.NoError(t, err)
+		var wallet ElectrumWatchOnlyWallet
+		require.NoError(t, json.Unmarshal(data, &wallet))
+		assert.Equal(t, ElectrumWatchOnlyWallet{Addresses: map[string]struct{}{expected: {}}, SeedVersion: electrumImportedSeedVersion,
+			WalletType: "imported"}, wallet)
+	}
+
+	data, err := os.ReadFile(files[2])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "vault-1")
+	assert.Contains(t, string(data), "\nwpkh(0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798)#ucxz0gak\n")
+	assert.NotContains(t, string(data), "0000000000000000000000000000000000000000000000000000000000000001")
+
+	// existing files are never overwritten
+	_, err = writeWatchOnlyWallets(filepath.Join(dir, "vault.json"), "vault-1", ecdsaSK)
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode"
+)
+
+const (
+	// keystoreLabelField is the wallet v3 JSON field holding the -label watermark. Wallets ignore unknown fields.
+	keystoreLabelField = "x-io-recovery-label"
+
+	maxExportLabelLen = 128
+)
+
+// validateExportLabel ensures that a label is short, printable text, so that it cannot break the files it is embedded in.
+func validateExportLabel(label string) error {
+	if len(label) > maxExportLabelLen {
+		return fmt.Errorf("⚠ the label must be at most %d characters", maxExportLabelLen)
+	}
+	for _, r := range label {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("⚠ the label must only contain printable characters")
+		}
+	}
+	return nil
+}
+
+// labelKeystoreJSON adds the label to a wallet v3 JSON as an extra top-level field.
+// The other fields are copied byte for byte, so the encrypted key and its parameters are never altered.
+func labelKeystoreJSON(keyfile []byte, label string) ([]byte, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(keyfile, &fields); err != nil {
+		return nil, fmt.Errorf("⚠ could not read the wallet v3 file json: %v", err)
+	}
+	labelJSON, err := json.Marshal(label)
+	if err != nil {
+		return nil, err
+	}
+	fields[keystoreLabelField] = labelJSON
+	return json.Marshal(fields)
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2021 io finnet group, inc.
+// SPDX-License-Identifier: AGPL-3.0-or-later
+// Full license text available in LICENSE file in repository root.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatermark_LabelKeystoreJSON(t *testing.T) {
+	privKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(*privKey.PubKey().ToECDSA()),
+		PrivateKey: privKey.ToECDSA(),
+	}
+	keyfile, err := keystore.EncryptKey(key, "password", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	labelled, err := labelKeystoreJSON(keyfile, "ticket-1234")
+	require.NoError(t, err)
+
+	var before, after map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(keyfile, &before))
+	require.NoError(t, json.Unmarshal(labelled, &after))
+	assert.JSONEq(t, `"ticket-1234"`, string(after[keystoreLabelField]))
+	delete(after, keystoreLabelField)
+	assert.Equal(t, before, after, "the key fields must not change")
+
+	decrypted, err := keystore.DecryptKey(labelled, "password")
+	require.NoError(t, err)
+	assert.Equal(t, privKey.ToECDSA().D, decrypted.PrivateKey.D)
+}
+
+func TestWatermark_ValidateExportLabel(t *testing.T) {
+	assert.NoError(t, validateExportLabel(""))
+	assert.NoError(t, validateExportLabel("ticket-1234 / ops"))
+	assert.Error(t, validateExportLabel("line\nbreak"))
+	assert.Error(t, validateExportLabel(strings.Repeat("a", maxExportLabelLen+1)))
+}